@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ArgKind int
+
+const (
+	StringArg     ArgKind = iota // "Name"
+	IntArg                       // 12, -1 or 0x4000
+	SymbolArg                    // Myself or TRUE, an IDS value
+	ObjectSpecArg                // [PC] or [ENEMY.0.0.MAGE]
+	PointArg                     // [100.200]
+	CallArg                      // LastTalkedToBy(Myself), or an action
+)
+
+func (k ArgKind) String() string {
+	switch k {
+	case StringArg:
+		return "string"
+	case IntArg:
+		return "integer"
+	case SymbolArg:
+		return "symbol"
+	case ObjectSpecArg:
+		return "object specifier"
+	case PointArg:
+		return "point"
+	case CallArg:
+		return "function"
+	default:
+		return "unknown"
+	}
+}
+
+// Arg is an argument of a trigger or action call.
+type Arg struct {
+	Kind   ArgKind
+	Text   string   // as written, without quotes for strings and brackets
+	Int    int64    // value of IntArg
+	Fields []string // fields of ObjectSpecArg and PointArg
+	Call   *Call    // CallArg
+	Param  *Param   // parameter of the signature, once resolved
+}
+
+// Returns the coordinates of a point like [100.200].
+func (a *Arg) Point() (int64, int64, bool) {
+	if len(a.Fields) != 2 {
+		return 0, 0, false
+	}
+	x, errX := strconv.ParseInt(a.Fields[0], 10, 64)
+	y, errY := strconv.ParseInt(a.Fields[1], 10, 64)
+	return x, y, errX == nil && errY == nil
+}
+
+// Sets the value of an integer argument.
+func (a *Arg) SetInt(value int64) {
+	a.Kind, a.Int, a.Text = IntArg, value, strconv.FormatInt(value, 10)
+}
+
+func (a *Arg) String() string {
+	switch a.Kind {
+	case StringArg:
+		return `"` + a.Text + `"`
+	case ObjectSpecArg, PointArg:
+		return "[" + a.Text + "]"
+	case CallArg:
+		return a.Call.String()
+	default:
+		return a.Text
+	}
+}
+
+// Call is a trigger or action, like !Global("X","GLOBAL",1).
+type Call struct {
+	Negated   bool
+	Name      string
+	Args      []*Arg
+	Line      int
+	Signature *Signature // once resolved
+}
+
+func (c *Call) String() string {
+	var sb strings.Builder
+	if c.Negated {
+		sb.WriteByte('!')
+	}
+	sb.WriteString(c.Name)
+	sb.WriteByte('(')
+	for i, a := range c.Args {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(a.String())
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// Script is the trigger of a state or transition, or the action of a
+// transition: a list of calls.
+type Script struct {
+	Calls []*Call
+}
+
+// Formats the script with a call per line, as in DLG files. Triggers of an
+// OR(n) block are indented.
+func (s *Script) String() string {
+	var sb strings.Builder
+	orLeft := 0
+	for _, c := range s.Calls {
+		if orLeft > 0 {
+			sb.WriteString("  ")
+			orLeft--
+		}
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+		if strings.EqualFold(c.Name, "OR") && len(c.Args) == 1 && c.Args[0].Kind == IntArg {
+			orLeft = int(c.Args[0].Int)
+		}
+	}
+	return sb.String()
+}
+
+// Returns the calls with their nested calls, like the actions of
+// ActionOverride, in order.
+func (s *Script) All() []*Call {
+	var calls []*Call
+	var walk func(c *Call)
+	walk = func(c *Call) {
+		calls = append(calls, c)
+		for _, a := range c.Args {
+			if a.Kind == CallArg {
+				walk(a.Call)
+			}
+		}
+	}
+	for _, c := range s.Calls {
+		walk(c)
+	}
+	return calls
+}
+
+// Returns the arguments which are strrefs, like the one of
+// DisplayStringHead(Myself,12345). The script must be resolved.
+func (s *Script) Strrefs() []*Arg {
+	var strrefs []*Arg
+	for _, c := range s.All() {
+		for _, a := range c.Args {
+			if a.Kind == IntArg && a.Param != nil && a.Param.IsStrref() {
+				strrefs = append(strrefs, a)
+			}
+		}
+	}
+	return strrefs
+}
+
+// Replaces the strrefs of the script, e.g. to move texts to another TLK
+// file. Returns the number of changed strrefs.
+func (s *Script) MapStrrefs(mapping func(strref uint32) (uint32, bool)) int {
+	changed := 0
+	for _, a := range s.Strrefs() {
+		if a.Int < 0 {
+			continue
+		}
+		if strref, ok := mapping(uint32(a.Int)); ok && int64(strref) != a.Int {
+			a.SetInt(int64(strref))
+			changed++
+		}
+	}
+	return changed
+}
+
+// SyntaxError is an error of a script that cannot be parsed.
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type scriptParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *scriptParser) errorf(format string, args ...any) error {
+	return &SyntaxError{Line: p.line, Message: fmt.Sprintf(format, args...)}
+}
+
+// Skips whitespace and // comments.
+func (p *scriptParser) skipSpace() {
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		switch {
+		case ch == '\n':
+			p.line++
+			p.pos++
+		case unicode.IsSpace(rune(ch)):
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *scriptParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func isNameChar(ch byte) bool {
+	return ch == '_' || ch >= 0x80 || unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch))
+}
+
+func (p *scriptParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// Parses a call: [!]Name(args).
+func (p *scriptParser) call() (*Call, error) {
+	c := &Call{}
+	if p.peek() == '!' {
+		c.Negated = true
+		p.pos++
+		p.skipSpace()
+	}
+	c.Line = p.line
+	if c.Name = p.name(); c.Name == "" {
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("expected a function name")
+		}
+		return nil, p.errorf("expected a function name, got %q", p.src[p.pos])
+	}
+	if p.peek() != '(' {
+		return nil, p.errorf("expected ( after %s", c.Name)
+	}
+	p.pos++
+
+	if p.peek() == ')' {
+		p.pos++
+		return c, nil
+	}
+	for {
+		arg, err := p.arg()
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, arg)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return c, nil
+		default:
+			return nil, p.errorf("expected , or ) in arguments of %s", c.Name)
+		}
+	}
+}
+
+func (p *scriptParser) arg() (*Arg, error) {
+	ch := p.peek()
+	switch {
+	case ch == '"' || ch == '~':
+		end := strings.IndexByte(p.src[p.pos+1:], ch)
+		if end < 0 {
+			return nil, p.errorf("unclosed string")
+		}
+		text := p.src[p.pos+1 : p.pos+1+end]
+		p.line += strings.Count(text, "\n")
+		p.pos += end + 2
+		return &Arg{Kind: StringArg, Text: text}, nil
+	case ch == '[':
+		end := strings.IndexByte(p.src[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("unclosed object specifier")
+		}
+		text := strings.TrimSpace(p.src[p.pos+1 : p.pos+end])
+		p.pos += end + 1
+		fields := strings.Split(text, ".")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		arg := &Arg{Kind: ObjectSpecArg, Text: text, Fields: fields}
+		if _, _, ok := arg.Point(); ok {
+			arg.Kind = PointArg
+		}
+		return arg, nil
+	case ch == '-' || (ch >= '0' && ch <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		text := p.src[start:p.pos]
+		value, err := strconv.ParseInt(text, 0, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %s", text)
+		}
+		return &Arg{Kind: IntArg, Text: text, Int: value}, nil
+	case ch == '!' || isNameChar(ch):
+		start, line := p.pos, p.line
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("unexpected !")
+		}
+		if p.peek() == '(' {
+			p.pos, p.line = start, line
+			call, err := p.call()
+			if err != nil {
+				return nil, err
+			}
+			return &Arg{Kind: CallArg, Text: call.Name, Call: call}, nil
+		}
+		return &Arg{Kind: SymbolArg, Text: name}, nil
+	case ch == 0:
+		return nil, p.errorf("unexpected end of script")
+	}
+	return nil, p.errorf("unexpected %q", ch)
+}
+
+// Parses a trigger or action of a DLG or BAF file: calls one after
+// another, like Global("X","GLOBAL",1) !InParty("Imoen").
+func Parse(src string) (*Script, error) {
+	p := &scriptParser{src: src, line: 1}
+	s := &Script{}
+	for p.peek() != 0 {
+		c, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		s.Calls = append(s.Calls, c)
+	}
+	return s, nil
+}
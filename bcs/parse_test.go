@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"trigger", "Global(\"X\",\"GLOBAL\",1)\n", "Global(\"X\",\"GLOBAL\",1)\n"},
+		{"negated and spaces", "  !InParty( \"Imoen\" )  NumTimesTalkedTo(0)", "!InParty(\"Imoen\")\nNumTimesTalkedTo(0)\n"},
+		{"or block", "OR(2) See([PC]) Range(LastTalkedToBy(Myself),10) True()", "OR(2)\n  See([PC])\n  Range(LastTalkedToBy(Myself),10)\nTrue()\n"},
+		{"action", "ActionOverride(\"Jaheira\",MoveToPoint([100.-20]))\r\nDisplayStringHead(Myself,0x10)", "ActionOverride(\"Jaheira\",MoveToPoint([100.-20]))\nDisplayStringHead(Myself,0x10)\n"},
+		{"object specifier", "See([ENEMY.0.0.MAGE])", "See([ENEMY.0.0.MAGE])\n"},
+		{"comment", "True() // always\n", "True()\n"},
+		{"empty", " \n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	s, err := Parse(`MoveToPoint([100.-20]) See([PC]) SetGlobal("X","GLOBAL",-1) Range(LastTalkedToBy(Myself),10)`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if a := s.Calls[0].Args[0]; a.Kind != PointArg {
+		t.Errorf("[100.-20] is %s, want point", a.Kind)
+	} else if x, y, _ := a.Point(); x != 100 || y != -20 {
+		t.Errorf("Point() = %d, %d, want 100, -20", x, y)
+	}
+	if a := s.Calls[1].Args[0]; a.Kind != ObjectSpecArg || a.Fields[0] != "PC" {
+		t.Errorf("[PC] = %+v", a)
+	}
+	if a := s.Calls[2].Args[2]; a.Kind != IntArg || a.Int != -1 {
+		t.Errorf("-1 = %+v", a)
+	}
+	if a := s.Calls[3].Args[0]; a.Kind != CallArg || a.Call.Args[0].Kind != SymbolArg {
+		t.Errorf("LastTalkedToBy(Myself) = %+v", a)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`Global("X","GLOBAL",1`, "line 1: expected , or ) in arguments of Global"},
+		{`Global("X",`, "line 1: unexpected end of script"},
+		{"True()\nGlobal(\"X,1)", "line 2: unclosed string"},
+		{"True", "line 1: expected ( after True"},
+		{"See([PC)", "line 1: unclosed object specifier"},
+		{"Global(,)", "line 1: unexpected ','"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
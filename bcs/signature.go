@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// ParamType is the type of a parameter in TRIGGER.IDS and ACTION.IDS.
+type ParamType byte
+
+const (
+	StringParam ParamType = 'S'
+	IntParam    ParamType = 'I'
+	ObjectParam ParamType = 'O'
+	PointParam  ParamType = 'P'
+	ActionParam ParamType = 'A'
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case StringParam:
+		return "string"
+	case IntParam:
+		return "integer"
+	case ObjectParam:
+		return "object"
+	case PointParam:
+		return "point"
+	case ActionParam:
+		return "action"
+	default:
+		return fmt.Sprintf("type %c", byte(t))
+	}
+}
+
+type Param struct {
+	Type ParamType
+	Name string
+	Ids  string // IDS file of the values of an integer, e.g. BOOLEAN
+}
+
+// Reports whether the parameter is a strref, like I:StrRef* of
+// DisplayStringHead or I:Entry* of AddJournalEntry.
+func (p Param) IsStrref() bool {
+	name := strings.ToLower(p.Name)
+	return p.Type == IntParam && (strings.Contains(name, "strref") || name == "entry")
+}
+
+// Signature is a trigger or an action of an IDS file, like
+// Global(S:Name*,S:Area*,I:Value*).
+type Signature struct {
+	Id     int32
+	Name   string
+	Params []Param
+}
+
+// Parses an entry of TRIGGER.IDS or ACTION.IDS.
+func ParseSignature(id int32, entry string) (*Signature, error) {
+	name, rest, ok := strings.Cut(strings.TrimSpace(entry), "(")
+	if !ok || !strings.HasSuffix(rest, ")") || strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("invalid signature %q", entry)
+	}
+	s := &Signature{Id: id, Name: strings.TrimSpace(name)}
+
+	rest = strings.TrimSpace(strings.TrimSuffix(rest, ")"))
+	if rest == "" {
+		return s, nil
+	}
+	for _, part := range strings.Split(rest, ",") {
+		typ, param, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || len(typ) != 1 {
+			return nil, fmt.Errorf("invalid parameter %q of %s", part, s.Name)
+		}
+		paramName, ids, _ := strings.Cut(param, "*")
+		s.Params = append(s.Params, Param{Type: ParamType(typ[0]), Name: paramName, Ids: strings.ToUpper(ids)})
+	}
+	return s, nil
+}
+
+func (s *Signature) String() string {
+	params := make([]string, 0, len(s.Params))
+	for _, p := range s.Params {
+		params = append(params, fmt.Sprintf("%c:%s*%s", byte(p.Type), p.Name, p.Ids))
+	}
+	return fmt.Sprintf("%s(%s)", s.Name, strings.Join(params, ","))
+}
+
+// Functions are the triggers or actions of an IDS file by their names.
+type Functions struct {
+	byName map[string][]*Signature
+}
+
+// Returns the functions of TRIGGER.IDS or ACTION.IDS. Entries which are
+// not signatures are skipped.
+func NewFunctions(ids *parser.Ids) *Functions {
+	f := &Functions{byName: make(map[string][]*Signature)}
+	for id, entry := range ids.Entries {
+		s, err := ParseSignature(id, entry)
+		if err != nil {
+			continue
+		}
+		key := strings.ToLower(s.Name)
+		f.byName[key] = append(f.byName[key], s)
+	}
+	for _, signatures := range f.byName {
+		slices.SortFunc(signatures, func(a, b *Signature) int { return cmp.Compare(a.Id, b.Id) })
+	}
+	return f
+}
+
+// Parses TRIGGER.IDS or ACTION.IDS and returns its functions.
+func ParseFunctions(r io.Reader) (*Functions, error) {
+	ids, err := parser.ParseIds(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewFunctions(ids), nil
+}
+
+// Returns the signatures of a function, case-insensitively; IDS files may
+// have several with different parameters. A nil Functions has none.
+func (f *Functions) Lookup(name string) []*Signature {
+	if f == nil {
+		return nil
+	}
+	return f.byName[strings.ToLower(name)]
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Problem is a call which does not match TRIGGER.IDS or ACTION.IDS.
+type Problem struct {
+	Line    int
+	Call    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Accepted kinds of arguments for each parameter type.
+var paramArgKinds = map[ParamType][]ArgKind{
+	StringParam: {StringArg},
+	IntParam:    {IntArg, SymbolArg},
+	ObjectParam: {SymbolArg, StringArg, ObjectSpecArg, PointArg, CallArg},
+	PointParam:  {PointArg},
+	ActionParam: {CallArg},
+}
+
+// Resolves the calls against the signatures of TRIGGER.IDS for triggers or
+// ACTION.IDS for actions, and sets the signatures and parameters of calls
+// and arguments. Actions in arguments, like the one of ActionOverride, are
+// resolved too; object functions like LastTalkedToBy(Myself) are not.
+// Returns the unknown functions, calls with a wrong number of arguments and
+// arguments of a wrong type. With nil funcs every function is unknown.
+func (s *Script) Resolve(funcs *Functions) []Problem {
+	var problems []Problem
+	for _, c := range s.Calls {
+		problems = append(problems, c.resolve(funcs)...)
+	}
+	return problems
+}
+
+func (c *Call) resolve(funcs *Functions) []Problem {
+	problem := func(format string, args ...any) []Problem {
+		return []Problem{{Line: c.Line, Call: c.Name, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	signatures := funcs.Lookup(c.Name)
+	if len(signatures) == 0 {
+		return problem("unknown function %s", c.Name)
+	}
+	i := slices.IndexFunc(signatures, func(s *Signature) bool { return len(s.Params) == len(c.Args) })
+	if i < 0 {
+		counts := make([]string, 0, len(signatures))
+		for _, s := range signatures {
+			counts = append(counts, strconv.Itoa(len(s.Params)))
+		}
+		return problem("%s takes %s arguments, got %d", signatures[0].Name, strings.Join(counts, " or "), len(c.Args))
+	}
+	c.Signature = signatures[i]
+
+	var problems []Problem
+	for n, a := range c.Args {
+		param := &c.Signature.Params[n]
+		a.Param = param
+		if !slices.Contains(paramArgKinds[param.Type], a.Kind) {
+			problems = append(problems, problem("argument %d (%s) of %s must be %s, got %s %s",
+				n+1, param.Name, c.Signature.Name, withArticle(param.Type.String()), a.Kind, a)...)
+			continue
+		}
+		switch {
+		case param.Type == ObjectParam && a.Kind == PointArg:
+			// [0.0] is an object specifier here.
+			a.Kind = ObjectSpecArg
+		case param.Type == ActionParam:
+			problems = append(problems, a.Call.resolve(funcs)...)
+		}
+	}
+	return problems
+}
+
+func withArticle(s string) string {
+	if strings.ContainsRune("aeiou", rune(s[0])) {
+		return "an " + s
+	}
+	return "a " + s
+}
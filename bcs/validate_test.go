@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package bcs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testFunctions(t *testing.T, content string) *Functions {
+	t.Helper()
+	funcs, err := ParseFunctions(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseFunctions() error = %v", err)
+	}
+	return funcs
+}
+
+const testActions = `IDS V1.0
+1 ActionOverride(O:Actor*,A:Action*)
+23 MoveToPoint(P:Point*)
+30 SetGlobal(S:Name*,S:Area*,I:Value*)
+269 DisplayStringHead(O:Object*,I:StrRef*)
+173 AddJournalEntry(I:Entry*,I:Type*JourType)
+`
+
+func TestResolve(t *testing.T) {
+	actions := testFunctions(t, testActions)
+
+	s, err := Parse(`SetGlobal("X","GLOBAL",TRUE)
+ActionOverride("Jaheira",DisplayStringHead(Myself,100))
+AddJournalEntry(200,QUEST)
+MoveToPoint([10.20])`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if problems := s.Resolve(actions); len(problems) > 0 {
+		t.Fatalf("Resolve() problems = %v", problems)
+	}
+	if s.Calls[0].Signature.Id != 30 || s.Calls[2].Args[1].Param.Ids != "JOURTYPE" {
+		t.Errorf("unresolved calls: %+v", s.Calls)
+	}
+
+	var strrefs []int64
+	for _, a := range s.Strrefs() {
+		strrefs = append(strrefs, a.Int)
+	}
+	if !reflect.DeepEqual(strrefs, []int64{100, 200}) {
+		t.Errorf("Strrefs() = %v, want [100 200]", strrefs)
+	}
+
+	changed := s.MapStrrefs(func(strref uint32) (uint32, bool) { return strref + 1000, strref == 100 })
+	want := "SetGlobal(\"X\",\"GLOBAL\",TRUE)\nActionOverride(\"Jaheira\",DisplayStringHead(Myself,1100))\nAddJournalEntry(200,QUEST)\nMoveToPoint([10.20])\n"
+	if changed != 1 || s.String() != want {
+		t.Errorf("MapStrrefs() = %d, script:\n%s", changed, s)
+	}
+}
+
+func TestResolveProblems(t *testing.T) {
+	actions := testFunctions(t, testActions)
+
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{`SetGlobl("X","GLOBAL",1)`, []string{"line 1: unknown function SetGlobl"}},
+		{`SetGlobal("X","GLOBAL")`, []string{"line 1: SetGlobal takes 3 arguments, got 2"}},
+		{"MoveToPoint([PC])\nSetGlobal(\"X\",GLOBAL,1)", []string{
+			"line 1: argument 1 (Point) of MoveToPoint must be a point, got object specifier [PC]",
+			"line 2: argument 2 (Area) of SetGlobal must be a string, got symbol GLOBAL",
+		}},
+		{`ActionOverride(Player1,Wait(1))`, []string{"line 1: unknown function Wait"}},
+	}
+	for _, tt := range tests {
+		s, err := Parse(tt.src)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.src, err)
+		}
+		var got []string
+		for _, p := range s.Resolve(actions) {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+
+	s, err := Parse(`SetGlobal("X","GLOBAL",1)`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if problems := s.Resolve(nil); len(problems) != 1 || problems[0].String() != "line 1: unknown function SetGlobal" {
+		t.Errorf("Resolve(nil) = %v", problems)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	s, err := ParseSignature(0x4023, "Global(S:Name*,S:Area*,I:Value*)")
+	if err != nil {
+		t.Fatalf("ParseSignature() error = %v", err)
+	}
+	if s.Name != "Global" || len(s.Params) != 3 || s.Params[1].Type != StringParam || s.Params[2].Name != "Value" {
+		t.Errorf("ParseSignature() = %+v", s)
+	}
+	if s.String() != "Global(S:Name*,S:Area*,I:Value*)" {
+		t.Errorf("String() = %s", s)
+	}
+	if _, err := ParseSignature(1, "NoParens"); err == nil {
+		t.Error("ParseSignature(NoParens) error = nil")
+	}
+}
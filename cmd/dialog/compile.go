@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewCompileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compile [dCanvas-file...]",
+		Short: "Compile edited dCanvas files back to a DLG file",
+		Long: `Compile dCanvas files made by 'dialog export' and edited, e.g. to fix a broken
+dialog flow, back into a DLG V1.0 file.
+
+The DLG file of the game is patched: states keep their indexes from
+x-nodeId, transitions are matched by x-nodeId, and new nodes (with
+x-nodeRole but without x-nodeId) are appended. The transition table is
+rewritten in the order of states. Transitions without text are edges straight between states,
+with the trigger in x-condition. Nodes without x-nodeRole are notes.
+
+Texts are resolved against the TLK file: unchanged texts keep their strrefs,
+and new texts are appended. Changed texts are appended too, or with
+--replace-text written to their strrefs. The patched TLK file is written
+next to the DLG file if any text was added or changed; if the game has
+dialogf.tlk, it is patched as well, so both files keep the same number of
+entries. With --feminine texts are resolved against dialogf.tlk and
+replaced texts are only changed in it.`,
+		Example: `  Fix a dialog of Jaheira and write JAHEIRA.DLG and dialog.tlk to override:
+
+      sbt-inf dialog compile -o override JAHEIRA-0.d.canvas
+
+  Compile two dialogs of the same file in Ukrainian, changing texts in place:
+
+      sbt-inf dialog compile -o override -l uk_UA --replace-text KHALID-0.d.canvas KHALID-3.d.canvas`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runCompile,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output directory")
+	cmd.Flags().String("dlg", "", "Name of the compiled DLG file (default: the DLG of the first state)")
+	cmd.Flags().String("tlk-output", "", "Path of the written dialog.tlk file, dialogf.tlk is written next to it (default: <output>/<TLK name>)")
+	cmd.Flags().Bool("replace-text", false, "Write changed texts to their strrefs instead of appending them")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagDirname("output")
+	cmd.MarkFlagFilename("tlk-output", "tlk")
+
+	return cmd
+}
+
+func runCompile(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	dlgName, _ := cmd.Flags().GetString("dlg")
+	tlkOutput, _ := cmd.Flags().GetString("tlk-output")
+	replaceText, _ := cmd.Flags().GetBool("replace-text")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	canvases := make([]*dcanvas.Canvas, 0, len(args))
+	for _, path := range args {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open dCanvas file %s: %v", path, err)
+		}
+		canvas, err := dcanvas.Decode(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", path, err)
+		}
+		canvases = append(canvases, canvas)
+	}
+
+	if dlgName == "" {
+		for _, n := range canvases[0].Nodes {
+			if n.NodeRole == "state" && n.NodeId != "" {
+				dlgName, _, _ = strings.Cut(n.NodeId, "[")
+				break
+			}
+		}
+		if dlgName == "" {
+			return fmt.Errorf("no states with x-nodeId in %s, set the DLG name with --dlg", args[0])
+		}
+	}
+	dlgName = strings.ToUpper(strings.TrimSuffix(strings.ToUpper(dlgName), ".DLG"))
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlk, err := readTlkEntries(cmd)
+	if err != nil {
+		return err
+	}
+
+	// The DLG file of the game, if there is one.
+	var base *dialog.DlgWriteFile
+	dlgFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG))
+	if _, err := dlgFs.Stat(dlgName + ".DLG"); err == nil {
+		if base, err = readDlgWriteFile(dlgFs, dlgName); err != nil {
+			return err
+		}
+	} else if verbose {
+		fmt.Printf("%s.DLG is not in the game, creating a new file\n", dlgName)
+	}
+
+	added, replaced := 0, 0
+	resolve := func(strref uint32, known bool, s string) (uint32, error) {
+		if known && int(strref) < len(tlk.Texts) {
+			if tlk.Texts[strref].Text == s {
+				return strref, nil
+			}
+			if replaceText {
+				tlk.SetText(strref, s)
+				replaced++
+				return strref, nil
+			}
+		}
+		entry := text.NewEmptyTlkEntry()
+		entry.Text, entry.HasText = s, true
+		added++
+		return tlk.Append(entry), nil
+	}
+
+	compiler := dialog.NewDlgCompiler(dlgName, base, resolve)
+	for i, canvas := range canvases {
+		if err := compiler.AddCanvas(canvas); err != nil {
+			return fmt.Errorf("error compiling %s: %v", args[i], err)
+		}
+	}
+
+	dlgPath := filepath.Join(outputDir, dlgName+".DLG")
+	if err := dialog.WriteDlgFile(dlgPath, compiler.File); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d states, %d transitions\n", dlgPath, len(compiler.File.States), len(compiler.File.Transitions))
+
+	if added+replaced > 0 {
+		if tlkOutput == "" {
+			tlkOutput = filepath.Join(outputDir, filepath.Base(tlk.Path))
+		}
+		written, err := tlk.Write(tlkOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d texts added, %d replaced\n", strings.Join(written, ", "), added, replaced)
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/spf13/cobra"
+)
+
+func NewDCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "d",
+		Short: "Convert DLG files to and from WeiDU D files",
+		Long: `Decompile DLG files into WeiDU D files and compile D files back into DLG
+files, e.g. to edit a dialog of the game as text or to move it to a mod.
+
+States are labeled with their indexes, so a decompiled file compiles back
+into the same DLG file. Compiling supports D files with a BEGIN action:
+states with IF, SAY, REPLY, DO, JOURNAL, SOLVED_JOURNAL, UNSOLVED_JOURNAL,
+FLAGS, GOTO, EXTERN and EXIT, and the + and ++ short forms.`,
+	}
+
+	cmd.AddCommand(NewDExportCommand())
+	cmd.AddCommand(NewDCompileCommand())
+
+	return cmd
+}
+
+func NewDExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export [DLG-file...]",
+		Aliases: []string{"ex"},
+		Short:   "Decompile DLG files into WeiDU D files",
+		Long: `Decompile DLG files of the game into WeiDU D files, one <DLG>.d file per
+DLG file. Without arguments all DLG files are decompiled.
+
+Texts are strrefs like #123 with the texts in comments. With --tra they are
+references like @0 into a <DLG>.tra file written next to the D file, and
+with --inline they are written in place as ~text~ [SOUND]. If the game has
+dialogf.tlk, texts with a distinct female variant are written as
+~text~ ~female~ in place and in the TRA file.`,
+		Example: `  Decompile the dialog of Khalid with a TRA file:
+
+      sbt-inf dialog d export -o d --tra KHALID`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runDExport,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output directory")
+	cmd.Flags().Bool("tra", false, "Write texts to TRA files as @N references")
+	cmd.Flags().Bool("inline", false, "Write texts in place instead of strrefs")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagDirname("output")
+	cmd.MarkFlagsMutuallyExclusive("tra", "inline")
+
+	return cmd
+}
+
+func NewDCompileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compile [D-file...]",
+		Short: "Compile WeiDU D files into DLG files",
+		Long: `Compile WeiDU D files into DLG V1.0 files named after their BEGIN actions.
+
+References like @0 are resolved against the TRA file given with --tra, or
+the <name>.tra file next to the D file. Texts given in place or in the TRA
+file reuse strrefs of the same texts and sounds in the TLK file, and new
+texts are appended. A female text (~text~ ~female~, or the female variant
+of a TRA entry) is written to dialogf.tlk at the same strref. The TLK file is written next to the DLG files if any
+text was added; if the game has dialogf.tlk, the texts are appended to it
+too, so both files keep the same number of entries.`,
+		Example: `  Compile a decompiled dialog with its TRA file:
+
+      sbt-inf dialog d compile -o override d/KHALID.d`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runDCompile,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output directory")
+	cmd.Flags().String("tra", "", "TRA file for @N references (default: <name>.tra next to the D file)")
+	cmd.Flags().String("tlk-output", "", "Path of the written dialog.tlk file, dialogf.tlk is written next to it (default: <output>/<TLK name>)")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagDirname("output")
+	cmd.MarkFlagFilename("tra", "tra")
+	cmd.MarkFlagFilename("tlk-output", "tlk")
+
+	return cmd
+}
+
+func runDExport(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	withTra, _ := cmd.Flags().GetBool("tra")
+	inline, _ := cmd.Flags().GetBool("inline")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlk, err := readTlkEntries(cmd)
+	if err != nil {
+		return err
+	}
+	entries := tlk.Texts
+
+	opts := dialog.DWriteOptions{
+		Mode: dialog.DTextStrref,
+		Text: func(strref uint32) (string, string) {
+			if int(strref) >= len(entries) {
+				return "", ""
+			}
+			sound := ""
+			if entries[strref].HasSound {
+				sound = entries[strref].AudioName
+			}
+			return entries[strref].Text, sound
+		},
+		FemaleText: func(strref uint32) string {
+			if int(strref) >= len(entries) {
+				return ""
+			}
+			_, female := tlk.Pair(strref)
+			return female
+		},
+	}
+	if withTra {
+		opts.Mode = dialog.DTextTra
+	} else if inline {
+		opts.Mode = dialog.DTextInline
+	}
+
+	dlgFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG))
+	dialogFiles := args
+	if len(dialogFiles) == 0 {
+		dir, err := dlgFs.Open("DLG")
+		if err != nil {
+			return fmt.Errorf("unable to list existing DLG files: %v", err)
+		}
+		defer dir.Close()
+		dialogFiles, err = dir.Readdirnames(0)
+		if err != nil {
+			return fmt.Errorf("unable to read dialog directory names: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("unable to create output directory %s: %v", outputDir, err)
+	}
+
+	for _, df := range dialogFiles {
+		name := dlgName(df)
+
+		dlgFile, err := readDlgWriteFile(dlgFs, name)
+		if err != nil {
+			return err
+		}
+
+		dPath := filepath.Join(outputDir, name+".d")
+		out, err := os.Create(dPath)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %v", dPath, err)
+		}
+		traEntries, err := dialog.WriteD(out, name, dlgFile, opts)
+		out.Close()
+		if err != nil {
+			return err
+		}
+
+		if withTra {
+			traPath := filepath.Join(outputDir, name+".tra")
+			if err := tra.WriteFile(traPath, traEntries); err != nil {
+				return fmt.Errorf("error writing %s: %v", traPath, err)
+			}
+		}
+		if verbose {
+			fmt.Printf("%s: %d states, %d transitions\n", dPath, len(dlgFile.States), len(dlgFile.Transitions))
+		}
+	}
+
+	return nil
+}
+
+func runDCompile(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	traPath, _ := cmd.Flags().GetString("tra")
+	tlkOutput, _ := cmd.Flags().GetString("tlk-output")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	tlk, err := readTlkEntries(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Strrefs of the male and female texts and sounds of the TLK files,
+	// built on the first lookup.
+	var strrefs map[string]uint32
+	textKey := func(male, female, sound string) string {
+		return male + "\x00" + female + "\x00" + sound
+	}
+	added := 0
+	resolve := func(s, female, sound string) (uint32, error) {
+		if female == "" {
+			female = s
+		}
+		if strrefs == nil {
+			strrefs = make(map[string]uint32, len(tlk.Texts))
+			for i, e := range tlk.Texts {
+				male, female := tlk.Pair(uint32(i))
+				sound := ""
+				if e.HasSound {
+					sound = e.AudioName
+				}
+				key := textKey(male, female, sound)
+				if _, ok := strrefs[key]; !ok {
+					strrefs[key] = uint32(i)
+				}
+			}
+		}
+		key := textKey(s, female, sound)
+		if strref, ok := strrefs[key]; ok {
+			return strref, nil
+		}
+		entry := text.NewEmptyTlkEntry()
+		entry.Text, entry.HasText = s, true
+		entry.AudioName, entry.HasSound = sound, sound != ""
+		var strref uint32
+		if female != s {
+			strref = tlk.AppendWithFemale(entry, female)
+		} else {
+			strref = tlk.Append(entry)
+		}
+		strrefs[key] = strref
+		added++
+		return strref, nil
+	}
+
+	for _, path := range args {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read D file %s: %v", path, err)
+		}
+
+		opts := dialog.DCompileOptions{Resolve: resolve}
+		fileTraPath := traPath
+		if fileTraPath == "" {
+			fileTraPath = strings.TrimSuffix(path, filepath.Ext(path)) + ".tra"
+			if _, err := os.Stat(fileTraPath); err != nil {
+				fileTraPath = ""
+			}
+		}
+		if fileTraPath != "" {
+			traFile, err := p.ParseTraFile(fileTraPath)
+			if err != nil {
+				return fmt.Errorf("error reading TRA file %s: %v", fileTraPath, err)
+			}
+			opts.Tra = traFile.ToMap()
+			if verbose {
+				fmt.Printf("%s: using %s\n", path, fileTraPath)
+			}
+		}
+
+		name, dlgFile, err := dialog.CompileD(string(content), opts)
+		if err != nil {
+			return fmt.Errorf("error compiling %s: %v", path, err)
+		}
+
+		dlgPath := filepath.Join(outputDir, name+".DLG")
+		if err := dialog.WriteDlgFile(dlgPath, dlgFile); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d states, %d transitions\n", dlgPath, len(dlgFile.States), len(dlgFile.Transitions))
+	}
+
+	if added > 0 {
+		if tlkOutput == "" {
+			tlkOutput = filepath.Join(outputDir, filepath.Base(tlk.Path))
+		}
+		written, err := tlk.Write(tlkOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d texts added\n", strings.Join(written, ", "), added)
+	}
+
+	return nil
+}
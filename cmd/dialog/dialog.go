@@ -6,6 +6,20 @@
 package dialog
 
 import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/sbtlocalization/sbt-infinity/bcs"
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -18,5 +32,244 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(NewLsCommand())
 	cmd.AddCommand(NewExportCommand())
+	cmd.AddCommand(NewSiteCommand())
+	cmd.AddCommand(NewCompileCommand())
+	cmd.AddCommand(NewImportTextCommand())
+	cmd.AddCommand(NewDCommand())
+	cmd.AddCommand(NewGraphCommand())
+	cmd.AddCommand(NewLintCommand())
+	cmd.AddCommand(NewVarsCommand())
 	return cmd
 }
+
+// Reads a DLG file of the game for writing.
+func readDlgWriteFile(dlgFs afero.Fs, name string) (*dialog.DlgWriteFile, error) {
+	file, err := dlgFs.Open(name + ".DLG")
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s.DLG: %v", name, err)
+	}
+	defer file.Close()
+
+	dlg := p.NewDlg()
+	if err := dlg.Read(kaitai.NewStream(file), nil, dlg); err != nil {
+		return nil, fmt.Errorf("error reading %s.DLG: %v", name, err)
+	}
+	dlgFile, err := dialog.DlgWriteFileFromDlg(dlg)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s.DLG: %v", name, err)
+	}
+	return dlgFile, nil
+}
+
+func dlgName(name string) string {
+	return strings.TrimSuffix(strings.ToUpper(name), ".DLG")
+}
+
+// Reads all DLG files of the game, keyed by their names. Files that cannot
+// be read are skipped.
+func readAllDlgWriteFiles(dlgFs afero.Fs, verbose bool) (map[string]*dialog.DlgWriteFile, error) {
+	dir, err := dlgFs.Open("DLG")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list existing DLG files: %v", err)
+	}
+	names, err := dir.Readdirnames(0)
+	dir.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read dialog directory names: %v", err)
+	}
+
+	files := make(map[string]*dialog.DlgWriteFile, len(names))
+	for _, name := range names {
+		f, err := readDlgWriteFile(dlgFs, dlgName(name))
+		if err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+			continue
+		}
+		files[dlgName(name)] = f
+	}
+	return files, nil
+}
+
+// Resolves the TLK file of the --tlk and --lang flags: the file of --tlk,
+// or dialog.tlk of the language in the game folder. Returns also whether
+// texts are read from the female TLK file next to it (--feminine).
+func resolveTlk(cmd *cobra.Command) (afero.Fs, string, bool, error) {
+	tlkPath, _ := cmd.Flags().GetString("tlk")
+	lang, _ := cmd.Flags().GetString("lang")
+	feminine, _ := cmd.Flags().GetBool("feminine")
+
+	osFs := afero.NewOsFs()
+	if cmd.Flags().Changed("tlk") {
+		return osFs, tlkPath, feminine, nil
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return afero.NewBasePathFs(osFs, filepath.Dir(keyPath)), filepath.Join("lang", lang, "dialog.tlk"), feminine, nil
+}
+
+// Resolves the TLK file to read texts from: the one of resolveTlk or, with
+// --feminine, the female TLK file next to it.
+func resolveTextTlk(cmd *cobra.Command) (afero.Fs, string, error) {
+	tlkFs, tlkPath, feminine, err := resolveTlk(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	if feminine {
+		tlkPath = text.FemaleTlkPath(tlkPath)
+	}
+	return tlkFs, tlkPath, nil
+}
+
+// TLK entries patched by a command: the entries of dialog.tlk and, if it
+// exists, dialogf.tlk. Texts are looked up in and changed in Texts; Write
+// applies the changes to both files, so they keep the same number of
+// entries.
+type tlkEntries struct {
+	Path         string // dialog.tlk
+	Male, Female []text.TlkWriteEntry
+	Lang         uint16
+	Feminine     bool // Texts are of dialogf.tlk
+
+	// Entries of dialogf.tlk with --feminine, otherwise of dialog.tlk,
+	// padded to the size of the larger file.
+	Texts    []text.TlkWriteEntry
+	changed  map[uint32]bool
+	variants map[uint32][2]string // appended male and female texts
+}
+
+// Reads the TLK files of the --tlk, --lang and --feminine flags.
+func readTlkEntries(cmd *cobra.Command) (*tlkEntries, error) {
+	tlkFs, tlkPath, feminine, err := resolveTlk(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	male, female, lang, err := text.ReadTlkWriteEntries(tlkFs, tlkPath)
+	if err != nil {
+		return nil, err
+	}
+	if feminine && female == nil {
+		return nil, fmt.Errorf("female TLK file %s not found", text.FemaleTlkPath(tlkPath))
+	}
+
+	t := &tlkEntries{
+		Path:     tlkPath,
+		Male:     male,
+		Female:   female,
+		Lang:     lang,
+		Feminine: feminine,
+		changed:  make(map[uint32]bool),
+		variants: make(map[uint32][2]string),
+	}
+	if feminine {
+		t.Texts = slices.Clone(female)
+	} else {
+		t.Texts = slices.Clone(male)
+	}
+	for len(t.Texts) < max(len(male), len(female)) {
+		t.Texts = append(t.Texts, text.NewEmptyTlkEntry())
+	}
+	return t, nil
+}
+
+// Changes the text of an entry.
+func (t *tlkEntries) SetText(strref uint32, s string) {
+	t.Texts[strref].Text, t.Texts[strref].HasText = s, true
+	t.changed[strref] = true
+}
+
+// Appends an entry and returns its strref.
+func (t *tlkEntries) Append(entry text.TlkWriteEntry) uint32 {
+	t.Texts = append(t.Texts, entry)
+	strref := uint32(len(t.Texts) - 1)
+	t.changed[strref] = true
+	return strref
+}
+
+// Appends an entry with a distinct female text and returns its strref. The
+// text of the entry is the male one.
+func (t *tlkEntries) AppendWithFemale(entry text.TlkWriteEntry, female string) uint32 {
+	male := entry.Text
+	if t.Feminine {
+		entry.Text = female
+	}
+	strref := t.Append(entry)
+	t.variants[strref] = [2]string{male, female}
+	return strref
+}
+
+// Returns the male and female texts of an entry. Without dialogf.tlk both
+// are the text of dialog.tlk.
+func (t *tlkEntries) Pair(strref uint32) (string, string) {
+	if v, ok := t.variants[strref]; ok {
+		return v[0], v[1]
+	}
+	male, female := t.Texts[strref].Text, t.Texts[strref].Text
+	switch {
+	case t.Feminine && int(strref) < len(t.Male):
+		male = t.Male[strref].Text
+	case !t.Feminine && int(strref) < len(t.Female):
+		female = t.Female[strref].Text
+	}
+	return male, female
+}
+
+// Writes the changed entries to the TLK file at path and, if the game has
+// dialogf.tlk or the texts are of it, to the female TLK file next to it.
+// Returns the written paths.
+func (t *tlkEntries) Write(path string) ([]string, error) {
+	patches := make([]text.TlkPatch, 0, len(t.changed))
+	for _, strref := range slices.Sorted(maps.Keys(t.changed)) {
+		patch := text.TlkPatch{Key: strref, Male: t.Texts[strref]}
+		if v, ok := t.variants[strref]; ok {
+			patch.Female, patch.HasFemale = t.Texts[strref], true
+			patch.Male.Text, patch.Female.Text = v[0], v[1]
+		} else if t.Feminine {
+			patch.Female, patch.HasFemale = t.Texts[strref], true
+			if int(strref) < len(t.Male) {
+				patch.Male = t.Male[strref]
+			}
+		}
+		patches = append(patches, patch)
+	}
+
+	male, female, _, _ := text.PatchTlkEntries(t.Male, t.Female, patches)
+	opts := text.TlkWriteOptions{Lang: t.Lang}
+	if err := text.WriteTlkFile(path, male, opts); err != nil {
+		return nil, fmt.Errorf("failed to write TLK file: %w", err)
+	}
+	written := []string{path}
+	if female != nil {
+		femalePath := text.FemaleTlkPath(path)
+		if err := text.WriteTlkFile(femalePath, female, opts); err != nil {
+			return nil, fmt.Errorf("failed to write TLK file: %w", err)
+		}
+		written = append(written, femalePath)
+	}
+	return written, nil
+}
+
+// Returns the functions of TRIGGER.IDS or ACTION.IDS, or nil if the game
+// does not have the file.
+func readFunctions(infFs afero.Fs, name string, verbose bool) *bcs.Functions {
+	file, err := infFs.Open(name)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "warning: %s is not in the game, its functions are unknown\n", name)
+		}
+		return nil
+	}
+	defer file.Close()
+
+	funcs, err := bcs.ParseFunctions(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to parse %s: %v\n", name, err)
+		return nil
+	}
+	return funcs
+}
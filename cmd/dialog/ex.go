@@ -29,9 +29,18 @@ func NewExportCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "export [DLG-file...]",
 		Aliases: []string{"ex"},
-		Short:   "Export dialogs as dCanvas files",
+		Short:   "Export dialogs as dCanvas, DOT, SVG, Ink, Yarn or Twee files",
 		Long: `Export dialogs from DLG files (with texts from TLK file) as dCanvas files.
-Creates a visual representation of dialog structures.`,
+Creates a visual representation of dialog structures.
+
+With --format dot the dialogs are Graphviz DOT graphs, and with --format svg
+standalone SVG images laid out the same way as dCanvas files. Edges carry
+the trigger conditions; loops back to earlier states are dashed.
+
+With --format ink, yarn or twee the dialogs are stories for Ink, Yarn Spinner
+or Twine (Twee 3, Harlowe), to play through a dialog outside the game. States
+are knots, nodes or passages, transitions are choices, and triggers and
+actions are tagged comments.`,
 		Args: cobra.MinimumNArgs(0),
 		RunE: runExportDialogs,
 	}
@@ -41,6 +50,7 @@ Creates a visual representation of dialog structures.`,
 	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
 
 	cmd.Flags().StringP("output", "o", "", "Output directory")
+	cmd.Flags().String("format", "dcanvas", "Output format: dcanvas, dot, svg, ink, yarn or twee")
 	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
 	cmd.Flags().BoolP("speakers", "s", true, "Load and export information about characters from CRE files")
 	cmd.Flags().StringSliceP("exclude", "x", []string{}, "Exclude specific dialog files (e.g., ABISHAB.DLG)")
@@ -55,9 +65,6 @@ Creates a visual representation of dialog structures.`,
 }
 
 func runExportDialogs(cmd *cobra.Command, args []string) error {
-	tlkPath, _ := cmd.Flags().GetString("tlk")
-	lang, _ := cmd.Flags().GetString("lang")
-	feminine, _ := cmd.Flags().GetBool("feminine")
 	outputDir, _ := cmd.Flags().GetString("output")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	withCreatures, _ := cmd.Flags().GetBool("speakers")
@@ -66,6 +73,20 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 	soundSuffix, _ := cmd.Flags().GetString("sound-suffix")
 	reportPath, _ := cmd.Flags().GetString("report")
 	scanOverlaps, _ := cmd.Flags().GetBool("scan-overlaps")
+	format, _ := cmd.Flags().GetString("format")
+
+	extensions := map[string]string{
+		"dcanvas": ".d.canvas",
+		"dot":     ".dot",
+		"svg":     ".svg",
+		"ink":     ".ink",
+		"yarn":    ".yarn",
+		"twee":    ".twee",
+	}
+	extension, ok := extensions[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected dcanvas, dot, svg, ink, yarn or twee", format)
+	}
 
 	fmtOpts := dialog.FormatOptions{
 		SoundPrefix: soundPrefix,
@@ -83,18 +104,9 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 		dialogFiles = args
 	}
 
-	osFs := afero.NewOsFs()
-
-	var tlkFs afero.Fs
-	if !cmd.Flags().Changed("tlk") {
-		tlkFs = afero.NewBasePathFs(osFs, filepath.Dir(keyPath))
-		if feminine {
-			tlkPath = filepath.Join("lang", lang, "dialogf.tlk")
-		} else {
-			tlkPath = filepath.Join("lang", lang, "dialog.tlk")
-		}
-	} else {
-		tlkFs = osFs
+	tlkFs, tlkPath, err := resolveTextTlk(cmd)
+	if err != nil {
+		return err
 	}
 
 	typesToLoad := []fs.FileType{fs.FileType_DLG}
@@ -154,7 +166,11 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 			fmt.Printf("%s: loaded %d dialogs\n", df, len(dlg.Dialogs))
 		}
 		for _, d := range dlg.Dialogs {
-			canvas := d.ToDCanvas(fmtOpts)
+			// Only dCanvas and SVG place nodes themselves.
+			var layout *dialog.Layout
+			if format == "dcanvas" || format == "svg" {
+				layout = d.Layout(fmtOpts)
+			}
 
 			if reportPath != "" {
 				for _, node := range d.All() {
@@ -181,8 +197,8 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 				}
 			}
 			dialogName := strings.TrimSuffix(d.Id.DlgName, filepath.Ext(d.Id.DlgName))
-			fileName := filepath.Join(outputDir, fmt.Sprintf("%s-%d.d.canvas", dialogName, d.Id.Index))
-			if scanOverlaps && canvas.HasOverlappingNodes() {
+			fileName := filepath.Join(outputDir, fmt.Sprintf("%s-%d%s", dialogName, d.Id.Index, extension))
+			if scanOverlaps && layout != nil && layout.Canvas.HasOverlappingNodes() {
 				overlapFiles = append(overlapFiles, fileName)
 				if verbose {
 					fmt.Printf("  warning: overlapping nodes detected in %s\n", fileName)
@@ -198,42 +214,26 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 			}
 			defer file.Close()
 
-			err = dcanvas.Encode(canvas, file)
+			switch format {
+			case "dot":
+				err = d.WriteDot(file, fmtOpts)
+			case "svg":
+				err = d.WriteSvg(file, layout)
+			case "ink":
+				err = d.WriteInk(file)
+			case "yarn":
+				err = d.WriteYarn(file)
+			case "twee":
+				err = d.WriteTwee(file)
+			default:
+				err = dcanvas.Encode(layout.Canvas, file)
+			}
 			if err != nil {
-				return fmt.Errorf("error encoding canvas for dialog %s: %v", d.Id, err)
+				return fmt.Errorf("error writing %s for dialog %s: %v", format, d.Id, err)
 			}
 
 			for _, creature := range d.AllCreatures {
-				if creature.Portrait != "" {
-					if picFile, err := dlgFs.Open(creature.Portrait); err == nil {
-						img, _, err := image.Decode(picFile)
-						picFile.Close()
-						if err != nil {
-							fmt.Printf("error converting portrait %s for %s: %v\n", creature.Portrait, creature.LongName, err)
-							continue
-						}
-
-						picName := strings.TrimSuffix(creature.Portrait, filepath.Ext(creature.Portrait)) + ".png"
-
-						err = os.MkdirAll(filepath.Join(outputDir, "portraits"), 0755)
-						if err != nil {
-							fmt.Printf("error creating portraits directory for %s: %v\n", creature.LongName, err)
-							continue
-						}
-
-						outFile, err := os.Create(filepath.Join(outputDir, "portraits", picName))
-						if err != nil {
-							fmt.Printf("error creating portrait file %s for %s: %v\n", picName, creature.LongName, err)
-							continue
-						}
-						defer outFile.Close()
-
-						if err := png.Encode(outFile, img); err != nil {
-							fmt.Printf("error writing portrait file %s for %s: %v\n", picName, creature.LongName, err)
-							continue
-						}
-					}
-				}
+				exportPortrait(dlgFs, creature, outputDir)
 			}
 		}
 	}
@@ -282,3 +282,43 @@ func runExportDialogs(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// Converts the portrait of the creature to PNG in the portraits
+// subdirectory of outputDir. Returns the PNG path relative to outputDir, or
+// an empty string if the creature has no portrait or it can't be converted.
+func exportPortrait(dlgFs afero.Fs, creature *dialog.Creature, outputDir string) string {
+	if creature.Portrait == "" {
+		return ""
+	}
+	picFile, err := dlgFs.Open(creature.Portrait)
+	if err != nil {
+		return ""
+	}
+	img, _, err := image.Decode(picFile)
+	picFile.Close()
+	if err != nil {
+		fmt.Printf("error converting portrait %s for %s: %v\n", creature.Portrait, creature.LongName, err)
+		return ""
+	}
+
+	picName := strings.TrimSuffix(creature.Portrait, filepath.Ext(creature.Portrait)) + ".png"
+
+	err = os.MkdirAll(filepath.Join(outputDir, "portraits"), 0755)
+	if err != nil {
+		fmt.Printf("error creating portraits directory for %s: %v\n", creature.LongName, err)
+		return ""
+	}
+
+	outFile, err := os.Create(filepath.Join(outputDir, "portraits", picName))
+	if err != nil {
+		fmt.Printf("error creating portrait file %s for %s: %v\n", picName, creature.LongName, err)
+		return ""
+	}
+	defer outFile.Close()
+
+	if err := png.Encode(outFile, img); err != nil {
+		fmt.Printf("error writing portrait file %s for %s: %v\n", picName, creature.LongName, err)
+		return ""
+	}
+	return "portraits/" + picName
+}
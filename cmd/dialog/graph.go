@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewGraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph [DLG-file...]",
+		Short: "Show how DLG files link to each other",
+		Long: `Build the graph of transitions between DLG files of the game: EXTERNs,
+interjections and banters. For each DLG file the outbound and inbound links
+are listed with their state indexes, so dialogs that must be read together,
+like companion interjections in a main dialog, can be found.
+
+A link into another DLG file which returns to the same file is an
+interjection, and so is the link returning. Links from or to banter files
+of INTERDIA.2DA and --banter are banters; other links are EXTERNs.
+
+All DLG files are read; with arguments only the links from or to the given
+files are shown. With --format json or dot the graph is written as JSON or
+as a Graphviz DOT graph.`,
+		Example: `  List the links of the dialog of Jaheira:
+
+      sbt-inf dialog graph JAHEIRA
+
+  Draw the graph of all dialogs:
+
+      sbt-inf dialog graph --format dot -o dialogs.dot`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runGraph,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output file (default: standard output)")
+	cmd.Flags().String("format", "text", "Output format: text, json or dot")
+	cmd.Flags().StringSlice("banter", []string{}, "Additional banter files (e.g., BJAHEIR.DLG)")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagFilename("output", "json", "dot", "txt")
+
+	return cmd
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	banterFiles, _ := cmd.Flags().GetStringSlice("banter")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if format != "text" && format != "json" && format != "dot" {
+		return fmt.Errorf("unknown format %q, expected text, json or dot", format)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	infFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG, fs.FileType_2DA))
+
+	banters := make(map[string]bool)
+	for _, name := range banterFiles {
+		banters[dlgName(name)] = true
+	}
+	for _, name := range readInterdiaBanters(infFs, verbose) {
+		banters[name] = true
+	}
+
+	files, err := readAllDlgWriteFiles(infFs, verbose)
+	if err != nil {
+		return err
+	}
+
+	graph := dialog.BuildDlgGraph(files, banters)
+	if len(args) > 0 {
+		selected := make([]string, 0, len(args))
+		for _, arg := range args {
+			selected = append(selected, dlgName(arg))
+		}
+		graph = graph.Subgraph(selected...)
+		// Only the given files are listed as text.
+		if format == "text" {
+			graph.Names = selected
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %v", outputPath, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case "json":
+		return graph.WriteJson(w)
+	case "dot":
+		return graph.WriteDot(w)
+	}
+
+	for _, name := range graph.Names {
+		outbound, inbound := graph.Outbound(name), graph.Inbound(name)
+		fmt.Fprintf(w, "%s.DLG: %d outbound, %d inbound\n", name, len(outbound), len(inbound))
+		for _, l := range outbound {
+			fmt.Fprintf(w, "  [%d] -> %s (%s)\n", l.From.Index, l.To, l.Kind)
+		}
+		for _, l := range inbound {
+			fmt.Fprintf(w, "  [%d] <- %s (%s)\n", l.To.Index, l.From, l.Kind)
+		}
+	}
+	return nil
+}
+
+// Returns the banter files of INTERDIA.2DA, if the game has it.
+func readInterdiaBanters(infFs afero.Fs, verbose bool) []string {
+	file, err := infFs.Open("INTERDIA.2DA")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	table, err := p.ParseTwoDA(file)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "warning: unable to parse INTERDIA.2DA: %v\n", err)
+		}
+		return nil
+	}
+
+	var banters []string
+	for _, row := range table.RowKeys {
+		if name, ok := table.Get(row, "FILE"); ok && name != table.DefaultValue {
+			banters = append(banters, dlgName(name))
+		}
+	}
+	return banters
+}
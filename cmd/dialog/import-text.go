@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewImportTextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-text [dCanvas-file...]",
+		Short: "Collect texts edited in dCanvas files",
+		Long: `Collect texts edited in dCanvas files made by 'dialog export', e.g. by editors
+proofreading dialogs in the canvas view.
+
+The texts of nodes (text and x-journalText) are compared with the TLK file
+by x-textId and x-journalTextId. The changed strings are written as:
+
+  - CSV (.csv) with 'id', 'text', 'original' and 'source' columns, for
+    'tra update' and 'csv diff';
+  - XLSX (.xlsx) patch for 'text import --base'.
+
+A strref edited differently in several canvases is a conflict: it is
+reported and not written.`,
+		Example: `  Collect the edits of all canvases for 'tra update':
+
+      sbt-inf dialog import-text -o edits.csv dialogs/*.d.canvas
+
+  Patch the Ukrainian TLK file:
+
+      sbt-inf dialog import-text -l uk_UA -o edits.xlsx dialogs/*.d.canvas
+      sbt-inf text import -i edits.xlsx --base lang/uk_UA/dialog.tlk -o lang/uk_UA/`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runImportText,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output CSV or XLSX file")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("output", "csv", "xlsx")
+
+	return cmd
+}
+
+func runImportText(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	ext := strings.ToLower(filepath.Ext(outputPath))
+	if ext != ".csv" && ext != ".xlsx" {
+		return fmt.Errorf("output file must be .csv or .xlsx: %s", outputPath)
+	}
+
+	var texts []dialog.CanvasText
+	for _, path := range args {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open dCanvas file %s: %v", path, err)
+		}
+		canvas, err := dcanvas.Decode(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", path, err)
+		}
+		texts = append(texts, dialog.CanvasTexts(canvas, path)...)
+	}
+
+	tlkFs, tlkPath, err := resolveTextTlk(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlkFile, err := p.ReadTlkFile(tlkFs, tlkPath)
+	if err != nil {
+		return err
+	}
+	defer tlkFile.Close()
+
+	result := dialog.CompareTexts(texts, func(strref uint32) (string, bool) {
+		if int(strref) >= len(tlkFile.Entries) {
+			return "", false
+		}
+		s, err := tlkFile.Entries[strref].Text()
+		return s, err == nil
+	})
+
+	for _, t := range result.Unknown {
+		fmt.Printf("warning: %s: node %s has strref #%d, which is not in the TLK file\n", t.Source, t.NodeId, t.StrRef)
+	}
+	for _, c := range result.Conflicts {
+		fmt.Printf("conflict: #%d is edited differently:\n", c.StrRef)
+		for _, t := range c.Texts {
+			fmt.Printf("  %s (%s): %q\n", t.Source, t.NodeId, t.Text)
+		}
+	}
+	if verbose {
+		for _, e := range result.Edits {
+			fmt.Printf("#%d: %q -> %q\n", e.StrRef, e.Original, e.Text)
+		}
+	}
+
+	if ext == ".csv" {
+		err = writeTextEditsCsv(outputPath, result.Edits)
+	} else {
+		collection := text.NewTextCollection(tlkFile.Tlk)
+		ids := make([]uint32, 0, len(result.Edits))
+		for _, e := range result.Edits {
+			collection.Entries[e.StrRef].Text = e.Text
+			collection.Entries[e.StrRef].HasText = true
+			ids = append(ids, e.StrRef)
+		}
+		err = collection.ExportEntriesToXlsx(outputPath, ids, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d changed texts written to %s, %d conflicts\n", len(result.Edits), outputPath, len(result.Conflicts))
+	return nil
+}
+
+func writeTextEditsCsv(path string, edits []dialog.TextEdit) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create output file: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"id", "text", "original", "source"})
+	for _, e := range edits {
+		sources := make([]string, 0, len(e.Sources))
+		for _, s := range e.Sources {
+			sources = append(sources, s.Source)
+		}
+		slices.Sort(sources)
+		sources = slices.Compact(sources)
+		w.Write([]string{strconv.FormatUint(uint64(e.StrRef), 10), e.Text, e.Original, strings.Join(sources, ", ")})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("error writing CSV file: %w", err)
+	}
+	return nil
+}
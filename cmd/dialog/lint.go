@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/spf13/cobra"
+)
+
+func NewLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [DLG-file...]",
+		Short: "Find unreachable states and broken transitions in dialogs",
+		Long: `Check the DLG files of the game for dead content and engine bugs:
+
+  orphan-state         states not reachable from any root state (a state with
+                       a trigger) of any DLG file
+  dangling-transition  transitions to missing DLG files or states
+  empty-state          states without text, or with an empty text
+  endless-loop         states from which the dialog never ends
+  invalid-script       triggers and actions with syntax errors, unknown
+                       functions or wrong arguments for TRIGGER.IDS and
+                       ACTION.IDS
+
+All DLG files are read, as transitions lead from one file to another; with
+arguments only the issues of the given files are shown. Orphan states need
+no translation.`,
+		Example: `  Check the dialogs of Khalid and Jaheira:
+
+      sbt-inf dialog lint KHALID JAHEIRA
+
+  Write all issues as JSON lines:
+
+      sbt-inf dialog lint -j > lint.jsonl`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runLint,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().BoolP("json", "j", false, "Output in JSONL format")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	return cmd
+}
+
+type lintIssueJson struct {
+	Kind    dialog.LintKind `json:"kind"`
+	Node    string          `json:"node"`
+	Message string          `json:"message"`
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlk, err := readTlkEntries(cmd)
+	if err != nil {
+		return err
+	}
+	entries := tlk.Texts
+
+	infFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG, fs.FileType_IDS))
+	files, err := readAllDlgWriteFiles(infFs, verbose)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool, len(args))
+	for _, arg := range args {
+		selected[dlgName(arg)] = true
+	}
+
+	issues := dialog.LintDlgFiles(files, dialog.LintOptions{
+		Text: func(strref uint32) (string, bool) {
+			if int(strref) >= len(entries) {
+				return "", false
+			}
+			return entries[strref].Text, true
+		},
+		Triggers: readFunctions(infFs, "TRIGGER.IDS", verbose),
+		Actions:  readFunctions(infFs, "ACTION.IDS", verbose),
+	})
+
+	counts := make(map[dialog.LintKind]int)
+	encoder := json.NewEncoder(os.Stdout)
+	for _, issue := range issues {
+		if len(selected) > 0 && !selected[issue.Origin.DlgName] {
+			continue
+		}
+		counts[issue.Kind]++
+		if jsonOutput {
+			if err := encoder.Encode(lintIssueJson{issue.Kind, fmt.Sprintf("%s-%s", issue.Type, issue.Origin), issue.Message}); err != nil {
+				return fmt.Errorf("error writing JSON: %v", err)
+			}
+		} else {
+			fmt.Println(issue)
+		}
+	}
+
+	if !jsonOutput {
+		fmt.Printf("\n%d orphan states, %d dangling transitions, %d empty states, %d states in endless loops, %d invalid scripts\n",
+			counts[dialog.OrphanState], counts[dialog.DanglingTransition], counts[dialog.EmptyState], counts[dialog.EndlessLoop], counts[dialog.InvalidScript])
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/spf13/cobra"
+)
+
+func NewSiteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "site [DLG-file...]",
+		Short: "Render dialogs as a static HTML site",
+		Long: `Render dialogs from DLG files (with texts from TLK file) as a static HTML
+site, e.g. to publish at dialog_site_base_url of the config file.
+
+Every dialog is a page dialog/<dlg>-<root state>/index.html with the speaker,
+portrait, texts, triggers and actions of its states and transitions. Nodes
+have the anchors used by the dialog links of 'text export', so links like
+<base URL>/dialog/abishab-0#state-abishab-3- lead to the node. States of
+other DLG files reached by EXTERN jumps link to their own dialogs.
+
+The index.html page lists dialogs per speaker and has a search box.`,
+		Example: `  Build the site of all dialogs:
+
+      sbt-inf dialog site -o site
+
+  Build the site of two characters in Ukrainian:
+
+      sbt-inf dialog site -o site -l uk_UA JAHEIRA KHALID`,
+		Args: cobra.MinimumNArgs(0),
+		RunE: runSite,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output directory")
+	cmd.Flags().String("title", "Dialogs", "Title of the index page")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+	cmd.Flags().BoolP("speakers", "s", true, "Load information about characters from CRE files")
+	cmd.Flags().StringSliceP("exclude", "x", []string{}, "Exclude specific dialog files (e.g., ABISHAB.DLG)")
+
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagDirname("output")
+
+	return cmd
+}
+
+func runSite(cmd *cobra.Command, args []string) error {
+	outputDir, _ := cmd.Flags().GetString("output")
+	title, _ := cmd.Flags().GetString("title")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	withCreatures, _ := cmd.Flags().GetBool("speakers")
+	excludeFiles, _ := cmd.Flags().GetStringSlice("exclude")
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlkFs, tlkPath, err := resolveTextTlk(cmd)
+	if err != nil {
+		return err
+	}
+
+	typesToLoad := []fs.FileType{fs.FileType_DLG}
+	if withCreatures {
+		typesToLoad = append(typesToLoad, fs.FileType_CRE, fs.FileType_BMP, fs.FileType_IDS)
+	}
+	dlgFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(typesToLoad...))
+
+	dc := dialog.NewDialogBuilder(dlgFs, tlkFs, withCreatures, verbose)
+
+	dialogFiles := args
+	if len(dialogFiles) == 0 {
+		dir, err := dlgFs.Open("DLG")
+		if err != nil {
+			return fmt.Errorf("unable to list existing DLG files: %v", err)
+		}
+		defer dir.Close()
+		dialogFiles, err = dir.Readdirnames(0)
+		if err != nil {
+			return fmt.Errorf("unable to read dialog directory names: %v", err)
+		}
+	}
+
+	excludeMap := make(map[string]bool)
+	for _, ef := range excludeFiles {
+		ef = strings.ToUpper(ef)
+		if !strings.HasSuffix(ef, ".DLG") {
+			ef = ef + ".DLG"
+		}
+		excludeMap[ef] = true
+	}
+	dialogFiles = slices.DeleteFunc(dialogFiles, func(df string) bool {
+		df = strings.ToUpper(df)
+		if !strings.HasSuffix(df, ".DLG") {
+			df = df + ".DLG"
+		}
+		return excludeMap[df]
+	})
+
+	// Root states of all dialogs, to link EXTERN jumps to other pages.
+	roots, err := dc.LoadAllRootStates(dialogFiles...)
+	if err != nil {
+		return fmt.Errorf("error loading dialogs: %v", err)
+	}
+	linker := dialog.NewSiteLinker(roots)
+
+	if err := os.MkdirAll(filepath.Join(outputDir, "dialog"), 0755); err != nil {
+		return fmt.Errorf("unable to create output directory %s: %v", outputDir, err)
+	}
+
+	// Dialogs of the index, without the trees.
+	index := dialog.NewDialogCollection()
+	pages := 0
+
+	for _, df := range dialogFiles {
+		collection, err := dc.LoadAllDialogs(tlkPath, df)
+		if err != nil {
+			return fmt.Errorf("error loading dialogs: %v", err)
+		}
+
+		for _, d := range collection.Dialogs {
+			for _, creature := range d.AllCreatures {
+				if _, done := linker.Portraits[creature.Portrait]; !done {
+					linker.Portraits[creature.Portrait] = exportPortrait(dlgFs, creature, outputDir)
+				}
+			}
+
+			pageDir := filepath.Join(outputDir, "dialog", d.PageName())
+			if err := os.MkdirAll(pageDir, 0755); err != nil {
+				return fmt.Errorf("unable to create directory %s: %v", pageDir, err)
+			}
+			file, err := os.Create(filepath.Join(pageDir, "index.html"))
+			if err != nil {
+				return fmt.Errorf("error creating page of dialog %s: %v", d.Id, err)
+			}
+			err = linker.WriteDialogHtml(file, d)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			pages++
+
+			if verbose {
+				fmt.Printf("  %s: %s\n", d.Id, pageDir)
+			}
+
+			root := *d.RootState
+			root.Children = nil
+			index.Dialogs = append(index.Dialogs, &dialog.Dialog{Id: d.Id, RootState: &root, AllCreatures: d.AllCreatures})
+		}
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		return fmt.Errorf("error creating index page: %v", err)
+	}
+	defer file.Close()
+	if err := linker.WriteIndexHtml(file, title, index); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d dialog pages written to %s\n", pages, outputDir)
+	return nil
+}
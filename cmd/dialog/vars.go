@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/dialog"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/spf13/cobra"
+)
+
+func NewVarsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vars [DLG-file...]",
+		Short: "Index variables set and checked in dialogs",
+		Long: `Parse triggers and actions of dialogs and list every variable they use:
+where it is set (SetGlobal, IncrementGlobal, ...) and where it is checked
+(Global, GlobalGT, ...), with which values and in which dialog, state or
+transition. Triggers and actions are resolved against TRIGGER.IDS and
+ACTION.IDS of the game, which tell the variables and the values of every
+function.
+
+With a base URL of the dialog site (--dlg-base-url or dialog_site_base_url
+of the config file) every place links to its node on the site built by
+'dialog site'.
+
+All DLG files are read unless files are given. With --format json the index
+is written as JSON.`,
+		Example: `  List variables used in the dialog of Jaheira:
+
+      sbt-inf dialog vars JAHEIRA
+
+  Write the index of all variables with "Jaheira" in the name as JSON:
+
+      sbt-inf dialog vars -n jaheira --format json -o vars.json`,
+		Args: cobra.ArbitraryArgs,
+		RunE: runVars,
+	}
+
+	cmd.Flags().StringP("lang", "l", "en_US", "Language code for TLK file")
+	cmd.Flags().StringP("tlk", "t", "<KEY_DIR>/lang/<LANG>/dialog.tlk", "Path to dialog.tlk file")
+	cmd.Flags().BoolP("feminine", "f", false, "Open dialogf.tlk instead of dialog.tlk")
+
+	cmd.Flags().StringP("output", "o", "", "Output file (default: standard output)")
+	cmd.Flags().String("format", "text", "Output format: text or json")
+	cmd.Flags().StringP("name", "n", "", "Show only variables with the `substring` in the name")
+	cmd.Flags().String("dlg-base-url", "", "base `URL` for dialog references (overrides config)")
+	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	cmd.MarkFlagFilename("output", "json", "txt")
+
+	return cmd
+}
+
+func runVars(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	nameFilter, _ := cmd.Flags().GetString("name")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	baseUrl, _ := config.ResolveDialogBaseUrl(cmd)
+
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlkFs, tlkPath, err := resolveTextTlk(cmd)
+	if err != nil {
+		return err
+	}
+
+	dlgFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG, fs.FileType_IDS))
+	dc := dialog.NewDialogBuilder(dlgFs, tlkFs, false, verbose)
+
+	dialogFiles := args
+	if len(dialogFiles) == 0 {
+		dir, err := dlgFs.Open("DLG")
+		if err != nil {
+			return fmt.Errorf("unable to list existing DLG files: %v", err)
+		}
+		defer dir.Close()
+		dialogFiles, err = dir.Readdirnames(0)
+		if err != nil {
+			return fmt.Errorf("unable to read dialog directory names: %v", err)
+		}
+	}
+
+	collection, err := dc.LoadAllDialogs(tlkPath, dialogFiles...)
+	if err != nil {
+		return fmt.Errorf("error loading dialogs: %v", err)
+	}
+
+	index, errs := dialog.BuildVarIndex(collection, dialog.VarIndexOptions{
+		BaseUrl:  baseUrl,
+		Triggers: readFunctions(dlgFs, "TRIGGER.IDS", true),
+		Actions:  readFunctions(dlgFs, "ACTION.IDS", true),
+	})
+	if verbose {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "warning: unable to parse %v\n", err)
+		}
+	} else if len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: %d triggers and actions cannot be parsed, use --verbose to list them\n", len(errs))
+	}
+	if nameFilter != "" {
+		nameFilter = strings.ToUpper(nameFilter)
+		index.Vars = slices.DeleteFunc(index.Vars, func(v *dialog.Var) bool {
+			return !strings.Contains(strings.ToUpper(v.Name), nameFilter)
+		})
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("unable to create %s: %v", outputPath, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if format == "json" {
+		return index.WriteJson(w)
+	}
+
+	for _, v := range index.Vars {
+		set, checks := v.Filter(dialog.VarSet), v.Filter(dialog.VarCheck)
+		fmt.Fprintf(w, "%s: %d set, %d checks\n", v, len(set), len(checks))
+		for _, a := range v.Accesses {
+			fmt.Fprintf(w, "  %-5s %s %s in %s (%s)", a.Kind, a.Function, a.Condition(), a.Node, a.Dialog)
+			if a.Url != "" {
+				fmt.Fprintf(w, " %s", a.Url)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
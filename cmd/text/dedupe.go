@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewDedupeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find duplicate source texts with inconsistent translations",
+		Long: `Group strrefs with identical source text (taken from the game TLK file) and
+report groups whose translations disagree. Untranslated strrefs, whose text
+is empty or the same as the source, are left out.
+
+With --normalize the source texts are compared ignoring punctuation and
+differences in whitespace. Male and female variants (separated with
+--separator, as in 'text import') are compared separately.
+
+With --propagate the translation is copied to the strrefs of the group with
+exactly the same source text and the result is written to --output. The translation of a strref given with --choose wins;
+otherwise the most common variant is used. Groups without a clear majority
+are left untouched and reported.`,
+		Example: `  Report inconsistent translations:
+
+      sbt-inf text dedupe -i dialog.xlsx
+
+  Propagate the majority translations, preferring the translation of #1234:
+
+      sbt-inf text dedupe -i dialog.xlsx --normalize --propagate --choose 1234 -o fixed.xlsx`,
+		Args: cobra.NoArgs,
+		RunE: runDedupe,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file`")
+	cmd.Flags().StringP("output", "o", "", "output XLSX `file` for --propagate")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants")
+	cmd.Flags().BoolP("normalize", "n", false, "ignore punctuation and whitespace differences in source texts")
+	cmd.Flags().BoolP("all", "a", false, "report all groups, not only inconsistent ones")
+	cmd.Flags().Bool("propagate", false, "copy one translation to the whole group (XLSX input only)")
+	cmd.Flags().UintSlice("choose", []uint{}, "`strrefs` whose translations win over the majority")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+	cmd.MarkFlagFilename("output", "xlsx")
+	cmd.MarkFlagsRequiredTogether("propagate", "output")
+
+	return cmd
+}
+
+type dedupeGroupReport struct {
+	Source       string            `json:"source"`
+	Ids          []uint32          `json:"ids"`
+	Translations map[uint32]string `json:"translations"`
+	Consistent   bool              `json:"consistent"`
+	Propagated   map[string]string `json:"propagated,omitempty"` // by exact source text
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	outputPath, _ := cmd.Flags().GetString("output")
+	separator, _ := cmd.Flags().GetString("separator")
+	normalize, _ := cmd.Flags().GetBool("normalize")
+	all, _ := cmd.Flags().GetBool("all")
+	propagate, _ := cmd.Flags().GetBool("propagate")
+	choose, _ := cmd.Flags().GetUintSlice("choose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if propagate && !strings.HasSuffix(strings.ToLower(inputPath), ".xlsx") {
+		return fmt.Errorf("--propagate requires an xlsx input file: %s", inputPath)
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return err
+	}
+	sources := tlkTexts(tlkFile.Tlk)
+	tlkFile.Close()
+
+	translations, err := loadTranslations(inputPath)
+	if err != nil {
+		return err
+	}
+
+	chosen := make(map[uint32]struct{}, len(choose))
+	for _, id := range choose {
+		chosen[uint32(id)] = struct{}{}
+	}
+
+	updates := make(map[uint32]string)
+	var reports []dedupeGroupReport
+	unresolved := 0
+
+	for _, group := range text.FindDuplicates(sources, translations, normalize) {
+		consistent := !group.Disagrees(separator)
+		if consistent && !all {
+			continue
+		}
+
+		report := dedupeGroupReport{
+			Source:       group.Source,
+			Ids:          group.Ids,
+			Translations: group.Translations,
+			Consistent:   consistent,
+		}
+
+		if propagate && !consistent {
+			for _, same := range group.SplitBySource() {
+				if !same.Disagrees(separator) {
+					continue
+				}
+				resolved, ok := same.Resolve(separator, chosen)
+				if !ok {
+					unresolved++
+					continue
+				}
+				if report.Propagated == nil {
+					report.Propagated = make(map[string]string)
+				}
+				report.Propagated[same.Source] = resolved
+				for _, id := range same.Ids {
+					updates[id] = resolved
+				}
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	if jsonOutput {
+		for _, report := range reports {
+			jsonData, _ := json.Marshal(report)
+			fmt.Println(string(jsonData))
+		}
+	} else {
+		for _, report := range reports {
+			printDedupeGroup(report)
+		}
+	}
+
+	if propagate {
+		updated, err := updateXlsxTexts(inputPath, outputPath, updates)
+		if err != nil {
+			return err
+		}
+		if !jsonOutput {
+			fmt.Printf("updated %d rows in %s; %d groups have no majority translation, use --choose for them\n", updated, outputPath, unresolved)
+		}
+	}
+
+	return nil
+}
+
+func printDedupeGroup(report dedupeGroupReport) {
+	ids := make([]string, len(report.Ids))
+	for i, id := range report.Ids {
+		ids[i] = fmt.Sprintf("#%d", id)
+	}
+	fmt.Printf("%s: %q\n", strings.Join(ids, ", "), report.Source)
+
+	width := len(fmt.Sprintf("%d", report.Ids[len(report.Ids)-1]))
+	for _, id := range report.Ids {
+		fmt.Printf("  #%-*d %s\n", width, id, report.Translations[id])
+	}
+	for _, source := range slices.Sorted(maps.Keys(report.Propagated)) {
+		if len(report.Propagated) == 1 && source == report.Source {
+			fmt.Printf("  → %s\n", report.Propagated[source])
+		} else {
+			fmt.Printf("  %q → %s\n", source, report.Propagated[source])
+		}
+	}
+}
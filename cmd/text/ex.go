@@ -33,7 +33,27 @@ func NewExCommand() *cobra.Command {
 		Short:   "Export textual resources from the game as XLSX",
 		Long: `Export all textual resources or specific IDs from the game.
 Reads the texts from dialog.tlk file, and optionally extracts only specified
-text IDs (e.g., 1234, 5678).`,
+text IDs or ranges (e.g., 1234, 5678, 0..1000).
+
+Entries can be filtered by label, context type and resource name. Filters of
+different kinds are combined (all must match); repeated values of the same
+filter are alternatives. With --split-by the result is written to several
+workbooks, one per chunk, named after the output file.`,
+		Example: `  Export drink names from all stores:
+
+      sbt-inf text export --context-from sto --label "store drink" -o drinks.xlsx
+
+  Export texts of items from a range of strrefs:
+
+      sbt-inf text export --context-from itm --context ITEMS 10000..20000
+
+  Export texts used in AR02* areas and dialogs, one workbook per dialog:
+
+      sbt-inf text export --context-from are,dlg --resource "AR02*" --split-by dialog
+
+  Split the whole TLK into workbooks of 5000 entries:
+
+      sbt-inf text export --split-by size=5000`,
 		Args: cobra.MinimumNArgs(0),
 		RunE: runEx,
 	}
@@ -43,6 +63,10 @@ text IDs (e.g., 1234, 5678).`,
 	cmd.Flags().String("dlg-base-url", "", "base `URL` for dialog references (overrides config)")
 	cmd.Flags().StringSlice("context-from", []string{}, "load context from `types` of files. Use 'all' to include all types.\nUse 'bif types' command to see all types.")
 	cmd.Flags().String("timestamps-from", "", "CSV file `path` containing timestamps to include in the export")
+	cmd.Flags().StringSlice("label", []string{}, "export only entries with any of the `labels` (e.g. \"store drink\")")
+	cmd.Flags().StringSlice("context", []string{}, "export only entries with any of the context `types` (e.g. ITEMS, DIALOGS, \"WORLD MAPS\")")
+	cmd.Flags().StringSlice("resource", []string{}, "export only entries used in resources matching any of the glob `patterns` (e.g. \"AR02*\")")
+	cmd.Flags().String("split-by", "", "write one XLSX per chunk: `dialog`, label or size=N")
 
 	cmd.MarkFlagFilename("output", "xlsx")
 	cmd.MarkFlagFilename("timestamps-from", "csv")
@@ -58,6 +82,21 @@ func runEx(cmd *cobra.Command, args []string) error {
 	baseUrl, _ := config.ResolveDialogBaseUrl(cmd)
 	contextFrom, _ := cmd.Flags().GetStringSlice("context-from")
 	timestampsFrom, _ := cmd.Flags().GetString("timestamps-from")
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	contextNames, _ := cmd.Flags().GetStringSlice("context")
+	resourcePatterns, _ := cmd.Flags().GetStringSlice("resource")
+	splitBy, _ := cmd.Flags().GetString("split-by")
+
+	filter, err := buildEntryFilter(args, labels, contextNames, resourcePatterns)
+	if err != nil {
+		return err
+	}
+
+	splitOpts, err := text.ParseSplitOptions(splitBy)
+	if err != nil {
+		return err
+	}
+	splitOpts.Labels = labels
 
 	outputPath, _ := cmd.Flags().GetString("output")
 	if cmd.Flags().Changed("output") && !strings.HasSuffix(strings.ToLower(outputPath), ".xlsx") {
@@ -96,6 +135,69 @@ func runEx(cmd *cobra.Command, args []string) error {
 		fmt.Println("done.")
 	}
 
+	loadContext(collection, fs.NewInfinityFs(keyPath), contextFrom, baseUrl, verbose)
+
+	ids := collection.Filter(filter)
+	if len(ids) == 0 {
+		return fmt.Errorf("no entries match the given filters")
+	}
+
+	// Load timestamps from CSV if provided
+	var timestamps map[uint32]int64
+	if timestampsFrom != "" {
+		if verbose {
+			fmt.Print("loading timestamps from CSV... ")
+		}
+
+		timestampEntries, err := loadTimestamps(timestampsFrom)
+		if err != nil {
+			return err
+		}
+
+		if verbose {
+			fmt.Printf("done (%d entries).\n", len(timestampEntries))
+		}
+
+		// Validate and extract timestamps
+		timestamps = make(map[uint32]int64)
+		for id, entry := range timestampEntries {
+			timestamps[id] = entry.Timestamp
+			if _, ok := collection.Entries[id]; !ok {
+				fmt.Printf("warning: CSV contains ID %d which is not in the TLK file\n", id)
+			}
+		}
+
+		// Check only the exported entries against the CSV
+		for _, id := range ids {
+			entry, ok := timestampEntries[id]
+			if !ok {
+				fmt.Printf("warning: ID %d not found in timestamps CSV\n", id)
+			} else if tlkText := collection.Entries[id].Text; tlkText != entry.Text {
+				fmt.Printf("warning: ID %d text mismatch - TLK: %q, CSV: %q\n", id, tlkText, entry.Text)
+			}
+		}
+	}
+
+	for _, chunk := range collection.Split(ids, splitOpts) {
+		chunkPath := text.ChunkFileName(outputPath, chunk.Name)
+		if verbose {
+			fmt.Printf("writing %s (%d entries)... ", chunkPath, len(chunk.Ids))
+		}
+		err = collection.ExportEntriesToXlsx(chunkPath, chunk.Ids, timestamps)
+		if err != nil {
+			return err
+		}
+		if verbose {
+			fmt.Println("done.")
+		}
+	}
+
+	return nil
+}
+
+// Loads context from the given types of game files ('all' for every supported type)
+// and fills the context known for fixed strrefs.
+func loadContext(collection *text.TextCollection, infFs afero.Fs, contextFrom []string, baseUrl string, verbose bool) {
 	contextTypes := []fs.FileType{
 		fs.FileType_2DA,
 		fs.FileType_ARE,
@@ -113,62 +215,60 @@ func runEx(cmd *cobra.Command, args []string) error {
 		contextTypes = lo.UniqMap(contextFrom, utils.Iteratee(fs.FileTypeFromExtension))
 	}
 
-	infFs := fs.NewInfinityFs(keyPath)
-
 	for _, t := range contextTypes {
 		switch t {
 		case fs.FileType_2DA:
-			err = process2daFiles(collection, infFs, verbose)
+			err := process2daFiles(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process 2DA files:", err)
 			}
 		case fs.FileType_ARE:
-			err = processAreas(collection, infFs, verbose)
+			err := processAreas(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process areas:", err)
 			}
 		case fs.FileType_CHU:
-			err = processUiScreens(collection, infFs, verbose)
+			err := processUiScreens(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process UI screens:", err)
 			}
 		case fs.FileType_CRE:
-			err = processCreatures(collection, infFs, verbose)
+			err := processCreatures(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process creatures:", err)
 			}
 		case fs.FileType_DLG:
-			err = processDialogs(collection, infFs, baseUrl, verbose)
+			err := processDialogs(collection, infFs, baseUrl, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process dialogs:", err)
 			}
 		case fs.FileType_EFF:
-			err = processEffects(collection, infFs, verbose)
+			err := processEffects(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process effects:", err)
 			}
 		case fs.FileType_ITM:
-			err = processItems(collection, infFs, verbose)
+			err := processItems(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process items:", err)
 			}
 		case fs.FileType_PRO:
-			err = processProjectiles(collection, infFs, verbose)
+			err := processProjectiles(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process projectiles:", err)
 			}
 		case fs.FileType_SPL:
-			err = processSpells(collection, infFs, verbose)
+			err := processSpells(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process spells:", err)
 			}
 		case fs.FileType_STO:
-			err = processStores(collection, infFs, verbose)
+			err := processStores(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process stores:", err)
 			}
 		case fs.FileType_WMP:
-			err = processWorldMaps(collection, infFs, verbose)
+			err := processWorldMaps(collection, infFs, verbose)
 			if err != nil {
 				fmt.Println("warning: unable to process world maps:", err)
 			}
@@ -178,55 +278,42 @@ func runEx(cmd *cobra.Command, args []string) error {
 	}
 
 	collection.FillKnownContext()
+}
 
-	// Load timestamps from CSV if provided
-	var timestamps map[uint32]int64
-	if timestampsFrom != "" {
-		if verbose {
-			fmt.Print("loading timestamps from CSV... ")
-		}
-
-		timestampEntries, err := loadTimestamps(timestampsFrom)
-		if err != nil {
-			return err
-		}
-
-		if verbose {
-			fmt.Printf("done (%d entries).\n", len(timestampEntries))
-		}
-
-		// Validate and extract timestamps
-		timestamps = make(map[uint32]int64)
-		csvIds := make(map[uint32]struct{})
+// Builds the entry filter from the export arguments and flags.
+// Returns nil if no filtering is requested.
+func buildEntryFilter(idArgs, labels, contextNames, resourcePatterns []string) (*text.EntryFilter, error) {
+	if len(idArgs) == 0 && len(labels) == 0 && len(contextNames) == 0 && len(resourcePatterns) == 0 {
+		return nil, nil
+	}
 
-		for id, entry := range timestampEntries {
-			csvIds[id] = struct{}{}
-			timestamps[id] = entry.Timestamp
+	filter := &text.EntryFilter{Labels: labels}
 
-			// Check if ID exists in collection and text matches
-			if tlkEntry, ok := collection.Entries[id]; ok {
-				if tlkEntry.Text != entry.Text {
-					fmt.Printf("warning: ID %d text mismatch - TLK: %q, CSV: %q\n", id, tlkEntry.Text, entry.Text)
-				}
-			} else {
-				fmt.Printf("warning: CSV contains ID %d which is not in the TLK file\n", id)
-			}
+	if len(idArgs) > 0 {
+		ids, _, err := splitIds(idArgs)
+		if err != nil {
+			return nil, err
 		}
+		filter.Ids = lo.Map(ids, func(id int, _ int) uint32 { return uint32(id) })
+	}
 
-		// Check for IDs in collection that are not in CSV
-		for id := range collection.Entries {
-			if _, ok := csvIds[id]; !ok {
-				fmt.Printf("warning: ID %d not found in timestamps CSV\n", id)
-			}
+	for _, name := range contextNames {
+		t, err := text.ContextTypeFromString(name)
+		if err != nil {
+			return nil, err
 		}
+		filter.Contexts = append(filter.Contexts, t)
 	}
 
-	err = collection.ExportToXlsx(outputPath, timestamps)
-	if err != nil {
-		return err
+	for _, pattern := range resourcePatterns {
+		f := fs.CompileFilter(pattern, false, false, true)
+		if f == nil {
+			return nil, fmt.Errorf("invalid resource pattern %q", pattern)
+		}
+		filter.Resources = append(filter.Resources, f)
 	}
 
-	return nil
+	return filter, nil
 }
 
 func processDialogs(collection *text.TextCollection, infFs afero.Fs, baseUrl string, verbose bool) error {
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/samber/lo"
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/sbtlocalization/sbt-infinity/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+func NewFitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fit",
+		Short: "Find UI texts overflowing their labels",
+		Long: `Measure translated UI texts with the game fonts and compare them with the
+size of the CHU label controls displaying them.
+
+The text is wrapped at spaces to the width of the label, the same way the game
+does. A text overflows if a word is wider than the label or the wrapped lines
+are higher than it. Male and female variants (separated with --separator, as
+in 'text import') are checked separately.
+
+With --png a preview of every reported label is rendered: the label box is
+drawn green if the text fits and red if it overflows.`,
+		Example: `  Check UI texts of a translated TLK:
+
+      sbt-inf text fit -i lang/uk_UA/dialog.tlk
+
+  Check a translation for a classic game and render previews:
+
+      sbt-inf text fit -i dialog.xlsx --encoding windows-1251 --png previews`,
+		Args: cobra.NoArgs,
+		RunE: runFit,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file`")
+	cmd.Flags().StringP("encoding", "e", "windows-1252", "code `page` of the translation for BAM fonts and TLK files of the classic games")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants")
+	cmd.Flags().String("png", "", "render PNG previews of the reported labels into `directory`")
+	cmd.Flags().BoolP("all", "a", false, "report all labels, not only overflowing ones")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+	cmd.MarkFlagDirname("png")
+
+	return cmd
+}
+
+type fitReport struct {
+	Strref    uint32 `json:"strref"`
+	Resource  string `json:"resource"`
+	Window    uint16 `json:"window"`
+	Control   int16  `json:"control"`
+	Font      string `json:"font"`
+	Text      string `json:"text"`
+	BoxWidth  int    `json:"box_width"`
+	BoxHeight int    `json:"box_height"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Overflows bool   `json:"overflows"`
+	Png       string `json:"png,omitempty"`
+}
+
+func runFit(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	encodingName, _ := cmd.Flags().GetString("encoding")
+	separator, _ := cmd.Flags().GetString("separator")
+	pngDir, _ := cmd.Flags().GetString("png")
+	all, _ := cmd.Flags().GetBool("all")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	codePage, err := ianaindex.IANA.Encoding(encodingName)
+	if err != nil || codePage == nil {
+		return fmt.Errorf("unsupported encoding %q", encodingName)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+	infFs := fs.NewInfinityFs(keyPath)
+
+	var labels []text.UiLabel
+	err = processFiles(infFs, verbose, "CHU", "UI screens", func(filename string, stream *kaitai.Stream) error {
+		chu := p.NewChu()
+		if err := chu.Read(stream, nil, chu); err != nil {
+			return err
+		}
+		chuLabels, err := text.UiLabelsFromChu(filename, chu)
+		labels = append(labels, chuLabels...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fontNames := lo.Uniq(lo.Map(labels, func(label text.UiLabel, _ int) string { return label.Font }))
+	gameFonts, err := findGameFonts(infFs, fontNames, verbose)
+	if err != nil {
+		return err
+	}
+
+	faces := make(map[string]text.FontFace)
+	for _, gf := range gameFonts {
+		var face text.FontFace
+		if gf.isTtf() {
+			face, err = text.NewTtfFace(gf.File, gf.Data, gf.Size)
+		} else {
+			face, err = text.NewBamFace(gf.File, gf.Data, codePage)
+		}
+		if err != nil {
+			fmt.Println("warning:", err)
+			continue
+		}
+		faces[gf.Resref] = face
+	}
+
+	translations, err := loadTranslationsInCodePage(inputPath, codePage)
+	if err != nil {
+		return err
+	}
+
+	if pngDir != "" {
+		if err := os.MkdirAll(pngDir, 0755); err != nil {
+			return fmt.Errorf("unable to create directory for previews: %w", err)
+		}
+	}
+
+	missingFonts := make(map[string]struct{})
+	checked, overflowing := 0, 0
+
+	for _, label := range labels {
+		translation, ok := translations[label.Strref]
+		if !ok || strings.TrimSpace(translation) == "" {
+			continue
+		}
+
+		face, ok := faces[label.Font]
+		if !ok {
+			if _, warned := missingFonts[label.Font]; !warned {
+				fmt.Printf("warning: font %s is not found, skipping its labels\n", label.Font)
+				missingFonts[label.Font] = struct{}{}
+			}
+			continue
+		}
+
+		variants := []string{translation}
+		if male, female, hasSplit := utils.SplitMaleFemaleText(translation, separator); hasSplit {
+			variants = lo.Uniq([]string{male, female})
+		}
+
+		for i, variant := range variants {
+			checked++
+			fit := text.FitText(face, variant, label.Width, label.Height)
+			if fit.Overflows() {
+				overflowing++
+			} else if !all {
+				continue
+			}
+
+			report := fitReport{
+				Strref:    label.Strref,
+				Resource:  label.Resource,
+				Window:    label.Window,
+				Control:   label.Control,
+				Font:      label.Font,
+				Text:      variant,
+				BoxWidth:  label.Width,
+				BoxHeight: label.Height,
+				Width:     fit.Width,
+				Height:    fit.Height,
+				Overflows: fit.Overflows(),
+			}
+
+			if pngDir != "" {
+				name := fmt.Sprintf("%d-%s-%d-%d", label.Strref, strings.TrimSuffix(label.Resource, ".CHU"), label.Window, label.Control)
+				if i > 0 {
+					name += "-f"
+				}
+				report.Png = filepath.Join(pngDir, name+".png")
+				if err := writePng(report.Png, face, fit); err != nil {
+					return err
+				}
+			}
+
+			printFitReport(report, jsonOutput)
+		}
+	}
+
+	if !jsonOutput {
+		fmt.Printf("%d of %d UI texts overflow their labels\n", overflowing, checked)
+	}
+
+	return nil
+}
+
+func writePng(path string, face text.FontFace, fit *text.TextFit) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create preview: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, text.RenderFit(face, fit)); err != nil {
+		return fmt.Errorf("unable to write preview %s: %w", path, err)
+	}
+	return nil
+}
+
+func printFitReport(report fitReport, jsonOutput bool) {
+	if jsonOutput {
+		jsonData, _ := json.Marshal(report)
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	status := "fits"
+	if report.Overflows {
+		status = "overflows"
+	}
+	fmt.Printf("#%d %s window %d → control %d (%s, %dx%d): text %dx%d %s\n",
+		report.Strref, report.Resource, report.Window, report.Control, report.Font,
+		report.BoxWidth, report.BoxHeight, report.Width, report.Height, status)
+	fmt.Printf("  %q\n", report.Text)
+	if report.Png != "" {
+		fmt.Printf("  preview: %s\n", report.Png)
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/afero"
+)
+
+// BAM fonts checked in the classic games when no --font is given.
+var classicBamFonts = []string{"NORMAL", "REALMS", "STONESML", "STONEBIG", "TOOLFONT", "FLOATTXT", "INITIALS"}
+
+// Pixel size of TTF fonts without PX_SIZE in FONTS.2DA.
+const defaultFontSize = 14
+
+// gameFont is a font resource used by the game under the name Resref.
+type gameFont struct {
+	Resref string
+	File   string
+	Data   []byte
+	Size   float64
+}
+
+func (f gameFont) isTtf() bool {
+	return strings.HasSuffix(f.File, ".TTF")
+}
+
+// Finds the fonts of the game. For the Enhanced Editions these are TTF fonts
+// referenced in FONTS.2DA, matched by resource or family name. For the classic
+// games these are the BAM fonts with given names.
+func findGameFonts(infFs afero.Fs, bamNames []string, verbose bool) ([]gameFont, error) {
+	file, err := infFs.Open("FONTS.2DA")
+	if err != nil {
+		if verbose {
+			fmt.Println("FONTS.2DA not found, using BAM fonts")
+		}
+		var fonts []gameFont
+		for _, name := range bamNames {
+			data, err := readResource(infFs, name+".BAM")
+			if err != nil {
+				if verbose {
+					fmt.Printf("warning: unable to read %s.BAM: %v\n", name, err)
+				}
+				continue
+			}
+			fonts = append(fonts, gameFont{Resref: name, File: name + ".BAM", Data: data})
+		}
+		return fonts, nil
+	}
+
+	twoda, err := p.ParseTwoDA(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse FONTS.2DA: %w", err)
+	}
+
+	// TTF resources by upper-case resource name and by family name without spaces.
+	ttfData := make(map[string][]byte)
+	ttfByKey := make(map[string]string)
+	if dir, err := infFs.Open("TTF"); err == nil {
+		names, _ := dir.Readdirnames(0)
+		dir.Close()
+		for _, name := range names {
+			data, err := readResource(infFs, name)
+			if err != nil {
+				fmt.Printf("warning: unable to read %s: %v\n", name, err)
+				continue
+			}
+			name = strings.ToUpper(name)
+			ttfData[name] = data
+			ttfByKey[fontKey(strings.TrimSuffix(name, ".TTF"))] = name
+			if family, err := text.TtfFamilyName(data); err == nil {
+				ttfByKey[fontKey(family)] = name
+			}
+		}
+	}
+
+	var fonts []gameFont
+	for _, row := range twoda.RowKeys {
+		resref := strings.ToUpper(twoda.GetOrDefault(row, "RESREF"))
+		fontName := twoda.GetOrDefault(row, "FONT_NAME")
+
+		name, ok := ttfByKey[fontKey(fontName)]
+		if !ok {
+			name, ok = ttfByKey[fontKey(resref)]
+		}
+		if !ok {
+			fmt.Printf("warning: TTF font %q for %s not found\n", fontName, resref)
+			continue
+		}
+
+		size, err := strconv.ParseFloat(twoda.GetOrDefault(row, "PX_SIZE"), 64)
+		if err != nil || size <= 0 {
+			size = defaultFontSize
+		}
+
+		fonts = append(fonts, gameFont{Resref: resref, File: name, Data: ttfData[name], Size: size})
+	}
+
+	return fonts, nil
+}
+
+func readResource(infFs afero.Fs, name string) ([]byte, error) {
+	file, err := infFs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// Normalizes a font name for matching: "Noto Serif" and "NOTOSERIF" are the same font.
+func fontKey(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.ReplaceAll(name, " ", ""), "-", ""))
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+// Game files holding the names of each term type.
+var glossaryFileTypes = map[string]string{
+	"creature": "cre",
+	"item":     "itm",
+	"spell":    "spl",
+	"area":     "wmp",
+	"store":    "sto",
+}
+
+func NewGlossaryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "glossary",
+		Short: "Export names of creatures, items, spells, areas and stores as a term base",
+		Long: `Export names used by the game as a term base, so they can be translated
+consistently everywhere:
+
+  - creature: long and short names from CRE files;
+  - item: identified and unidentified names from ITM files;
+  - spell: identified and unidentified names from SPL files;
+  - area: area captions and tooltips from WMP files;
+  - store: store names from STO files.
+
+Strrefs with the same text and type are merged into one term. Every term
+lists its resrefs, strrefs and the number of occurrences.
+
+The output format is selected by the extension: XLSX or TBX (TBX-Basic).
+Without --output the terms are printed. With --input the target terms are
+pre-filled from the translation (XLSX from 'text export' or a TLK file);
+different translations of the same term are all listed.`,
+		Example: `  Export the glossary of the game to XLSX:
+
+      sbt-inf text glossary -o glossary.xlsx
+
+  Export names of items and spells with the Ukrainian translation to TBX:
+
+      sbt-inf text glossary --types item,spell -i lang/uk_UA/dialog.tlk --target-lang uk_UA -o glossary.tbx`,
+		Args: cobra.NoArgs,
+		RunE: runGlossary,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "output XLSX or TBX `file`")
+	cmd.Flags().StringSlice("types", text.GlossaryTypes, "term `types` to export: "+strings.Join(text.GlossaryTypes, ", "))
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file` to pre-fill the target terms")
+	cmd.Flags().String("target-lang", "", "language `code` of the translation for TBX, e.g. uk_UA")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagFilename("output", "xlsx", "tbx")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+
+	return cmd
+}
+
+func runGlossary(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	types, _ := cmd.Flags().GetStringSlice("types")
+	inputPath, _ := cmd.Flags().GetString("input")
+	targetLang, _ := cmd.Flags().GetString("target-lang")
+	sourceLang, _ := cmd.Flags().GetString("lang")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	ext := strings.ToLower(filepath.Ext(outputPath))
+	if outputPath != "" && ext != ".xlsx" && ext != ".tbx" {
+		return fmt.Errorf("output file must be an xlsx or tbx file: %s", outputPath)
+	}
+	if ext == ".tbx" && inputPath != "" && targetLang == "" {
+		return fmt.Errorf("--target-lang is required to write translations to TBX")
+	}
+
+	var contextFrom []string
+	for _, termType := range types {
+		fileType, ok := glossaryFileTypes[termType]
+		if !ok {
+			return fmt.Errorf("unknown term type %q (valid: %s)", termType, strings.Join(text.GlossaryTypes, ", "))
+		}
+		contextFrom = append(contextFrom, fileType)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return err
+	}
+	collection := text.NewTextCollection(tlkFile.Tlk)
+	tlkFile.Close()
+
+	var translations map[uint32]string
+	if inputPath != "" {
+		translations, err = loadTranslations(inputPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	loadContext(collection, fs.NewInfinityFs(keyPath), contextFrom, "", verbose)
+
+	terms, err := collection.Glossary(types, translations)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case ext == ".xlsx":
+		if err := text.WriteGlossaryXlsx(outputPath, terms); err != nil {
+			return err
+		}
+	case ext == ".tbx":
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("unable to create output file: %w", err)
+		}
+		defer file.Close()
+		if err := text.WriteGlossaryTbx(file, terms, sourceLang, targetLang); err != nil {
+			return err
+		}
+	case jsonOutput:
+		for _, term := range terms {
+			jsonData, _ := json.Marshal(term)
+			fmt.Println(string(jsonData))
+		}
+		return nil
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tSOURCE\tTARGET\tOCCURRENCES\tRESREFS")
+		for _, term := range terms {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", term.Type, term.Source,
+				strings.Join(term.Targets, " | "), term.Occurrences, strings.Join(term.Resrefs, ", "))
+		}
+		return w.Flush()
+	}
+
+	if verbose {
+		fmt.Printf("Wrote %d terms to %s\n", len(terms), outputPath)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/sbtlocalization/sbt-infinity/utils"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+func NewGlyphsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "glyphs",
+		Short: "Find characters the game fonts cannot render",
+		Long: `Collect every character used in a translation and check it against the
+glyphs available in the game fonts.
+
+For the Enhanced Editions the TTF fonts referenced in FONTS.2DA are checked.
+A font is matched by its resource name or by its family name. For the classic
+games the BAM fonts are checked; as BAM fonts are indexed by bytes, the code
+page of the translation must be given with --encoding. TLK files of the
+classic games are decoded from the same code page.
+
+Fonts can also be given explicitly with --font, either as game resources
+(e.g. NORMAL.BAM) or as paths to TTF and BAM files on disk.`,
+		Example: `  Check a translated TLK against the fonts of an Enhanced Edition:
+
+      sbt-inf text glyphs -i lang/uk_UA/dialog.tlk
+
+  Check a translation for a classic game with Cyrillic fonts:
+
+      sbt-inf text glyphs -i dialog.xlsx --encoding windows-1251
+
+  Check against a custom font:
+
+      sbt-inf text glyphs -i dialog.xlsx --font ./fonts/NotoSerif-Regular.ttf`,
+		Args: cobra.NoArgs,
+		RunE: runGlyphs,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file`")
+	cmd.Flags().StringSlice("font", []string{}, "font `resources` or files to check instead of the game fonts")
+	cmd.Flags().StringP("encoding", "e", "windows-1252", "code `page` of the translation for BAM fonts and TLK files of the classic games")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants")
+	cmd.Flags().Int("max-ids", 10, "list at most `N` strrefs per character (0 for all)")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+
+	return cmd
+}
+
+type missingGlyphReport struct {
+	Char  string   `json:"char"`
+	Code  string   `json:"code"`
+	Fonts []string `json:"fonts"`
+	Ids   []uint32 `json:"ids"`
+}
+
+func runGlyphs(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	fontNames, _ := cmd.Flags().GetStringSlice("font")
+	encodingName, _ := cmd.Flags().GetString("encoding")
+	separator, _ := cmd.Flags().GetString("separator")
+	maxIds, _ := cmd.Flags().GetInt("max-ids")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	codePage, err := ianaindex.IANA.Encoding(encodingName)
+	if err != nil || codePage == nil {
+		return fmt.Errorf("unsupported encoding %q", encodingName)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+	infFs := fs.NewInfinityFs(keyPath)
+
+	var fonts []text.Font
+	if len(fontNames) > 0 {
+		fonts, err = loadFonts(infFs, fontNames, codePage)
+	} else {
+		fonts, err = loadGameFonts(infFs, codePage, verbose)
+	}
+	if err != nil {
+		return err
+	}
+	if len(fonts) == 0 {
+		return fmt.Errorf("no fonts found, use --font to specify them")
+	}
+
+	translations, err := loadTranslationsInCodePage(inputPath, codePage)
+	if err != nil {
+		return err
+	}
+	for id, translation := range translations {
+		male, female, _ := utils.SplitMaleFemaleText(translation, separator)
+		translations[id] = male + "\n" + female
+	}
+
+	missing := text.FindMissingGlyphs(translations, fonts)
+
+	if verbose && !jsonOutput {
+		fmt.Printf("checked %d fonts:", len(fonts))
+		for _, font := range fonts {
+			fmt.Printf(" %s", font.Name())
+		}
+		fmt.Println()
+	}
+
+	for _, glyph := range missing {
+		report := missingGlyphReport{
+			Char:  string(glyph.Rune),
+			Code:  fmt.Sprintf("U+%04X", glyph.Rune),
+			Fonts: glyph.Fonts,
+			Ids:   glyph.Ids,
+		}
+
+		if jsonOutput {
+			jsonData, _ := json.Marshal(report)
+			fmt.Println(string(jsonData))
+			continue
+		}
+
+		ids := make([]string, 0, len(report.Ids))
+		for i, id := range report.Ids {
+			if maxIds > 0 && i == maxIds {
+				ids = append(ids, fmt.Sprintf("... (%d in total)", len(report.Ids)))
+				break
+			}
+			ids = append(ids, fmt.Sprintf("#%d", id))
+		}
+		fmt.Printf("%s %q missing in %s\n  used in %s\n", report.Code, report.Char, strings.Join(report.Fonts, ", "), strings.Join(ids, ", "))
+	}
+
+	if !jsonOutput && len(missing) == 0 {
+		fmt.Println("all characters are covered by the fonts")
+	}
+
+	return nil
+}
+
+// Loads fonts referenced in FONTS.2DA, or the BAM fonts if the game has no FONTS.2DA.
+func loadGameFonts(infFs afero.Fs, codePage encoding.Encoding, verbose bool) ([]text.Font, error) {
+	gameFonts, err := findGameFonts(infFs, classicBamFonts, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	var fonts []text.Font
+	loaded := make(map[string]struct{})
+	for _, gf := range gameFonts {
+		if _, ok := loaded[gf.File]; ok {
+			continue
+		}
+		loaded[gf.File] = struct{}{}
+
+		var font text.Font
+		if gf.isTtf() {
+			font, err = text.ParseTtfFont(gf.File, gf.Data)
+		} else {
+			font, err = text.ParseBamFont(gf.File, gf.Data, codePage)
+		}
+		if err != nil {
+			fmt.Println("warning:", err)
+			continue
+		}
+		fonts = append(fonts, font)
+	}
+
+	return fonts, nil
+}
+
+// Loads fonts given as game resources or as files on disk.
+func loadFonts(infFs afero.Fs, names []string, codePage encoding.Encoding) ([]text.Font, error) {
+	var fonts []text.Font
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			data, err = readResource(infFs, strings.ToUpper(name))
+			if err != nil {
+				return nil, fmt.Errorf("font %s not found on disk nor in the game", name)
+			}
+		}
+
+		var font text.Font
+		switch strings.ToUpper(filepath.Ext(name)) {
+		case ".TTF":
+			font, err = text.ParseTtfFont(filepath.Base(name), data)
+		case ".BAM":
+			font, err = text.ParseBamFont(filepath.Base(name), data, codePage)
+		default:
+			err = fmt.Errorf("font %s must be a TTF or BAM file", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, font)
+	}
+	return fonts, nil
+}
@@ -7,14 +7,19 @@ package text
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"codeberg.org/tealeg/xlsx/v4"
 	"github.com/samber/lo"
+	"github.com/sbtlocalization/sbt-infinity/config"
 	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/sbtlocalization/sbt-infinity/typography"
 	"github.com/sbtlocalization/sbt-infinity/utils"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -34,72 +39,122 @@ func NewImportCommand() *cobra.Command {
 		Use:     "import",
 		Aliases: []string{"im"},
 		Short:   "Import XLSX file to TLK files",
-		Long: `Import an XLSX file (produced by 'text export') to TLK files.
+		Long: `Import XLSX files (produced by 'text export') to TLK files.
 
 The command creates dialog.tlk and optionally dialogf.tlk (for feminine text)
-in the specified output directory.`,
+in the specified output directory. Several input files (e.g. chunks written by
+'text export --split-by') are merged; an entry present in more than one file
+must have the same content everywhere.
+
+With --base the command patches existing TLK files instead of building them
+from scratch: only the entries present in the input are replaced, all others
+are kept as they are, and entries beyond the end of the base file are appended.
+If dialogf.tlk exists next to the base file, it is patched as well. An entry
+is reported as conflicting when the input has a single text for it, but the
+base dialogf.tlk contains a distinct female variant; such variants are kept.
+
+With --normalize the typography of the imported texts is normalized first
+(see 'text normalize'), and every modified entry is reported.`,
 		Example: `  Import dialog.xlsx to TLK files:
-    sbt-inf text import --input dialog.xlsx --output ./lang/en_US/`,
+    sbt-inf text import --input dialog.xlsx --output ./lang/en_US/
+
+  Import several chunks at once:
+    sbt-inf text import -i dialog-0.xlsx -i dialog-1.xlsx --output ./lang/en_US/
+
+  Apply one translator's chunk over the existing TLK files:
+    sbt-inf text import -i chunk.xlsx --base ./lang/uk_UA/dialog.tlk --output ./lang/uk_UA/`,
 		Args: cobra.NoArgs,
 		RunE: runImport,
 	}
 
-	cmd.Flags().StringP("input", "i", "", "input XLSX `file` path")
+	cmd.Flags().StringSliceP("input", "i", []string{}, "input XLSX `files` (repeat the flag or separate with commas)")
 	cmd.Flags().StringP("output", "o", "", "output `directory` path (writes dialog.tlk and dialogf.tlk)")
 	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants")
 	cmd.Flags().Uint16("lang-code", 0, "language code for TLK header")
 	cmd.Flags().Uint32P("max-entry", "n", 0, "import entries from 0 to `N`; if not set, all entries are imported")
+	cmd.Flags().StringP("base", "b", "", "existing dialog.tlk `file` to patch instead of building TLK files from scratch")
+	cmd.Flags().Bool("normalize", false, "normalize typography of the imported texts")
 	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
 
 	cmd.MarkFlagRequired("input")
 	cmd.MarkFlagRequired("output")
 	cmd.MarkFlagFilename("input", "xlsx")
+	cmd.MarkFlagFilename("base", "tlk")
 	cmd.MarkFlagDirname("output")
+	cmd.MarkFlagsMutuallyExclusive("base", "max-entry")
 
 	return cmd
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
-	inputPath, _ := cmd.Flags().GetString("input")
+	inputPaths, _ := cmd.Flags().GetStringSlice("input")
 	outputPath, _ := cmd.Flags().GetString("output")
 	separator, _ := cmd.Flags().GetString("separator")
 	langCode, _ := cmd.Flags().GetUint16("lang-code")
 	tillEntry, _ := cmd.Flags().GetUint32("max-entry")
+	basePath, _ := cmd.Flags().GetString("base")
+	normalize, _ := cmd.Flags().GetBool("normalize")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
-	if !strings.HasSuffix(strings.ToLower(inputPath), ".xlsx") {
-		return fmt.Errorf("input file must be an xlsx file: %s", inputPath)
-	}
-
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		return fmt.Errorf("input file does not exist: %s", inputPath)
-	}
-
-	if verbose {
-		fmt.Printf("Reading XLSX file: %s\n", inputPath)
-	}
-
-	rows, err := parseXlsxForTlk(inputPath)
+	rows, err := readXlsxFilesForTlk(inputPaths, verbose)
 	if err != nil {
-		return fmt.Errorf("failed to parse XLSX file: %w", err)
+		return err
 	}
 
 	if len(rows) == 0 {
 		return fmt.Errorf("input file contains no data rows")
 	}
 
+	if normalize {
+		normalizer, err := config.ResolveNormalizer(cmd)
+		if err != nil {
+			return err
+		}
+		var changes []typography.Change
+		for i := range rows {
+			if change, ok := normalizer.Apply(rows[i].Key, &rows[i].Text, separator); ok {
+				changes = append(changes, change)
+			}
+		}
+		typography.PrintChanges(os.Stdout, changes, "#")
+	}
+
 	if verbose {
 		fmt.Printf("Found %d entries\n", len(rows))
 	}
 
-	var maxEntry *uint32
-	if tillEntry != 0 {
-		maxEntry = &tillEntry
-	}
+	var maleEntries, femaleEntries []text.TlkWriteEntry
+	var hasFemale bool
+	opts := text.TlkWriteOptions{Lang: langCode}
+
+	if basePath != "" {
+		if verbose {
+			fmt.Printf("Reading base TLK files: %s, %s\n", basePath, text.FemaleTlkPath(basePath))
+		}
+		baseMale, baseFemale, baseLang, err := text.ReadTlkWriteEntries(afero.NewOsFs(), basePath)
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("lang-code") {
+			opts.Lang = baseLang
+		}
 
-	maleEntries, femaleEntries, hasFemale := buildTlkEntries(rows, separator, maxEntry)
+		var maleStats, femaleStats text.TlkPatchStats
+		maleEntries, femaleEntries, maleStats, femaleStats = text.PatchTlkEntries(baseMale, baseFemale, tlkPatches(rows, separator))
+		hasFemale = femaleEntries != nil
 
-	opts := text.TlkWriteOptions{Lang: langCode}
+		printTlkPatchStats("dialog.tlk", maleStats)
+		if hasFemale {
+			printTlkPatchStats("dialogf.tlk", femaleStats)
+		}
+	} else {
+		var maxEntry *uint32
+		if tillEntry != 0 {
+			maxEntry = &tillEntry
+		}
+
+		maleEntries, femaleEntries, hasFemale = buildTlkEntries(rows, separator, maxEntry)
+	}
 
 	if err := os.MkdirAll(outputPath, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -130,6 +185,47 @@ func runImport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Reads and merges rows of several XLSX files, sorted by key.
+// The same key may appear in several files only with identical content.
+func readXlsxFilesForTlk(paths []string, verbose bool) ([]xlsxTlkRow, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no input files given")
+	}
+
+	merged := make(map[uint32]xlsxTlkRow)
+	sources := make(map[uint32]string)
+
+	for _, path := range paths {
+		if !strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+			return nil, fmt.Errorf("input file must be an xlsx file: %s", path)
+		}
+
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("input file does not exist: %s", path)
+		}
+
+		if verbose {
+			fmt.Printf("Reading XLSX file: %s\n", path)
+		}
+
+		rows, err := parseXlsxForTlk(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XLSX file %s: %w", path, err)
+		}
+
+		for _, row := range rows {
+			if existing, ok := merged[row.Key]; ok && existing != row {
+				return nil, fmt.Errorf("entry %d differs in %s and %s", row.Key, sources[row.Key], path)
+			}
+			merged[row.Key] = row
+			sources[row.Key] = path
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(merged))
+	return lo.Map(keys, func(key uint32, _ int) xlsxTlkRow { return merged[key] }), nil
+}
+
 func parseXlsxForTlk(path string) ([]xlsxTlkRow, error) {
 	xlsxFile, err := xlsx.OpenFile(path)
 	if err != nil {
@@ -272,33 +368,56 @@ func buildTlkEntries(rows []xlsxTlkRow, separator string, maxEntry *uint32) ([]t
 		if row.Key > maxKey {
 			break
 		}
-		maleText, femaleText, hasSplit := utils.SplitMaleFemaleText(row.Text, separator)
+		male, female, hasSplit := row.toTlkEntries(separator)
 		if hasSplit {
 			hasFemale = true
-		} else {
-			femaleText = maleText // Same text for both if no split
-		}
-
-		maleEntries[row.Key] = text.TlkWriteEntry{
-			Text:           maleText,
-			HasText:        row.HasText,
-			HasSound:       row.HasSound,
-			HasToken:       row.HasToken,
-			AudioName:      row.SoundFile,
-			VolumeVariance: row.VolumeVariance,
-			PitchVariance:  row.PitchVariance,
 		}
 
-		femaleEntries[row.Key] = text.TlkWriteEntry{
-			Text:           femaleText,
-			HasText:        row.HasText,
-			HasSound:       row.HasSound,
-			HasToken:       row.HasToken,
-			AudioName:      row.SoundFile,
-			VolumeVariance: row.VolumeVariance,
-			PitchVariance:  row.PitchVariance,
-		}
+		maleEntries[row.Key] = male
+		femaleEntries[row.Key] = female
 	}
 
 	return maleEntries, femaleEntries, hasFemale
 }
+
+// Converts the row to male and female TLK entries. If the text has no
+// female variant, both entries have the same text.
+func (row xlsxTlkRow) toTlkEntries(separator string) (text.TlkWriteEntry, text.TlkWriteEntry, bool) {
+	maleText, femaleText, hasSplit := utils.SplitMaleFemaleText(row.Text, separator)
+	if !hasSplit {
+		femaleText = maleText // Same text for both if no split
+	}
+
+	male := text.TlkWriteEntry{
+		Text:           maleText,
+		HasText:        row.HasText,
+		HasSound:       row.HasSound,
+		HasToken:       row.HasToken,
+		AudioName:      row.SoundFile,
+		VolumeVariance: row.VolumeVariance,
+		PitchVariance:  row.PitchVariance,
+	}
+
+	female := male
+	female.Text = femaleText
+
+	return male, female, hasSplit
+}
+
+func printTlkPatchStats(name string, s text.TlkPatchStats) {
+	fmt.Printf("%s: %d applied, %d unchanged, %d appended, %d conflicting\n",
+		name, s.Applied, s.Unchanged, s.Appended, len(s.Conflicting))
+	for _, id := range s.Conflicting {
+		fmt.Printf("  conflict: entry %d has a female variant in the base file, but not in the input; kept the base variant\n", id)
+	}
+}
+
+// Converts the rows to patches of TLK entries.
+func tlkPatches(rows []xlsxTlkRow, separator string) []text.TlkPatch {
+	patches := make([]text.TlkPatch, 0, len(rows))
+	for _, row := range rows {
+		male, female, hasSplit := row.toTlkEntries(separator)
+		patches = append(patches, text.TlkPatch{Key: row.Key, Male: male, Female: female, HasFemale: hasSplit})
+	}
+	return patches
+}
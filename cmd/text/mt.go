@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"codeberg.org/tealeg/xlsx/v4"
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/mt"
+	"github.com/spf13/cobra"
+)
+
+// Status of rows pre-filled with machine translation.
+const mtStatus = "MT"
+
+func NewMtCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mt [ID...]",
+		Short: "Pre-fill untranslated XLSX rows with machine translation",
+		Long: `Fill untranslated rows of an XLSX file (produced by 'text export') with
+machine translation, e.g. as a first draft of low-priority strings.
+
+A row is untranslated if its text is the same as in the game TLK and it has
+no status yet. Rows can be limited to IDs and to labels of the 'labels'
+column (e.g. --label "store drink").
+
+The translation is requested from a LibreTranslate-compatible HTTP API; set
+its address with --url or mt_url in the config file, and the key (if the
+server needs one) with --api-key or mt_api_key. Engine tokens like <CHARNAME>
+and color tags are replaced with placeholders before sending and restored
+afterwards; a row whose placeholders were damaged is left untranslated and
+reported. Male and female variants are translated separately.
+
+Every pre-filled row gets the "MT" status in the 'status' column (added if
+missing), so it is never mistaken for reviewed text.`,
+		Example: `  Pre-fill store drinks and tracking messages with a local LibreTranslate:
+
+      sbt-inf text mt -i dialog.xlsx -o dialog-mt.xlsx --target-lang uk \
+          --url http://localhost:5000 --label "store drink" --label tracking.2da`,
+		Args: cobra.MinimumNArgs(0),
+		RunE: runMt,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "input XLSX `file`")
+	cmd.Flags().StringP("output", "o", "", "output XLSX `file`")
+	cmd.Flags().String("url", "", "base `URL` of the LibreTranslate API (overrides config)")
+	cmd.Flags().String("api-key", "", "API `key` (overrides config)")
+	cmd.Flags().String("source-lang", "en", "source language `code` for the provider")
+	cmd.Flags().String("target-lang", "", "target language `code` for the provider, e.g. uk")
+	cmd.Flags().StringSlice("label", []string{}, "translate only rows with one of the `labels`")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants")
+	cmd.Flags().Int("batch", 20, "number of texts sent in one `request`")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagRequired("target-lang")
+	cmd.MarkFlagFilename("input", "xlsx")
+	cmd.MarkFlagFilename("output", "xlsx")
+
+	return cmd
+}
+
+func runMt(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	outputPath, _ := cmd.Flags().GetString("output")
+	url, _ := cmd.Flags().GetString("url")
+	apiKey, _ := cmd.Flags().GetString("api-key")
+	sourceLang, _ := cmd.Flags().GetString("source-lang")
+	targetLang, _ := cmd.Flags().GetString("target-lang")
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	separator, _ := cmd.Flags().GetString("separator")
+	batchSize, _ := cmd.Flags().GetInt("batch")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	gameConfig, err := config.ResolveGameConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if url == "" {
+		url = gameConfig.MtUrl
+	}
+	if url == "" {
+		return fmt.Errorf("no translation server given: use --url or mt_url in the config")
+	}
+	if apiKey == "" {
+		apiKey = gameConfig.MtApiKey
+	}
+
+	idArgs, _, err := splitIds(args)
+	if err != nil {
+		return err
+	}
+
+	texts, err := loadTranslations(inputPath)
+	if err != nil {
+		return err
+	}
+	columns, err := readXlsxColumns(inputPath, "labels", "status")
+	if err != nil {
+		return err
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return err
+	}
+	sources := tlkTexts(tlkFile.Tlk)
+	tlkFile.Close()
+
+	// Male and female variants of every selected row, in order.
+	var ids []uint32
+	var parts []string
+	var partCounts []int
+	for _, id := range slices.Sorted(maps.Keys(texts)) {
+		text := texts[id]
+		rowLabels, status := columns[id][0], columns[id][1]
+
+		if strings.TrimSpace(text) == "" || text != sources[id] || status != "" {
+			continue
+		}
+		if _, found := slices.BinarySearch(idArgs, int(id)); len(idArgs) > 0 && !found {
+			continue
+		}
+		if len(labels) > 0 && !slices.ContainsFunc(strings.Split(rowLabels, ","), func(label string) bool {
+			return slices.Contains(labels, label)
+		}) {
+			continue
+		}
+
+		variants := strings.Split(text, separator)
+		ids = append(ids, id)
+		parts = append(parts, variants...)
+		partCounts = append(partCounts, len(variants))
+	}
+
+	if verbose {
+		fmt.Printf("Translating %d rows (%d texts) with %s\n", len(ids), len(parts), url)
+	}
+
+	results, err := mt.TranslateTexts(cmd.Context(), mt.NewLibreTranslate(url, apiKey), parts, sourceLang, targetLang, batchSize)
+	if err != nil {
+		return err
+	}
+
+	translated := make(map[uint32]string)
+	offset := 0
+	for i, id := range ids {
+		rowResults := results[offset : offset+partCounts[i]]
+		offset += partCounts[i]
+
+		variants := make([]string, 0, len(rowResults))
+		var rowErr error
+		for _, result := range rowResults {
+			if result.Err != nil {
+				rowErr = result.Err
+				break
+			}
+			variants = append(variants, result.Text)
+		}
+		if rowErr != nil {
+			fmt.Printf("warning: #%d left untranslated: %v\n", id, rowErr)
+			continue
+		}
+		translated[id] = strings.Join(variants, separator)
+	}
+
+	updated, err := updateXlsxRows(inputPath, outputPath, []string{"status"}, func(key uint32, textCell *xlsx.Cell, row *xlsx.Row, extra []int) bool {
+		text, ok := translated[key]
+		if !ok {
+			return false
+		}
+		textCell.SetString(text)
+		row.GetCell(extra[0]).SetString(mtStatus)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d rows pre-filled with machine translation, %d failed\n", updated, len(ids)-len(translated))
+	return nil
+}
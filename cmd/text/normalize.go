@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/sbtlocalization/sbt-infinity/typography"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func NewNormalizeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "normalize",
+		Short: "Normalize typography of translated texts",
+		Long: `Normalize typography of a translation (XLSX from 'text export', TLK or TRA file):
+
+  - collapse repeated spaces and drop trailing ones;
+  - replace three dots with an ellipsis;
+  - replace spaced hyphens with em dashes and hyphens in number ranges with en dashes;
+  - pair quotes using the quote style of the language (e.g. «…» and „…“ for uk);
+  - replace straight apostrophes inside words with typographic ones;
+  - bind short words and dashes to the next word with non-breaking spaces (off by default);
+  - fix Latin letters in Cyrillic words and the other way around.
+
+The language is taken from the [<game>.typography] section of the config file,
+or from --lang if given; without a known language quotes and short words are
+left as is. Each rule can be turned off in the same section. Engine tokens like
+<CHARNAME> and dice like 1d6-1 are never changed.
+The same rules are applied by 'text import --normalize' and 'tra update --normalize'.
+
+Every modified entry is reported with the applied rules. Words mixing scripts
+that can not be fixed automatically are reported as well. Without --output
+the input file is not changed.`,
+		Example: `  Show what would be changed in a Ukrainian TLK:
+
+      sbt-inf text normalize -l uk_UA -i lang/uk_UA/dialog.tlk
+
+  Normalize an XLSX translation:
+
+      sbt-inf text normalize -l uk_UA -i dialog.xlsx -o dialog-normalized.xlsx`,
+		Args: cobra.NoArgs,
+		RunE: runNormalize,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX, TLK or TRA `file`")
+	cmd.Flags().StringP("output", "o", "", "output `file` of the same type as the input")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants in XLSX")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk", "tra")
+	cmd.MarkFlagFilename("output", "xlsx", "tlk", "tra")
+
+	return cmd
+}
+
+func runNormalize(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	outputPath, _ := cmd.Flags().GetString("output")
+	separator, _ := cmd.Flags().GetString("separator")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	ext := strings.ToLower(filepath.Ext(inputPath))
+	if outputPath != "" && !strings.EqualFold(filepath.Ext(outputPath), ext) {
+		return fmt.Errorf("output file must have the same type as the input: %s", outputPath)
+	}
+
+	normalizer, err := config.ResolveNormalizer(cmd)
+	if err != nil {
+		return err
+	}
+
+	var changes []typography.Change
+	idPrefix := "#"
+
+	switch ext {
+	case ".xlsx":
+		texts, err := loadTranslations(inputPath)
+		if err != nil {
+			return err
+		}
+		changes = normalizer.NormalizeAll(texts, separator)
+		if outputPath != "" {
+			updated, err := updateXlsxTexts(inputPath, outputPath, texts)
+			if err != nil {
+				return err
+			}
+			if verbose {
+				fmt.Printf("Updated %d rows in %s\n", updated, outputPath)
+			}
+		}
+	case ".tlk":
+		changes, err = normalizeTlkFile(normalizer, inputPath, outputPath)
+		if err != nil {
+			return err
+		}
+	case ".tra":
+		idPrefix = "@"
+		changes, err = normalizeTraFile(normalizer, inputPath, outputPath)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("input file must be an xlsx, tlk or tra file: %s", inputPath)
+	}
+
+	if jsonOutput {
+		for _, change := range changes {
+			jsonData, _ := json.Marshal(change)
+			fmt.Println(string(jsonData))
+		}
+		return nil
+	}
+
+	typography.PrintChanges(os.Stdout, changes, idPrefix)
+	modified, mixed := 0, 0
+	for _, change := range changes {
+		if len(change.Rules) > 0 {
+			modified++
+		}
+		if len(change.Warnings) > 0 {
+			mixed++
+		}
+	}
+	fmt.Printf("%d texts modified, %d with unresolved mixed scripts\n", modified, mixed)
+
+	return nil
+}
+
+func normalizeTlkFile(normalizer *typography.Normalizer, inputPath, outputPath string) ([]typography.Change, error) {
+	tlkFile, err := p.ReadTlkFile(afero.NewOsFs(), inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer tlkFile.Close()
+
+	entries, err := text.TlkWriteEntriesFromTlk(tlkFile.Tlk)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make(map[uint32]string, len(entries))
+	for i, entry := range entries {
+		if entry.Text != "" {
+			texts[uint32(i)] = entry.Text
+		}
+	}
+	changes := normalizer.NormalizeAll(texts, "")
+
+	if outputPath == "" {
+		return changes, nil
+	}
+	for id, s := range texts {
+		entries[id].Text = s
+	}
+	if err := text.WriteTlkFile(outputPath, entries, text.TlkWriteOptions{Lang: tlkFile.Lang}); err != nil {
+		return nil, fmt.Errorf("failed to write TLK file: %w", err)
+	}
+	return changes, nil
+}
+
+// Normalizes male and female texts of a TRA file. A change of the female
+// text is reported under the same ID as the male one.
+func normalizeTraFile(normalizer *typography.Normalizer, inputPath, outputPath string) ([]typography.Change, error) {
+	traFile, err := p.ParseTraFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TRA file: %w", err)
+	}
+
+	var changes []typography.Change
+	for i := range traFile.Entries {
+		entry := &traFile.Entries[i]
+		for _, s := range []*string{&entry.MaleText, &entry.FemaleText} {
+			if change, ok := normalizer.Apply(entry.ID, s, ""); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	if outputPath != "" {
+		if err := tra.WriteParsedFile(outputPath, traFile); err != nil {
+			return nil, fmt.Errorf("failed to write TRA file: %w", err)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/spell"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewSpellcheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spellcheck",
+		Short: "Check spelling of translated texts with Hunspell dictionaries",
+		Long: `Check spelling of a translation (XLSX from 'text export', TLK or TRA file)
+with a local Hunspell dictionary (.aff and .dic files, e.g. from LibreOffice).
+
+Engine tokens like <CHARNAME>, color tags and words with digits are skipped.
+Names of creatures, items, spells, places and stores are collected from the
+game (CRE, ITM, SPL, WMP and STO files) and accepted as known words. This is
+on by default when a game is configured; disable it with --names=false to
+avoid loading the game files.
+
+The names are taken from the translation itself if it is indexed by strrefs.
+TRA files have no strrefs, so for them the names are taken from the translated
+game TLK selected with --lang or --tlk (e.g. -l uk_UA); without it names are
+not loaded.
+
+Additional words can be given in custom dictionaries: one word per line,
+optionally with Hunspell flags after a slash. The dictionary and a custom
+dictionary can be set per game in the config file (spell_dictionary and
+custom_dictionary).
+
+Misspelled words are grouped and ordered by the number of entries using them.`,
+		Example: `  Check a translated TLK with the Ukrainian dictionary:
+
+      sbt-inf text spellcheck -i lang/uk_UA/dialog.tlk -d dict/uk_UA
+
+  Check a TRA file of a mod with a project dictionary:
+
+      sbt-inf text spellcheck -i setup.tra -d dict/uk_UA --custom words.dic
+
+  Check a TRA file of a mod, accepting names of the translated game:
+
+      sbt-inf text spellcheck -i setup.tra -d dict/uk_UA -l uk_UA`,
+		Args: cobra.NoArgs,
+		RunE: runSpellcheck,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX, TLK or TRA `file`")
+	cmd.Flags().StringP("dictionary", "d", "", "`path` to the Hunspell dictionary without extension (overrides config)")
+	cmd.Flags().StringSlice("custom", []string{}, "custom dictionary `files` (added to the one from config)")
+	cmd.Flags().Bool("names", false, "accept names of creatures, items, spells, places and stores from the game\n(default: on if a game is configured and, for TRA files, --lang or --tlk is given)")
+	cmd.Flags().Int("max-ids", 10, "list at most `N` entries per word (0 for all)")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk", "tra")
+	cmd.MarkFlagFilename("dictionary", "aff", "dic")
+	cmd.MarkFlagFilename("custom", "dic", "txt")
+
+	return cmd
+}
+
+type misspelledWord struct {
+	Word string   `json:"word"`
+	Ids  []uint32 `json:"ids"`
+}
+
+func runSpellcheck(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	dictPath, _ := cmd.Flags().GetString("dictionary")
+	customPaths, _ := cmd.Flags().GetStringSlice("custom")
+	withNames, _ := cmd.Flags().GetBool("names")
+	maxIds, _ := cmd.Flags().GetInt("max-ids")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	gameConfig, err := config.ResolveGameConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if dictPath == "" {
+		dictPath = gameConfig.SpellDictionary
+	}
+	if dictPath == "" {
+		return fmt.Errorf("no dictionary given: use --dictionary or spell_dictionary in the config")
+	}
+	if gameConfig.CustomDictionary != "" {
+		customPaths = append([]string{gameConfig.CustomDictionary}, customPaths...)
+	}
+
+	dictPath = strings.TrimSuffix(strings.TrimSuffix(dictPath, ".aff"), ".dic")
+	if verbose {
+		fmt.Printf("loading dictionary %s... ", dictPath)
+	}
+	dict, err := spell.Load(dictPath+".aff", dictPath+".dic")
+	if err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Println("done.")
+	}
+
+	for _, path := range customPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open custom dictionary: %w", err)
+		}
+		err = dict.LoadPersonal(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read custom dictionary %s: %w", path, err)
+		}
+	}
+
+	isTra := strings.EqualFold(filepath.Ext(inputPath), ".tra")
+	texts, err := loadSpellcheckTexts(inputPath, isTra)
+	if err != nil {
+		return err
+	}
+
+	hasTlk := cmd.Flags().Changed("lang") || cmd.Flags().Changed("tlk")
+	if !cmd.Flags().Changed("names") {
+		_, err := config.ResolveKeyPath(cmd)
+		withNames = err == nil && (!isTra || hasTlk)
+	} else if withNames && isTra && !hasTlk {
+		return fmt.Errorf("names for TRA files are taken from the translated game TLK: select it with --lang or --tlk")
+	}
+
+	if withNames {
+		names, err := loadNames(cmd, texts, isTra, verbose)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			dict.AddWord(name)
+		}
+	}
+
+	misspelled := make(map[string][]uint32)
+	for _, id := range slices.Sorted(maps.Keys(texts)) {
+		seen := make(map[string]struct{})
+		for _, word := range spell.Words(texts[id]) {
+			if _, ok := seen[word]; ok {
+				continue
+			}
+			seen[word] = struct{}{}
+			if !dict.Check(word) {
+				misspelled[word] = append(misspelled[word], id)
+			}
+		}
+	}
+
+	words := make([]misspelledWord, 0, len(misspelled))
+	for word, ids := range misspelled {
+		words = append(words, misspelledWord{Word: word, Ids: ids})
+	}
+	slices.SortFunc(words, func(a, b misspelledWord) int {
+		if c := cmp.Compare(len(b.Ids), len(a.Ids)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Word, b.Word)
+	})
+
+	idPrefix := "#"
+	if isTra {
+		idPrefix = "@"
+	}
+
+	for _, word := range words {
+		if jsonOutput {
+			jsonData, _ := json.Marshal(word)
+			fmt.Println(string(jsonData))
+			continue
+		}
+
+		ids := make([]string, 0, len(word.Ids))
+		for i, id := range word.Ids {
+			if maxIds > 0 && i == maxIds {
+				ids = append(ids, "...")
+				break
+			}
+			ids = append(ids, fmt.Sprintf("%s%d", idPrefix, id))
+		}
+		fmt.Printf("%s (%d): %s\n", word.Word, len(word.Ids), strings.Join(ids, ", "))
+	}
+
+	if !jsonOutput {
+		fmt.Printf("%d misspelled words in %d entries\n", len(words), len(texts))
+	}
+
+	return nil
+}
+
+// Loads texts to check. Male and female variants of TRA entries are joined
+// with a line break.
+func loadSpellcheckTexts(path string, isTra bool) (map[uint32]string, error) {
+	if !isTra {
+		return loadTranslations(path)
+	}
+
+	tra, err := p.ParseTraFile(path)
+	if err != nil {
+		return nil, err
+	}
+	texts := make(map[uint32]string, len(tra.Entries))
+	for _, entry := range tra.Entries {
+		texts[entry.ID] = strings.TrimSpace(entry.MaleText + "\n" + entry.FemaleText)
+	}
+	return texts, nil
+}
+
+// Collects capitalized words of names of the glossary types. The names are
+// taken from the translation if it is indexed by strrefs, or from the TLK
+// selected with --lang or --tlk for TRA files.
+func loadNames(cmd *cobra.Command, texts map[uint32]string, isTra, verbose bool) ([]string, error) {
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return nil, err
+	}
+	collection := text.NewTextCollection(tlkFile.Tlk)
+	tlkFile.Close()
+
+	loadContext(collection, fs.NewInfinityFs(keyPath), []string{"cre", "itm", "spl", "sto", "wmp"}, "", verbose)
+
+	var names []string
+	for _, id := range collection.NameIds() {
+		name := collection.Entries[id].Text
+		if !isTra {
+			name = texts[id]
+		}
+		for _, word := range spell.Words(name) {
+			if r, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(r) {
+				names = append(names, word)
+			}
+		}
+	}
+	return names, nil
+}
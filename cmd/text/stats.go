@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats [ID...]",
+		Short: "Show translation progress and word counts",
+		Long: `Count entries, words and characters of the game texts per label, per dialog
+file and per context type. Labels and context are collected from the game
+files the same way as in 'text export'.
+
+With --input the translation (XLSX from 'text export' or a TLK file) is
+compared with the source TLK: an entry is translated if its translation is
+not empty and differs from the source text. The entries, words and
+characters left to translate are shown for every row; the percentage is
+calculated from words.
+
+Strrefs used in several categories are counted once in each of them and
+once in the total.`,
+		Example: `  Show statistics for the whole game:
+
+      sbt-inf text stats
+
+  Show progress of the translation, using context from dialogs only:
+
+      sbt-inf text stats -i uk_UA/dialog.tlk --context-from dlg --by dialog`,
+		Args: cobra.MinimumNArgs(0),
+		RunE: runStats,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file`")
+	cmd.Flags().StringSlice("context-from", []string{"all"}, "load context from `types` of files. Use 'all' to include all types.\nUse 'bif types' command to see all types.")
+	cmd.Flags().StringSlice("by", []string{"label", "dialog", "context"}, "`categories` to show: label, dialog, context")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+
+	return cmd
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	contextFrom, _ := cmd.Flags().GetStringSlice("context-from")
+	by, _ := cmd.Flags().GetStringSlice("by")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	for _, category := range by {
+		if !slices.Contains([]string{"label", "dialog", "context"}, category) {
+			return fmt.Errorf("unknown category %q (valid: label, dialog, context)", category)
+		}
+	}
+
+	filter, err := buildEntryFilter(args, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return err
+	}
+	collection := text.NewTextCollection(tlkFile.Tlk)
+	tlkFile.Close()
+
+	var translations map[uint32]string
+	if inputPath != "" {
+		translations, err = loadTranslations(inputPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	loadContext(collection, fs.NewInfinityFs(keyPath), contextFrom, "", verbose)
+
+	stats := collection.Stats(collection.Filter(filter), translations)
+
+	if jsonOutput {
+		if !slices.Contains(by, "label") {
+			stats.ByLabel = nil
+		}
+		if !slices.Contains(by, "dialog") {
+			stats.ByDialog = nil
+		}
+		if !slices.Contains(by, "context") {
+			stats.ByContext = nil
+		}
+		jsonData, _ := json.MarshalIndent(stats, "", "  ")
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printStatsRow(w, "", nil, translations != nil)
+	for _, category := range by {
+		switch category {
+		case "label":
+			printStatsSection(w, "LABELS", stats.ByLabel, translations != nil)
+		case "dialog":
+			printStatsSection(w, "DIALOGS", stats.ByDialog, translations != nil)
+		case "context":
+			printStatsSection(w, "CONTEXT", stats.ByContext, translations != nil)
+		}
+	}
+	fmt.Fprintln(w, "\t")
+	printStatsRow(w, "TOTAL", &stats.Total, translations != nil)
+	return w.Flush()
+}
+
+func printStatsSection(w *tabwriter.Writer, title string, counts map[string]*text.StatsCounts, withProgress bool) {
+	fmt.Fprintf(w, "\t\n%s\t\n", title)
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		printStatsRow(w, "  "+name, counts[name], withProgress)
+	}
+}
+
+// Prints a row of the statistics table, or the header if counts is nil.
+func printStatsRow(w *tabwriter.Writer, name string, counts *text.StatsCounts, withProgress bool) {
+	if counts == nil {
+		if withProgress {
+			fmt.Fprintln(w, "\tentries\twords\tchars\tleft entries\tleft words\tleft chars\ttranslated\t")
+		} else {
+			fmt.Fprintln(w, "\tentries\twords\tchars\t")
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t", name, counts.Entries, counts.Words, counts.Chars)
+	if withProgress {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%.1f%%\t", counts.LeftEntries, counts.LeftWords, counts.LeftChars, counts.Percent())
+	}
+	fmt.Fprintln(w)
+}
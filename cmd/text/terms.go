@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/cobra"
+)
+
+func NewTermsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terms",
+		Short: "Check that glossary terms are translated consistently",
+		Long: `Find entries whose source text contains a glossary term, but whose
+translation contains none of the approved target forms.
+
+The glossary is a TBX file or an XLSX file with 'source' and 'target' columns
+(e.g. produced by 'text glossary'); alternative targets in XLSX are separated
+with " | ". The translation is an XLSX file from 'text export' or a TLK file;
+source texts are read from the game TLK. Untranslated entries are skipped.
+
+Terms are matched as whole words. With --match:
+
+  exact  the case must match;
+  case   the case is ignored (default);
+  stem   the case is ignored and up to --stem-trim last letters of every
+         word may differ, which suits inflected languages.
+
+The report is grouped by term, terms with the most entries first.`,
+		Example: `  Check a Ukrainian translation against the project glossary:
+
+      sbt-inf text terms --glossary glossary.xlsx -i lang/uk_UA/dialog.tlk --match stem`,
+		Args: cobra.NoArgs,
+		RunE: runTerms,
+	}
+
+	cmd.Flags().String("glossary", "", "glossary TBX or XLSX `file`")
+	cmd.Flags().StringP("input", "i", "", "translation XLSX or TLK `file`")
+	cmd.Flags().String("match", "case", "term matching `mode`: exact, case, stem")
+	cmd.Flags().Int("stem-trim", 2, "number of last `letters` of a word that may differ in stem mode")
+	cmd.Flags().StringP("separator", "s", " // ", "separator for male/female text variants in XLSX")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+
+	cmd.MarkFlagRequired("glossary")
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagFilename("glossary", "tbx", "xlsx")
+	cmd.MarkFlagFilename("input", "xlsx", "tlk")
+
+	return cmd
+}
+
+func runTerms(cmd *cobra.Command, args []string) error {
+	glossaryPath, _ := cmd.Flags().GetString("glossary")
+	inputPath, _ := cmd.Flags().GetString("input")
+	match, _ := cmd.Flags().GetString("match")
+	stemTrim, _ := cmd.Flags().GetInt("stem-trim")
+	separator, _ := cmd.Flags().GetString("separator")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	mode, err := text.ParseMatchMode(match)
+	if err != nil {
+		return err
+	}
+	if stemTrim < 0 {
+		return fmt.Errorf("--stem-trim must not be negative")
+	}
+
+	glossary, err := loadGlossary(glossaryPath)
+	if err != nil {
+		return err
+	}
+
+	translations, err := loadTranslations(inputPath)
+	if err != nil {
+		return err
+	}
+
+	tlkFile, err := readSourceTlk(cmd)
+	if err != nil {
+		return err
+	}
+	sources := tlkTexts(tlkFile.Tlk)
+	tlkFile.Close()
+
+	if !strings.EqualFold(filepath.Ext(inputPath), ".xlsx") {
+		separator = ""
+	}
+
+	issues := text.CheckTerms(glossary, sources, translations, separator, text.TermMatcher{Mode: mode, StemTrim: stemTrim})
+
+	entries := 0
+	for _, issue := range issues {
+		entries += len(issue.Entries)
+
+		if jsonOutput {
+			jsonData, _ := json.Marshal(issue)
+			fmt.Println(string(jsonData))
+			continue
+		}
+
+		fmt.Printf("%s → %s (%d)\n", issue.Source, strings.Join(issue.Targets, " | "), len(issue.Entries))
+		for _, entry := range issue.Entries {
+			fmt.Printf("  #%d\n    - %q\n    + %q\n", entry.Id, entry.Source, entry.Translation)
+		}
+	}
+
+	if !jsonOutput {
+		fmt.Printf("%d terms translated inconsistently in %d entries\n", len(issues), entries)
+	}
+	return nil
+}
+
+// Loads a glossary from a TBX or XLSX file.
+func loadGlossary(path string) ([]text.GlossaryEntry, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return text.ReadGlossaryXlsx(path)
+	case ".tbx":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open glossary: %w", err)
+		}
+		defer file.Close()
+		return text.ReadGlossaryTbx(file)
+	default:
+		return nil, fmt.Errorf("glossary file must be a tbx or xlsx file: %s", path)
+	}
+}
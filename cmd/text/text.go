@@ -6,6 +6,11 @@
 package text
 
 import (
+	"path/filepath"
+
+	"github.com/sbtlocalization/sbt-infinity/config"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +34,39 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(NewExCommand())
 	cmd.AddCommand(NewImportCommand())
 	cmd.AddCommand(NewConvertCommand())
+	cmd.AddCommand(NewDedupeCommand())
+	cmd.AddCommand(NewStatsCommand())
+	cmd.AddCommand(NewGlyphsCommand())
+	cmd.AddCommand(NewFitCommand())
+	cmd.AddCommand(NewSpellcheckCommand())
+	cmd.AddCommand(NewNormalizeCommand())
+	cmd.AddCommand(NewGlossaryCommand())
+	cmd.AddCommand(NewTermsCommand())
+	cmd.AddCommand(NewMtCommand())
 
 	return cmd
 }
+
+// Reads the TLK file selected by the --tlk, --lang and --feminine flags.
+func readSourceTlk(cmd *cobra.Command) (*p.TlkFile, error) {
+	tlkPath, _ := cmd.Flags().GetString("tlk")
+	lang, _ := cmd.Flags().GetString("lang")
+	feminine, _ := cmd.Flags().GetBool("feminine")
+
+	if cmd.Flags().Changed("tlk") {
+		return p.ReadTlkFile(afero.NewOsFs(), tlkPath)
+	}
+
+	keyPath, err := config.ResolveKeyPath(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tlkFs := afero.NewBasePathFs(afero.NewOsFs(), filepath.Dir(keyPath))
+	if feminine {
+		tlkPath = filepath.Join("lang", lang, "dialogf.tlk")
+	} else {
+		tlkPath = filepath.Join("lang", lang, "dialog.tlk")
+	}
+	return p.ReadTlkFile(tlkFs, tlkPath)
+}
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"codeberg.org/tealeg/xlsx/v4"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/text"
+	"github.com/spf13/afero"
+	"golang.org/x/text/encoding"
+)
+
+// Returns texts of all TLK entries by strref.
+func tlkTexts(tlk *p.Tlk) map[uint32]string {
+	texts := make(map[uint32]string, len(tlk.Entries))
+	for i, entry := range tlk.Entries {
+		text, err := entry.Text()
+		if err != nil {
+			fmt.Printf("warning: unable to decode text for ID %d: %v\n", i, err)
+			continue
+		}
+		texts[uint32(i)] = text
+	}
+	return texts
+}
+
+// Loads translated texts by strref from an XLSX file (produced by 'text export')
+// or from a TLK file. Texts from XLSX keep male/female variants joined with the separator.
+func loadTranslations(path string) (map[uint32]string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("translation file does not exist: %s", path)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		rows, err := parseXlsxForTlk(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XLSX file: %w", err)
+		}
+		texts := make(map[uint32]string, len(rows))
+		for _, row := range rows {
+			texts[row.Key] = row.Text
+		}
+		return texts, nil
+	case ".tlk":
+		tlkFile, err := p.ReadTlkFile(afero.NewOsFs(), path)
+		if err != nil {
+			return nil, err
+		}
+		defer tlkFile.Close()
+		return tlkTexts(tlkFile.Tlk), nil
+	default:
+		return nil, fmt.Errorf("translation file must be an xlsx or tlk file: %s", path)
+	}
+}
+
+// Loads translated texts like loadTranslations. Texts of TLK files of the
+// classic games are decoded from the code page.
+func loadTranslationsInCodePage(path string, codePage encoding.Encoding) (map[uint32]string, error) {
+	texts, err := loadTranslations(path)
+	if err != nil || strings.ToLower(filepath.Ext(path)) != ".tlk" {
+		return texts, err
+	}
+	return text.DecodeTlkTexts(texts, codePage)
+}
+
+// Copies an XLSX file (produced by 'text export') replacing texts of the
+// given entries. Other cells are kept as is. Returns the number of updated rows.
+func updateXlsxTexts(inputPath, outputPath string, updates map[uint32]string) (int, error) {
+	return updateXlsxRows(inputPath, outputPath, nil, func(key uint32, textCell *xlsx.Cell, _ *xlsx.Row, _ []int) bool {
+		newText, ok := updates[key]
+		if !ok || textCell.Value == newText {
+			return false
+		}
+		textCell.SetString(newText)
+		return true
+	})
+}
+
+// Copies an XLSX file (produced by 'text export') calling update for every
+// data row. The callback returns true if it has changed the row. Extra columns
+// are added to the header if missing; their indices are passed to the callback
+// in the same order.
+func updateXlsxRows(
+	inputPath, outputPath string,
+	extraColumns []string,
+	update func(key uint32, textCell *xlsx.Cell, row *xlsx.Row, extra []int) bool,
+) (int, error) {
+	xlsxFile, err := xlsx.OpenFile(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open xlsx file: %w", err)
+	}
+
+	if len(xlsxFile.Sheets) == 0 {
+		return 0, fmt.Errorf("xlsx file has no sheets")
+	}
+	sheet := xlsxFile.Sheets[0]
+
+	headerRow, err := sheet.Row(0)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read header row: %w", err)
+	}
+
+	keyIdx, textIdx := -1, -1
+	extra := make([]int, len(extraColumns))
+	for i := range extra {
+		extra[i] = -1
+	}
+	colIdx := 0
+	headerRow.ForEachCell(func(cell *xlsx.Cell) error {
+		name := strings.ToLower(cell.Value)
+		switch name {
+		case "key":
+			keyIdx = colIdx
+		case "source or translation":
+			textIdx = colIdx
+		}
+		for i, column := range extraColumns {
+			if name == strings.ToLower(column) {
+				extra[i] = colIdx
+			}
+		}
+		colIdx++
+		return nil
+	})
+
+	if keyIdx == -1 {
+		return 0, fmt.Errorf("xlsx file missing required 'key' column")
+	}
+	if textIdx == -1 {
+		return 0, fmt.Errorf("xlsx file missing required 'source or translation' column")
+	}
+
+	nextCol := max(colIdx, sheet.MaxCol)
+	for i, column := range extraColumns {
+		if extra[i] == -1 {
+			extra[i] = nextCol
+			headerRow.GetCell(extra[i]).SetString(column)
+			nextCol++
+		}
+	}
+
+	updated := 0
+	for rowIdx := 1; rowIdx < sheet.MaxRow; rowIdx++ {
+		row, err := sheet.Row(rowIdx)
+		if err != nil {
+			return 0, fmt.Errorf("unable to read row %d: %w", rowIdx+1, err)
+		}
+
+		key, err := row.GetCell(keyIdx).Int64()
+		if err != nil {
+			continue
+		}
+
+		if update(uint32(key), row.GetCell(textIdx), row, extra) {
+			updated++
+		}
+	}
+
+	if err := xlsxFile.Save(outputPath); err != nil {
+		return 0, fmt.Errorf("failed to save xlsx file: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Reads values of the given columns of an XLSX file (produced by 'text export')
+// by key. Values of missing columns are empty.
+func readXlsxColumns(path string, columns ...string) (map[uint32][]string, error) {
+	values := make(map[uint32][]string)
+	indices := make([]int, len(columns))
+
+	xlsxFile, err := xlsx.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open xlsx file: %w", err)
+	}
+	if len(xlsxFile.Sheets) == 0 {
+		return nil, fmt.Errorf("xlsx file has no sheets")
+	}
+	sheet := xlsxFile.Sheets[0]
+
+	headerRow, err := sheet.Row(0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read header row: %w", err)
+	}
+
+	keyIdx := -1
+	for i := range indices {
+		indices[i] = -1
+	}
+	colIdx := 0
+	headerRow.ForEachCell(func(cell *xlsx.Cell) error {
+		name := strings.ToLower(cell.Value)
+		if name == "key" {
+			keyIdx = colIdx
+		}
+		for i, column := range columns {
+			if name == strings.ToLower(column) {
+				indices[i] = colIdx
+			}
+		}
+		colIdx++
+		return nil
+	})
+	if keyIdx == -1 {
+		return nil, fmt.Errorf("xlsx file missing required 'key' column")
+	}
+
+	for rowIdx := 1; rowIdx < sheet.MaxRow; rowIdx++ {
+		row, err := sheet.Row(rowIdx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read row %d: %w", rowIdx+1, err)
+		}
+		key, err := row.GetCell(keyIdx).Int64()
+		if err != nil {
+			continue
+		}
+
+		rowValues := make([]string, len(columns))
+		for i, idx := range indices {
+			if idx != -1 {
+				rowValues[i] = row.GetCell(idx).Value
+			}
+		}
+		values[uint32(key)] = rowValues
+	}
+
+	return values, nil
+}
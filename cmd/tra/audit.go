@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/spf13/cobra"
+)
+
+func NewAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit <mod-dir>",
+		Short: "Check TRA references of a WeiDU mod against its translations",
+		Long: `Scan .tp2, .tpa, .tph, .tpp, .d and .baf sources of a WeiDU mod for TRA
+references (@123) and include directives (LANGUAGE, LOAD_TRA, USING,
+WITH_TRA, AUTO_TRA), and cross-check them against every tra/<language>/
+folder.
+
+References of a .d or .baf file are looked up in the TRA files with the same
+name (the AUTO_TRA convention) if there are any, otherwise in all TRA files
+of the language.
+
+Reported issues:
+
+  missing          a referenced entry is absent in a language;
+  unused           an entry is not referenced by any source;
+  duplicate        an entry is defined more than once in a file;
+  female           the female variant exists only in one of the languages;
+  sound            the sound file differs from the base language;
+  missing-file     a TRA file of the base language is absent;
+  missing-include  a TRA path of an include directive does not exist.
+
+Include paths are resolved relative to the parent of the mod folder (as
+WeiDU does) or the mod folder itself; %s, %LANGUAGE% and %MOD_FOLDER% are
+substituted, paths with other variables are not checked.`,
+		Example: `  Audit a mod, comparing all languages with English:
+
+      sbt-inf tra audit mods/mymod
+
+  Only list missing entries:
+
+      sbt-inf tra audit mods/mymod --kind missing`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAudit,
+	}
+
+	cmd.Flags().String("tra-dir", "", "`folder` with language subfolders (default: first 'tra' folder of the mod)")
+	cmd.Flags().String("base", "", "base `language` folder (default: english or the first one)")
+	cmd.Flags().StringSlice("kind", []string{}, "report only issues of these `kinds`")
+	cmd.Flags().BoolP("json", "j", false, "output in JSON format")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagDirname("tra-dir")
+
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	traDir, _ := cmd.Flags().GetString("tra-dir")
+	baseLanguage, _ := cmd.Flags().GetString("base")
+	kinds, _ := cmd.Flags().GetStringSlice("kind")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+		return fmt.Errorf("mod folder does not exist: %s", args[0])
+	}
+
+	audit, err := tra.Audit(args[0], tra.AuditOptions{TraDir: traDir, BaseLanguage: baseLanguage})
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		refs := 0
+		for _, src := range audit.Sources {
+			refs += len(src.Refs)
+		}
+		fmt.Printf("Scanned %d sources with %d references\n", len(audit.Sources), refs)
+		for _, lang := range audit.Languages {
+			fmt.Printf("Language %s: %d TRA files\n", lang.Name, len(lang.Files))
+		}
+		fmt.Printf("Base language: %s\n", audit.Base)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	count := 0
+	for _, issue := range audit.Issues {
+		if len(kinds) > 0 && !slices.Contains(kinds, issue.Kind) {
+			continue
+		}
+		count++
+
+		if jsonOutput {
+			jsonData, _ := json.Marshal(issue)
+			fmt.Println(string(jsonData))
+			continue
+		}
+
+		location := issue.File
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+		}
+		id := ""
+		if issue.Id > 0 || issue.Kind != tra.IssueMissingFile && issue.Kind != tra.IssueMissingInclude {
+			id = fmt.Sprintf("@%d", issue.Id)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", issue.Kind, issue.Language, location, id, issue.Message)
+	}
+	w.Flush()
+
+	if !jsonOutput {
+		fmt.Printf("%d issues found\n", count)
+	}
+	return nil
+}
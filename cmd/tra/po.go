@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/spf13/cobra"
+)
+
+func NewPoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "po",
+		Short: "Convert TRA files to and from gettext PO",
+		Long: `Convert TRA files to gettext PO files for Poedit, Weblate and other
+translation tools, and back.
+
+Every TRA entry is a PO message with the TRA ID as msgctxt and the template
+(e.g. English) text as msgid. A female variant is a separate message with
+the "<ID> female" msgctxt. The sound file is a reference comment (#:).`,
+	}
+
+	cmd.AddCommand(NewPoExportCommand())
+	cmd.AddCommand(NewPoImportCommand())
+
+	return cmd
+}
+
+func NewPoExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Aliases: []string{"ex"},
+		Short:   "Export TRA file to PO or POT",
+		Long: `Export a template TRA file (e.g. the English one) to a PO file.
+
+Without --translation the msgstr fields are empty (a POT template); with it
+they are filled with the texts of the translated TRA file.
+
+With --all-female every entry gets a female message, even if the template
+has no female variant, which suits languages with grammatical gender.`,
+		Example: `  Create a template for translators:
+    sbt-inf tra po export -i tra/english/setup.tra -o setup.pot
+
+  Continue an existing Ukrainian translation in Poedit:
+    sbt-inf tra po export -i tra/english/setup.tra --translation tra/ukrainian/setup.tra \
+        --po-lang uk --all-female -o setup.uk.po`,
+		Args: cobra.NoArgs,
+		RunE: runPoExport,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "template TRA `file` path")
+	cmd.Flags().String("translation", "", "translated TRA `file` to fill msgstr")
+	cmd.Flags().StringP("output", "o", "", "output PO or POT `file` path")
+	cmd.Flags().String("po-lang", "", "`language` of the PO header, e.g. uk")
+	cmd.Flags().Bool("all-female", false, "add a female message for every entry")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("input", "tra")
+	cmd.MarkFlagFilename("translation", "tra")
+	cmd.MarkFlagFilename("output", "po", "pot")
+
+	return cmd
+}
+
+func NewPoImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import",
+		Aliases: []string{"im"},
+		Short:   "Import PO file to TRA file",
+		Long: `Import a translated PO file to a TRA file, using the template TRA file the
+PO file was exported from.
+
+The output keeps the comments, layout and sound files of the template; the
+translated texts are wrapped in delimiters they do not contain. Untranslated
+and fuzzy messages keep the template text. The import fails if the PO file
+has messages whose IDs are not in the template.`,
+		Example: `  Write the Ukrainian TRA file:
+    sbt-inf tra po import -i setup.uk.po --template tra/english/setup.tra -o tra/ukrainian/setup.tra`,
+		Args: cobra.NoArgs,
+		RunE: runPoImport,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "input PO `file` path")
+	cmd.Flags().String("template", "", "template TRA `file` path")
+	cmd.Flags().StringP("output", "o", "", "output TRA `file` path")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("template")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("input", "po")
+	cmd.MarkFlagFilename("template", "tra")
+	cmd.MarkFlagFilename("output", "tra")
+
+	return cmd
+}
+
+func runPoExport(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	translationPath, _ := cmd.Flags().GetString("translation")
+	outputPath, _ := cmd.Flags().GetString("output")
+	language, _ := cmd.Flags().GetString("po-lang")
+	allFemale, _ := cmd.Flags().GetBool("all-female")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	template, err := parser.ParseTraFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse TRA file: %w", err)
+	}
+
+	var translation *parser.TraFile
+	if translationPath != "" {
+		if translation, err = parser.ParseTraFile(translationPath); err != nil {
+			return fmt.Errorf("failed to parse translation TRA file: %w", err)
+		}
+	}
+
+	messages := tra.TraToPo(template, translation, allFemale)
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("unable to create output file: %w", err)
+	}
+	defer file.Close()
+
+	if err := tra.WritePo(file, filepath.Base(inputPath), language, messages); err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Wrote %d messages for %d entries to %s\n", len(messages), len(template.Entries), outputPath)
+	}
+	return nil
+}
+
+func runPoImport(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	templatePath, _ := cmd.Flags().GetString("template")
+	outputPath, _ := cmd.Flags().GetString("output")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if !strings.HasSuffix(strings.ToLower(outputPath), ".tra") {
+		outputPath = outputPath + ".tra"
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("unable to open PO file: %w", err)
+	}
+	defer file.Close()
+
+	messages, err := tra.ReadPo(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse PO file: %w", err)
+	}
+
+	template, err := parser.ParseTraFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse template TRA file: %w", err)
+	}
+
+	applied, err := tra.PoToTra(template, messages)
+	if err != nil {
+		return err
+	}
+
+	if err := tra.WriteParsedFile(outputPath, template); err != nil {
+		return fmt.Errorf("failed to write TRA file: %w", err)
+	}
+
+	if verbose {
+		fmt.Printf("Applied %d of %d messages, wrote %s\n", applied, len(messages), outputPath)
+	}
+	return nil
+}
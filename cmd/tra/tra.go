@@ -18,7 +18,11 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(NewExportCommand())
 	cmd.AddCommand(NewImportCommand())
+	cmd.AddCommand(NewExportWorkbookCommand())
+	cmd.AddCommand(NewImportWorkbookCommand())
 	cmd.AddCommand(NewUpdateCommand())
+	cmd.AddCommand(NewAuditCommand())
+	cmd.AddCommand(NewPoCommand())
 
 	return cmd
 }
@@ -12,8 +12,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/sbtlocalization/sbt-infinity/config"
 	"github.com/sbtlocalization/sbt-infinity/parser"
 	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/sbtlocalization/sbt-infinity/typography"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +26,14 @@ func NewUpdateCommand() *cobra.Command {
 		Long: `Update TRA file entries using CSV files (for example, output of 'csv diff').
 
 The CSV files must have 'id' and 'text' columns. Updates are matched by ID.
-At least one of --male-csv or --female-csv must be provided.`,
+At least one of --male-csv or --female-csv must be provided.
+
+The output keeps comments, blank lines, entry order, ID formatting and
+delimiters of the input file; only the texts that changed are re-written.
+
+With --normalize the typography of the updated texts is normalized using
+the [<game>.typography] section of the config file (see 'text normalize'),
+and every modified entry is reported.`,
 		Example: `  Update male texts:
     sbt-inf tra update -i dialog.tra -m male_diff.csv -o dialog_updated.tra
 
@@ -41,6 +50,7 @@ At least one of --male-csv or --female-csv must be provided.`,
 	cmd.Flags().StringP("output", "o", "", "output TRA `file` path")
 	cmd.Flags().StringP("male-csv", "m", "", "CSV `file` with male text updates")
 	cmd.Flags().StringP("female-csv", "f", "", "CSV `file` with female text updates")
+	cmd.Flags().Bool("normalize", false, "normalize typography of the updated texts")
 	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
 
 	cmd.MarkFlagRequired("input")
@@ -59,8 +69,17 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	outputPath, _ := cmd.Flags().GetString("output")
 	maleCsvPath, _ := cmd.Flags().GetString("male-csv")
 	femaleCsvPath, _ := cmd.Flags().GetString("female-csv")
+	normalize, _ := cmd.Flags().GetBool("normalize")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
+	var normalizer *typography.Normalizer
+	if normalize {
+		var err error
+		if normalizer, err = config.ResolveNormalizer(cmd); err != nil {
+			return err
+		}
+	}
+
 	if !strings.HasSuffix(strings.ToLower(inputPath), ".tra") {
 		return fmt.Errorf("input file must be a .tra file: %s", inputPath)
 	}
@@ -117,29 +136,42 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	maleCount, femaleCount := 0, 0
+	var changes []typography.Change
+	apply := func(id uint32, s *string, newText string) {
+		*s = newText
+		if normalizer == nil {
+			return
+		}
+		if change, ok := normalizer.Apply(id, s, ""); ok {
+			changes = append(changes, change)
+		}
+	}
+
 	for i := range traFile.Entries {
 		entry := &traFile.Entries[i]
 
 		if maleUpdates != nil {
 			if newText, ok := maleUpdates[entry.ID]; ok {
-				entry.MaleText = newText
+				apply(entry.ID, &entry.MaleText, newText)
 				maleCount++
 			}
 		}
 
 		if femaleUpdates != nil {
 			if newText, ok := femaleUpdates[entry.ID]; ok {
-				entry.FemaleText = newText
+				apply(entry.ID, &entry.FemaleText, newText)
 				femaleCount++
 			}
 		}
 	}
 
+	typography.PrintChanges(os.Stdout, changes, "@")
+
 	if verbose {
 		fmt.Printf("Applied %d male updates, %d female updates\n", maleCount, femaleCount)
 	}
 
-	err = tra.WriteFile(outputPath, traFile.Entries)
+	err = tra.WriteParsedFile(outputPath, traFile)
 	if err != nil {
 		return fmt.Errorf("failed to write TRA file: %w", err)
 	}
@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codeberg.org/tealeg/xlsx/v4"
+	"github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/tra"
+	"github.com/spf13/cobra"
+)
+
+// Fill of cells of entries missing in a language.
+const missingFillColor = "FFFFC7CE"
+
+func NewExportWorkbookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export-workbook",
+		Aliases: []string{"exw"},
+		Short:   "Export TRA files of all languages to one XLSX workbook",
+		Long: `Export TRA files of every language subfolder of a tra folder to one XLSX
+workbook.
+
+Every row is a TRA entry, identified by the 'File' (path relative to the
+language folder) and 'Key' (TRA ID) columns. Every language has three
+columns: '<language>' with the text, '<language> female' with the female
+variant and '<language> sound' with the sound file. Cells of entries missing
+in a language are highlighted.
+
+Use 'tra import-workbook' to write the TRA files back.`,
+		Example: `  Export all languages of a mod:
+    sbt-inf tra export-workbook -i mymod/tra -o mymod.xlsx
+
+  Export English and Ukrainian only, in this order:
+    sbt-inf tra export-workbook -i mymod/tra -o mymod.xlsx --langs english,ukrainian`,
+		Args: cobra.NoArgs,
+		RunE: runExportWorkbook,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "tra `folder` with language subfolders")
+	cmd.Flags().StringP("output", "o", "", "output XLSX `file` path")
+	cmd.Flags().StringSlice("langs", []string{}, "language `folders` to export, in column order (default: all)")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagDirname("input")
+	cmd.MarkFlagFilename("output", "xlsx")
+
+	return cmd
+}
+
+func NewImportWorkbookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import-workbook",
+		Aliases: []string{"imw"},
+		Short:   "Import XLSX workbook to TRA files of all languages",
+		Long: `Import an XLSX workbook (produced by 'tra export-workbook') to TRA files
+of every language column.
+
+If a TRA file exists in the original folder, its comments, layout and entries
+absent from the workbook are kept and only the changed texts are re-written;
+otherwise a new file is created. Existing files are matched regardless of the
+case of their paths and keep the path of their language. An entry with no text, female variant and
+sound file is not added to a language that does not have it.`,
+		Example: `  Update TRA files of a mod in place:
+    sbt-inf tra import-workbook -i mymod.xlsx -o mymod/tra
+
+  Write to another folder, using the mod files as the original:
+    sbt-inf tra import-workbook -i mymod.xlsx -o out/tra --original mymod/tra`,
+		Args: cobra.NoArgs,
+		RunE: runImportWorkbook,
+	}
+
+	cmd.Flags().StringP("input", "i", "", "input XLSX `file` path")
+	cmd.Flags().StringP("output", "o", "", "output tra `folder` with language subfolders")
+	cmd.Flags().String("original", "", "tra `folder` with the original files (default: the output folder)")
+	cmd.Flags().StringSlice("langs", []string{}, "language `columns` to import (default: all)")
+	cmd.Flags().BoolP("verbose", "v", false, "enable verbose output")
+
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("input", "xlsx")
+	cmd.MarkFlagDirname("output")
+	cmd.MarkFlagDirname("original")
+
+	return cmd
+}
+
+func runExportWorkbook(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	outputPath, _ := cmd.Flags().GetString("output")
+	langNames, _ := cmd.Flags().GetStringSlice("langs")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if !strings.HasSuffix(strings.ToLower(outputPath), ".xlsx") {
+		outputPath = outputPath + ".xlsx"
+	}
+
+	languages, err := tra.LoadLanguages(inputPath)
+	if err != nil {
+		return err
+	}
+	if len(langNames) > 0 {
+		var selected []*tra.TraLanguage
+		for _, name := range langNames {
+			idx := slices.IndexFunc(languages, func(lang *tra.TraLanguage) bool { return lang.Name == name })
+			if idx < 0 {
+				return fmt.Errorf("language folder not found: %s", name)
+			}
+			selected = append(selected, languages[idx])
+		}
+		languages = selected
+	}
+	if len(languages) == 0 {
+		return fmt.Errorf("no language folders in %s", inputPath)
+	}
+
+	if verbose {
+		for _, lang := range languages {
+			fmt.Printf("Language %s: %d TRA files\n", lang.Name, len(lang.Files))
+		}
+	}
+	rows := tra.WorkbookRows(languages)
+
+	xlsxFile := xlsx.NewFile()
+	sheet, err := xlsxFile.AddSheet("Strings")
+	if err != nil {
+		return fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	headerRow := sheet.AddRow()
+	headers := []string{"File", "Key"}
+	for _, lang := range languages {
+		headers = append(headers, lang.Name, lang.Name+" female", lang.Name+" sound")
+	}
+	for _, h := range headers {
+		headerRow.AddCell().SetValue(h)
+	}
+
+	missingStyle := xlsx.NewStyle()
+	missingStyle.Fill = *xlsx.NewFill(xlsx.Solid_Cell_Fill, missingFillColor, missingFillColor)
+	missingStyle.ApplyFill = true
+
+	incomplete := 0
+	for _, r := range rows {
+		row := sheet.AddRow()
+		fileCell := row.AddCell()
+		fileCell.SetValue(r.File)
+		keyCell := row.AddCell()
+		keyCell.SetInt64(int64(r.Id))
+
+		for _, entry := range r.Entries {
+			if entry == nil {
+				for range 3 {
+					row.AddCell().SetStyle(missingStyle)
+				}
+				continue
+			}
+			row.AddCell().SetValue(entry.MaleText)
+			row.AddCell().SetValue(entry.FemaleText)
+			row.AddCell().SetValue(entry.SoundFile)
+		}
+
+		if r.IsIncomplete() {
+			incomplete++
+			fileCell.SetStyle(missingStyle)
+			keyCell.SetStyle(missingStyle)
+		}
+	}
+
+	if err := xlsxFile.Save(outputPath); err != nil {
+		return fmt.Errorf("failed to save XLSX file: %w", err)
+	}
+
+	fmt.Printf("%d entries of %d languages exported, %d missing in some languages\n", len(rows), len(languages), incomplete)
+	return nil
+}
+
+// Text, female variant and sound file columns of a language.
+type workbookLanguage struct {
+	Name                         string
+	TextIdx, FemaleIdx, SoundIdx int
+}
+
+func runImportWorkbook(cmd *cobra.Command, args []string) error {
+	inputPath, _ := cmd.Flags().GetString("input")
+	outputPath, _ := cmd.Flags().GetString("output")
+	originalPath, _ := cmd.Flags().GetString("original")
+	langNames, _ := cmd.Flags().GetStringSlice("langs")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if originalPath == "" {
+		originalPath = outputPath
+	}
+
+	xlsxFile, err := xlsx.OpenFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("unable to open xlsx file: %w", err)
+	}
+	if len(xlsxFile.Sheets) == 0 {
+		return fmt.Errorf("xlsx file has no sheets")
+	}
+	sheet := xlsxFile.Sheets[0]
+
+	headerRow, err := sheet.Row(0)
+	if err != nil {
+		return fmt.Errorf("unable to read header row: %w", err)
+	}
+
+	fileIdx, keyIdx := -1, -1
+	var languages []*workbookLanguage
+	language := func(name string) *workbookLanguage {
+		idx := slices.IndexFunc(languages, func(lang *workbookLanguage) bool { return lang.Name == name })
+		if idx >= 0 {
+			return languages[idx]
+		}
+		lang := &workbookLanguage{Name: name, TextIdx: -1, FemaleIdx: -1, SoundIdx: -1}
+		languages = append(languages, lang)
+		return lang
+	}
+
+	colIdx := 0
+	headerRow.ForEachCell(func(cell *xlsx.Cell) error {
+		header := strings.TrimSpace(cell.Value)
+		switch {
+		case strings.EqualFold(header, "file"):
+			fileIdx = colIdx
+		case strings.EqualFold(header, "key"):
+			keyIdx = colIdx
+		case strings.HasSuffix(header, " female"):
+			language(strings.TrimSuffix(header, " female")).FemaleIdx = colIdx
+		case strings.HasSuffix(header, " sound"):
+			language(strings.TrimSuffix(header, " sound")).SoundIdx = colIdx
+		case header != "":
+			language(header).TextIdx = colIdx
+		}
+		colIdx++
+		return nil
+	})
+
+	if fileIdx == -1 || keyIdx == -1 {
+		return fmt.Errorf("xlsx file missing required 'file' and 'key' columns")
+	}
+	languages = slices.DeleteFunc(languages, func(lang *workbookLanguage) bool {
+		return lang.TextIdx == -1 || len(langNames) > 0 && !slices.Contains(langNames, lang.Name)
+	})
+	if len(languages) == 0 {
+		return fmt.Errorf("xlsx file has no language columns to import")
+	}
+
+	// Original files of every language, to keep their comments and the case
+	// of their paths.
+	originals := make(map[string]*tra.TraLanguage)
+	if _, err := os.Stat(originalPath); err == nil {
+		originalLanguages, err := tra.LoadLanguages(originalPath)
+		if err != nil {
+			return err
+		}
+		for _, lang := range originalLanguages {
+			originals[lang.Name] = lang
+		}
+	}
+
+	// Rows by lower-case path of the TRA file, files in order of appearance.
+	rows := make(map[string][]*xlsx.Row)
+	var files []string
+	for rowIdx := 1; rowIdx < sheet.MaxRow; rowIdx++ {
+		row, err := sheet.Row(rowIdx)
+		if err != nil {
+			return fmt.Errorf("unable to read row %d: %w", rowIdx+1, err)
+		}
+		if row.GetCell(fileIdx).Value == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(row.GetCell(keyIdx).Value, 10, 32); err != nil {
+			return fmt.Errorf("invalid key value at row %d: %q is not a valid number", rowIdx+1, row.GetCell(keyIdx).Value)
+		}
+		key := strings.ToLower(filepath.ToSlash(row.GetCell(fileIdx).Value))
+		if _, ok := rows[key]; !ok {
+			files = append(files, key)
+		}
+		rows[key] = append(rows[key], row)
+	}
+
+	cellValue := func(row *xlsx.Row, idx int) string {
+		if idx == -1 {
+			return ""
+		}
+		return row.GetCell(idx).Value
+	}
+
+	written := 0
+	for _, lang := range languages {
+		original := originals[lang.Name]
+		for _, key := range files {
+			tf, file := &parser.TraFile{}, rows[key][0].GetCell(fileIdx).Value
+			if original != nil && original.Files[key] != nil {
+				tf, file = original.Files[key], original.Paths[key]
+			}
+
+			texts := make([]tra.WorkbookText, 0, len(rows[key]))
+			for _, row := range rows[key] {
+				id, _ := strconv.ParseUint(row.GetCell(keyIdx).Value, 10, 32)
+				texts = append(texts, tra.WorkbookText{
+					Id:         uint32(id),
+					MaleText:   cellValue(row, lang.TextIdx),
+					FemaleText: cellValue(row, lang.FemaleIdx),
+					SoundFile:  cellValue(row, lang.SoundIdx),
+				})
+			}
+			tra.MergeWorkbookTexts(tf, texts)
+
+			if len(tf.Entries) == 0 {
+				continue
+			}
+			outputFile := filepath.Join(outputPath, lang.Name, filepath.FromSlash(file))
+			if err := tra.WriteParsedFile(outputFile, tf); err != nil {
+				return fmt.Errorf("failed to write TRA file: %w", err)
+			}
+			written++
+			if verbose {
+				fmt.Printf("Wrote %s (%d entries)\n", outputFile, len(tf.Entries))
+			}
+		}
+	}
+
+	fmt.Printf("%d TRA files of %d languages written\n", written, len(languages))
+	return nil
+}
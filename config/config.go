@@ -11,6 +11,7 @@ import (
 	"sort"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/sbtlocalization/sbt-infinity/typography"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +24,19 @@ type Config struct {
 // GameConfig represents per-game configuration options
 type GameConfig struct {
 	DialogSiteBaseUrl string `toml:"dialog_site_base_url"`
+	SpellDictionary   string `toml:"spell_dictionary"`
+	CustomDictionary  string `toml:"custom_dictionary"`
+	MtUrl             string `toml:"mt_url"`
+	MtApiKey          string `toml:"mt_api_key"`
+
+	Typography typography.Options `toml:"typography"`
+}
+
+// DefaultGameConfig returns the configuration used for options missing in the config file
+func DefaultGameConfig() GameConfig {
+	return GameConfig{
+		Typography: typography.DefaultOptions(),
+	}
 }
 
 // LoadKeyConfig loads the configuration file from the specified path
@@ -72,7 +86,7 @@ func LoadKeyConfig(configPath string) (*Config, error) {
 				continue // Skip this game config if marshaling fails
 			}
 
-			var gameConfig GameConfig
+			gameConfig := DefaultGameConfig()
 			if err := toml.Unmarshal(gameBytes, &gameConfig); err != nil {
 				continue // Skip this game config if unmarshaling fails
 			}
@@ -210,3 +224,58 @@ func ResolveDialogBaseUrl(cmd *cobra.Command) (string, error) {
 	// No games configured
 	return "", fmt.Errorf("no games configured and no dialog base URL provided")
 }
+
+// ResolveGameConfig returns the configuration of the game selected with --game,
+// or of the first game if none is selected. Returns the default config if the game
+// has no configuration section.
+func ResolveGameConfig(cmd *cobra.Command) (GameConfig, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	gameName, _ := cmd.Flags().GetString("game")
+
+	config, err := LoadKeyConfig(configPath)
+	if err != nil {
+		return GameConfig{}, fmt.Errorf("error loading config: %v", err)
+	}
+
+	if gameName == "" {
+		if len(config.Games) == 0 {
+			return DefaultGameConfig(), nil
+		}
+		games := config.ListGames()
+		sort.Strings(games)
+		gameName = games[0]
+	} else if _, exists := config.Games[gameName]; !exists {
+		return GameConfig{}, fmt.Errorf("game '%s' not found in config", gameName)
+	}
+
+	if gameConfig, exists := config.GetGameConfig(gameName); exists {
+		return gameConfig, nil
+	}
+	return DefaultGameConfig(), nil
+}
+
+// ResolveNormalizer returns a typography normalizer with the options of the
+// game config; the language is overridden with --lang if the command has the
+// flag and it is set. Warns on stderr if quotes cannot be normalized because
+// the language is unknown.
+func ResolveNormalizer(cmd *cobra.Command) (*typography.Normalizer, error) {
+	gameConfig, err := ResolveGameConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := gameConfig.Typography
+	hint := "set typography.language in the config"
+	if cmd.Flags().Lookup("lang") != nil {
+		hint += " or use --lang"
+		if cmd.Flags().Changed("lang") {
+			opts.Language, _ = cmd.Flags().GetString("lang")
+		}
+	}
+
+	normalizer := typography.New(opts)
+	if opts.Quotes && !normalizer.HasLanguage() {
+		fmt.Fprintf(os.Stderr, "warning: unknown typography language %q, quotes are not normalized: %s\n", opts.Language, hint)
+	}
+	return normalizer, nil
+}
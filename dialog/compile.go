@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+)
+
+// StrrefResolver returns the strref of a compiled text. The strref is the
+// one of the x-textId of the node, if known. It can be kept if the text is
+// the same, or the text can be written to it or appended as a new strref.
+type StrrefResolver func(strref uint32, known bool, text string) (uint32, error)
+
+// DlgCompiler compiles edited dialogs back into a DLG file. States keep
+// the indexes of their x-nodeId in the original file; new ones, without
+// x-nodeId, are appended. States of the file which are not in the compiled
+// dialogs stay unchanged. Transitions are matched by their x-nodeId too,
+// but the transition table is rewritten in the order of states, so their
+// indexes may change.
+type DlgCompiler struct {
+	Name     string // DLG name without extension
+	File     *DlgWriteFile
+	original DlgWriteFile // the file before compiling, x-nodeId refer to it
+	resolve  StrrefResolver
+}
+
+// Returns a compiler patching the DLG file, or creating a new one if file
+// is nil.
+func NewDlgCompiler(name string, file *DlgWriteFile, resolve StrrefResolver) *DlgCompiler {
+	if file == nil {
+		file = &DlgWriteFile{}
+	}
+	return &DlgCompiler{
+		Name: strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(name, ".DLG"), ".dlg")),
+		File: file,
+		original: DlgWriteFile{
+			States:      slices.Clone(file.States),
+			Transitions: slices.Clone(file.Transitions),
+		},
+		resolve: resolve,
+	}
+}
+
+// Parses an x-nodeId like "ABC[3]".
+func parseNodeId(id string) (NodeOrigin, bool) {
+	name, rest, ok := strings.Cut(id, "[")
+	if !ok || name == "" || !strings.HasSuffix(rest, "]") {
+		return NodeOrigin{}, false
+	}
+	index, err := strconv.ParseUint(strings.TrimSuffix(rest, "]"), 10, 32)
+	if err != nil {
+		return NodeOrigin{}, false
+	}
+	return NewNodeOrigin(name, uint32(index)), true
+}
+
+// Parses an x-textId like "#123".
+func parseTextId(id string) (uint32, bool) {
+	strref, err := strconv.ParseUint(strings.TrimPrefix(id, "#"), 10, 32)
+	if !strings.HasPrefix(id, "#") || err != nil {
+		return 0, false
+	}
+	return uint32(strref), true
+}
+
+// Keeps the original text of a trigger or action if only the surrounding
+// whitespace differs, as dCanvas files have trimmed code.
+func keepCode(original, edited string) string {
+	if strings.TrimSpace(original) == strings.TrimSpace(edited) {
+		return original
+	}
+	return strings.TrimSpace(edited) + "\n"
+}
+
+// Compiles the dialog tree, e.g. an edited one, into the DLG file.
+func (c *DlgCompiler) AddDialog(d *Dialog) error {
+	return c.AddCanvas(d.ToDCanvas(FormatOptions{}))
+}
+
+// A transition compiled from a canvas, with the key to sort transitions
+// of a state: the original index or the position of new ones.
+type compiledTransition struct {
+	DlgWriteTransition
+	index uint32
+	known bool
+	x, y  int
+}
+
+// Compiles the dialog of a dCanvas file into the DLG file. Nodes need the
+// x-nodeRole of state or transition; nodes without it are notes and are
+// skipped. Transitions are edges from states to transitions and, for
+// transitions without text and action, straight to the next states; the
+// trigger of a transition is the x-condition of its edge. Transitions
+// without outgoing edges end the dialog. Edges of notes are skipped too.
+func (c *DlgCompiler) AddCanvas(canvas *dcanvas.Canvas) error {
+	nodes := make(map[string]*dcanvas.Node, len(canvas.Nodes))
+	for _, n := range canvas.Nodes {
+		if n.NodeRole == "state" || n.NodeRole == "transition" {
+			nodes[n.ID] = n
+		}
+	}
+	outgoing := make(map[string][]*dcanvas.Edge)
+	for _, e := range canvas.Edges {
+		if nodes[e.FromNode] == nil || nodes[e.ToNode] == nil {
+			continue
+		}
+		outgoing[e.FromNode] = append(outgoing[e.FromNode], e)
+	}
+
+	// States of the file: existing ones by x-nodeId, new ones appended.
+	states := make(map[string]NodeOrigin)
+	compiled := make(map[uint32]*dcanvas.Node)
+	for _, n := range canvas.Nodes {
+		if nodes[n.ID] == nil || n.NodeRole != "state" {
+			continue
+		}
+		origin, ok := parseNodeId(n.NodeId)
+		switch {
+		case !ok:
+			origin = NewNodeOrigin(c.Name, uint32(len(c.File.States)))
+			c.File.States = append(c.File.States, DlgWriteState{Weight: math.MaxUint32})
+		case !strings.EqualFold(origin.DlgName, c.Name):
+			// A state of another file is only a target of transitions.
+			states[n.ID] = origin
+			continue
+		case int(origin.Index) >= len(c.File.States):
+			return fmt.Errorf("state %s is not in %s.DLG", n.NodeId, c.Name)
+		case n.TextId == "":
+			// A state that could not be loaded.
+			states[n.ID] = origin
+			continue
+		}
+		if _, ok := compiled[origin.Index]; ok {
+			return fmt.Errorf("state %s is in the canvas more than once", n.NodeId)
+		}
+		states[n.ID] = origin
+		compiled[origin.Index] = n
+	}
+
+	target := func(id string) (string, uint32, error) {
+		origin, ok := states[id]
+		if !ok {
+			return "", 0, fmt.Errorf("transition leads to %s, which is not a state", id)
+		}
+		return strings.ToUpper(origin.DlgName), origin.Index, nil
+	}
+
+	indexes := make([]uint32, 0, len(compiled))
+	for index := range compiled {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	blocks := make(map[uint32][]DlgWriteTransition, len(indexes))
+	for _, index := range indexes {
+		n := compiled[index]
+		state := &c.File.States[index]
+
+		var err error
+		state.TextRef = dlgNoIndex
+		if textRef, known := parseTextId(n.TextId); known || n.Text != "" {
+			if state.TextRef, err = c.resolve(textRef, known, n.Text); err != nil {
+				return fmt.Errorf("error resolving text of state %s: %w", n.NodeId, err)
+			}
+		}
+		state.Trigger = keepCode(state.Trigger, n.Trigger)
+		state.HasTrigger = strings.TrimSpace(n.Trigger) != ""
+		if !state.HasTrigger {
+			state.Trigger = ""
+		}
+
+		// Transitions of the state in the original file.
+		var original []DlgWriteTransition
+		var first uint32
+		if int(index) < len(c.original.States) {
+			o := c.original.States[index]
+			first, original = o.FirstTransition, c.original.Transitions[o.FirstTransition:o.FirstTransition+o.NumTransitions]
+		}
+		used := make(map[uint32]bool)
+
+		var transitions []compiledTransition
+		for _, e := range outgoing[n.ID] {
+			to := nodes[e.ToNode]
+			t := compiledTransition{DlgWriteTransition: NewEmptyDlgTransition(), x: to.X, y: to.Y}
+
+			if to.NodeRole == "state" {
+				// A transition without text and action.
+				t.NextStateResource, t.NextStateIndex, err = target(to.ID)
+				if err != nil {
+					return err
+				}
+				for i, o := range original {
+					index := first + uint32(i)
+					if !used[index] && !o.HasText && !o.HasAction && !o.HasJournalText && !o.IsDialogEnd &&
+						strings.EqualFold(o.NextStateResource, t.NextStateResource) && o.NextStateIndex == t.NextStateIndex {
+						t.index, t.known, t.Flags, t.Trigger = index, true, o.Flags, o.Trigger
+						break
+					}
+				}
+			} else {
+				if origin, ok := parseNodeId(to.NodeId); ok && strings.EqualFold(origin.DlgName, c.Name) && int(origin.Index) < len(c.original.Transitions) && !used[origin.Index] {
+					o := c.original.Transitions[origin.Index]
+					t.index, t.known, t.Flags, t.Trigger, t.Action = origin.Index, true, o.Flags, o.Trigger, o.Action
+				}
+
+				if textRef, known := parseTextId(to.TextId); known || to.Text != "" {
+					if t.TextRef, err = c.resolve(textRef, known, to.Text); err != nil {
+						return fmt.Errorf("error resolving text of transition %s: %w", to.NodeId, err)
+					}
+					t.HasText = true
+				}
+				if textRef, known := parseTextId(to.JournalTextId); known || to.JournalText != "" {
+					if t.JournalTextRef, err = c.resolve(textRef, known, to.JournalText); err != nil {
+						return fmt.Errorf("error resolving journal text of transition %s: %w", to.NodeId, err)
+					}
+					t.HasJournalText = true
+				}
+				t.Action = keepCode(t.Action, to.Action)
+				t.HasAction = strings.TrimSpace(to.Action) != ""
+				if !t.HasAction {
+					t.Action = ""
+				}
+
+				next := outgoing[to.ID]
+				switch len(next) {
+				case 0:
+					t.IsDialogEnd = true
+				case 1:
+					if t.NextStateResource, t.NextStateIndex, err = target(next[0].ToNode); err != nil {
+						return err
+					}
+				default:
+					return fmt.Errorf("transition %s leads to %d states", to.ID, len(next))
+				}
+			}
+
+			t.Trigger = keepCode(t.Trigger, e.Condition)
+			t.HasTrigger = strings.TrimSpace(e.Condition) != ""
+			if !t.HasTrigger {
+				t.Trigger = ""
+			}
+			if t.known {
+				used[t.index] = true
+			}
+			transitions = append(transitions, t)
+		}
+
+		// Known transitions keep their order, new ones follow from left
+		// to right.
+		slices.SortStableFunc(transitions, func(a, b compiledTransition) int {
+			if a.known != b.known {
+				if a.known {
+					return -1
+				}
+				return 1
+			}
+			if a.known {
+				return cmp.Compare(a.index, b.index)
+			}
+			return cmp.Or(cmp.Compare(a.x, b.x), cmp.Compare(a.y, b.y))
+		})
+
+		block := make([]DlgWriteTransition, len(transitions))
+		for i, t := range transitions {
+			block[i] = t.DlgWriteTransition
+		}
+		blocks[index] = block
+	}
+
+	c.writeTransitions(blocks)
+	return nil
+}
+
+// Rewrites the transition table in the order of states, with the compiled
+// blocks in place of the old transitions of their states, so the table has
+// no transitions left without a state. States sharing a block keep sharing
+// it.
+func (c *DlgCompiler) writeTransitions(blocks map[uint32][]DlgWriteTransition) {
+	table := make([]DlgWriteTransition, 0, len(c.File.Transitions))
+	shared := make(map[[2]uint32]uint32)
+	for i := range c.File.States {
+		state := &c.File.States[i]
+		block, compiled := blocks[uint32(i)]
+		if !compiled {
+			span := [2]uint32{state.FirstTransition, state.NumTransitions}
+			if first, ok := shared[span]; ok && state.NumTransitions > 0 {
+				state.FirstTransition = first
+				continue
+			}
+			shared[span] = uint32(len(table))
+			block = c.File.Transitions[state.FirstTransition : state.FirstTransition+state.NumTransitions]
+		}
+		state.FirstTransition, state.NumTransitions = uint32(len(table)), uint32(len(block))
+		table = append(table, block...)
+	}
+	c.File.Transitions = table
+}
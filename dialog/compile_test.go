@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+)
+
+// Resolves texts against a TLK of the given texts, appending changed and
+// new texts.
+func testResolver(tlk *[]string) StrrefResolver {
+	return func(strref uint32, known bool, text string) (uint32, error) {
+		if known && int(strref) < len(*tlk) && (*tlk)[strref] == text {
+			return strref, nil
+		}
+		*tlk = append(*tlk, text)
+		return uint32(len(*tlk) - 1), nil
+	}
+}
+
+func testTlk() []string {
+	tlk := make([]string, 14)
+	tlk[10], tlk[11], tlk[12], tlk[13] = "Hello & welcome", "Who is there?", "Me.", "Again?"
+	return tlk
+}
+
+func TestCompileUnchangedDialog(t *testing.T) {
+	tlk := testTlk()
+	c := NewDlgCompiler("ABC.DLG", testDlgFile(), testResolver(&tlk))
+	if err := c.AddDialog(testDialog()); err != nil {
+		t.Fatalf("AddDialog() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(c.File, testDlgFile()) {
+		t.Errorf("unchanged dialog changed the file:\ngot  %+v\nwant %+v", c.File, testDlgFile())
+	}
+	if len(tlk) != 14 {
+		t.Errorf("unchanged dialog added %d texts", len(tlk)-14)
+	}
+}
+
+func TestCompileEditedCanvas(t *testing.T) {
+	canvas := testDialog().ToDCanvas(FormatOptions{})
+	for _, n := range canvas.Nodes {
+		if n.ID == "transition-ABC[0]" {
+			n.Text = "Who goes there?"
+		}
+	}
+	// A new answer leading back to the root, and a note.
+	canvas.Nodes = append(canvas.Nodes,
+		&dcanvas.Node{ID: "new", NodeRole: "transition", Text: "Once more.", X: 5000},
+		&dcanvas.Node{ID: "note", Text: "Check with the writers"})
+	canvas.Edges = append(canvas.Edges,
+		&dcanvas.Edge{ID: "e1", FromNode: "state-ABC[0]", ToNode: "new", Condition: "Global(\"Y\",\"GLOBAL\",0)"},
+		&dcanvas.Edge{ID: "e2", FromNode: "new", ToNode: "state-ABC[0]"},
+		&dcanvas.Edge{ID: "e3", FromNode: "note", ToNode: "new"})
+
+	tlk := testTlk()
+	c := NewDlgCompiler("ABC", testDlgFile(), testResolver(&tlk))
+	if err := c.AddCanvas(canvas); err != nil {
+		t.Fatalf("AddCanvas() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDlg(&buf, c.File); err != nil {
+		t.Fatalf("WriteDlg() error = %v", err)
+	}
+	got := readDlg(t, buf.Bytes())
+
+	// The grown block is rewritten in place, leaving no unused transitions.
+	root := got.States[0]
+	if root.FirstTransition != 0 || root.NumTransitions != 3 || len(got.Transitions) != 3 {
+		t.Fatalf("root transitions = %d+%d of %d, want 0+3 of 3", root.FirstTransition, root.NumTransitions, len(got.Transitions))
+	}
+	transitions := got.Transitions
+
+	if tr := transitions[0]; tr.TextRef != 14 || tlk[14] != "Who goes there?" || tr.Flags != dlgInterrupt || tr.NextStateResource != "XYZ" {
+		t.Errorf("edited transition = %+v, want new strref 14 with flags and target kept", tr)
+	}
+	if tr := transitions[1]; !tr.IsDialogEnd || tr.Action != "EscapeArea()\n" {
+		t.Errorf("second transition = %+v, want the original end", tr)
+	}
+	want := DlgWriteTransition{
+		HasText: true, TextRef: 15, JournalTextRef: dlgNoIndex,
+		HasTrigger: true, Trigger: "Global(\"Y\",\"GLOBAL\",0)\n",
+		NextStateResource: "ABC", NextStateIndex: 0,
+	}
+	if tr := transitions[2]; !reflect.DeepEqual(tr, want) {
+		t.Errorf("new transition = %+v, want %+v", tr, want)
+	}
+}
+
+func TestCompileRemovedTransition(t *testing.T) {
+	canvas := testDialog().ToDCanvas(FormatOptions{})
+	canvas.Nodes = slices.DeleteFunc(canvas.Nodes, func(n *dcanvas.Node) bool { return n.ID == "transition-ABC[1]" })
+	canvas.Edges = slices.DeleteFunc(canvas.Edges, func(e *dcanvas.Edge) bool { return e.ToNode == "transition-ABC[1]" })
+
+	tlk := testTlk()
+	c := NewDlgCompiler("ABC", testDlgFile(), testResolver(&tlk))
+	if err := c.AddCanvas(canvas); err != nil {
+		t.Fatalf("AddCanvas() error = %v", err)
+	}
+
+	if root := c.File.States[0]; root.FirstTransition != 0 || root.NumTransitions != 1 || len(c.File.Transitions) != 1 {
+		t.Fatalf("root transitions = %d+%d of %d, want 0+1 of 1", root.FirstTransition, root.NumTransitions, len(c.File.Transitions))
+	}
+	if tr := c.File.Transitions[0]; tr.TextRef != 11 || tr.NextStateResource != "XYZ" {
+		t.Errorf("kept transition = %+v, want the answer to XYZ", tr)
+	}
+	if err := WriteDlg(&bytes.Buffer{}, c.File); err != nil {
+		t.Errorf("WriteDlg() error = %v", err)
+	}
+}
+
+func TestParseNodeId(t *testing.T) {
+	tests := []struct {
+		id   string
+		want NodeOrigin
+		ok   bool
+	}{
+		{"ABC[3]", NodeOrigin{"ABC", 3}, true},
+		{"ABC.DLG[12]", NodeOrigin{"ABC", 12}, true},
+		{"ABC", NodeOrigin{}, false},
+		{"ABC[x]", NodeOrigin{}, false},
+		{"", NodeOrigin{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseNodeId(tt.id)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseNodeId(%q) = %v, %v, want %v, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompileNewStateWithoutText(t *testing.T) {
+	canvas := testDialog().ToDCanvas(FormatOptions{})
+	canvas.Nodes = append(canvas.Nodes,
+		&dcanvas.Node{ID: "answer", NodeRole: "transition", Text: "Wait.", X: 5000},
+		&dcanvas.Node{ID: "silent", NodeRole: "state", X: 6000})
+	canvas.Edges = append(canvas.Edges,
+		&dcanvas.Edge{ID: "e1", FromNode: "state-ABC[0]", ToNode: "answer"},
+		&dcanvas.Edge{ID: "e2", FromNode: "answer", ToNode: "silent"})
+
+	tlk := testTlk()
+	c := NewDlgCompiler("ABC", testDlgFile(), testResolver(&tlk))
+	if err := c.AddCanvas(canvas); err != nil {
+		t.Fatalf("AddCanvas() error = %v", err)
+	}
+
+	// Only the answer is added to the TLK.
+	if len(tlk) != 15 || tlk[14] != "Wait." {
+		t.Errorf("TLK = %q, want only the answer added", tlk[14:])
+	}
+	last := c.File.States[len(c.File.States)-1]
+	if last.TextRef != dlgNoIndex {
+		t.Errorf("new state TextRef = %#x, want no text", last.TextRef)
+	}
+}
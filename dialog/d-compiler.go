@@ -0,0 +1,462 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+type DCompileOptions struct {
+	// Entries of the TRA file for @N references.
+	Tra map[uint32]*parser.TraEntry
+	// Returns the strref of a text given in the D file or in the TRA file.
+	// The female text is empty if the text has no female variant.
+	Resolve func(text, female, sound string) (uint32, error)
+}
+
+type dTokenKind int
+
+const (
+	dTokenWord dTokenKind = iota
+	dTokenString
+	dTokenSound
+)
+
+type dToken struct {
+	kind dTokenKind
+	text string
+	line int
+}
+
+// Splits a D file into words, strings and sounds like [SOUND], skipping
+// comments.
+func tokenizeD(content string) ([]dToken, error) {
+	var tokens []dToken
+	line := 1
+	pos := 0
+
+	advance := func(end int) {
+		line += strings.Count(content[pos:end], "\n")
+		pos = end
+	}
+
+	for pos < len(content) {
+		ch := content[pos]
+		switch {
+		case unicode.IsSpace(rune(ch)):
+			advance(pos + 1)
+		case strings.HasPrefix(content[pos:], "//"):
+			end := strings.IndexByte(content[pos:], '\n')
+			if end < 0 {
+				end = len(content) - pos
+			}
+			advance(pos + end)
+		case strings.HasPrefix(content[pos:], "/*"):
+			end := strings.Index(content[pos+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unclosed comment", line)
+			}
+			advance(pos + 2 + end + 2)
+		case ch == '~' || ch == '%' || ch == '"':
+			delimiter := content[pos : pos+1]
+			if strings.HasPrefix(content[pos:], "~~~~~") {
+				delimiter = "~~~~~"
+			}
+			start := pos + len(delimiter)
+			end := strings.Index(content[start:], delimiter)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unclosed string", line)
+			}
+			tokens = append(tokens, dToken{dTokenString, content[start : start+end], line})
+			advance(start + end + len(delimiter))
+		case ch == '[':
+			end := strings.IndexByte(content[pos:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unclosed sound", line)
+			}
+			tokens = append(tokens, dToken{dTokenSound, content[pos+1 : pos+end], line})
+			advance(pos + end + 1)
+		default:
+			end := pos + 1
+			for end < len(content) && !unicode.IsSpace(rune(content[end])) && !strings.ContainsRune("~%\"[", rune(content[end])) &&
+				!strings.HasPrefix(content[end:], "//") && !strings.HasPrefix(content[end:], "/*") {
+				end++
+			}
+			tokens = append(tokens, dToken{dTokenWord, content[pos:end], line})
+			advance(end)
+		}
+	}
+	return tokens, nil
+}
+
+// A transition with the label of its next state, resolved when all states
+// are known.
+type dTransition struct {
+	DlgWriteTransition
+	label string
+	line  int
+}
+
+type dState struct {
+	DlgWriteState
+	label       string
+	hasWeight   bool
+	transitions []dTransition
+}
+
+type dParser struct {
+	tokens []dToken
+	pos    int
+	opts   DCompileOptions
+}
+
+func (p *dParser) errorf(format string, args ...any) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *dParser) peek() (dToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return dToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// Reports whether the next token is the keyword, and skips it if so.
+func (p *dParser) accept(keyword string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == dTokenWord && strings.EqualFold(t.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *dParser) expect(keyword string) error {
+	if !p.accept(keyword) {
+		return p.errorf("expected %s", keyword)
+	}
+	return nil
+}
+
+func (p *dParser) string() (string, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != dTokenString {
+		return "", p.errorf("expected a string")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *dParser) number() (uint32, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != dTokenWord {
+		return 0, p.errorf("expected a number")
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(t.text, "#"), 10, 32)
+	if err != nil {
+		return 0, p.errorf("expected a number, got %s", t.text)
+	}
+	p.pos++
+	return uint32(n), nil
+}
+
+// A state label is a word or a string.
+func (p *dParser) label() (string, error) {
+	t, ok := p.peek()
+	if !ok || t.kind == dTokenSound {
+		return "", p.errorf("expected a state label")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+// Parses a text: #strref, @N from the TRA file, or a string with an
+// optional female string and sound.
+func (p *dParser) text() (uint32, error) {
+	t, ok := p.peek()
+	if !ok {
+		return 0, p.errorf("expected a text")
+	}
+	switch {
+	case t.kind == dTokenWord && strings.HasPrefix(t.text, "#"):
+		p.pos++
+		if t.text == "#-1" {
+			return dlgNoIndex, nil
+		}
+		strref, err := strconv.ParseUint(t.text[1:], 10, 32)
+		if err != nil {
+			return 0, p.errorf("invalid strref %s", t.text)
+		}
+		return uint32(strref), nil
+	case t.kind == dTokenWord && strings.HasPrefix(t.text, "@"):
+		p.pos++
+		id, err := strconv.ParseUint(t.text[1:], 10, 32)
+		if err != nil {
+			return 0, p.errorf("invalid TRA reference %s", t.text)
+		}
+		entry, ok := p.opts.Tra[uint32(id)]
+		if !ok {
+			return 0, p.errorf("%s is not in the TRA file", t.text)
+		}
+		return p.resolve(entry.MaleText, entry.FemaleText, entry.SoundFile)
+	case t.kind == dTokenString:
+		p.pos++
+		text, female := t.text, ""
+		if next, ok := p.peek(); ok && next.kind == dTokenString {
+			female = next.text
+			p.pos++
+		}
+		sound := ""
+		if next, ok := p.peek(); ok && next.kind == dTokenSound {
+			sound = next.text
+			p.pos++
+		}
+		return p.resolve(text, female, sound)
+	}
+	return 0, p.errorf("expected a text, got %s", t.text)
+}
+
+func (p *dParser) resolve(text, female, sound string) (uint32, error) {
+	if p.opts.Resolve == nil {
+		return 0, p.errorf("texts need a TLK file")
+	}
+	strref, err := p.opts.Resolve(text, female, sound)
+	if err != nil {
+		return 0, p.errorf("%v", err)
+	}
+	return strref, nil
+}
+
+// Returns whether there is a trigger or action, and its code.
+func code(s string) (bool, string) {
+	if strings.TrimSpace(s) == "" {
+		return false, ""
+	}
+	return true, s
+}
+
+// Parses a state: IF [WEIGHT #n] ~trigger~ [THEN] [BEGIN] label SAY text
+// transitions END.
+func (p *dParser) state() (dState, error) {
+	s := dState{}
+	if err := p.expect("IF"); err != nil {
+		return s, err
+	}
+	if p.accept("WEIGHT") {
+		weight, err := p.number()
+		if err != nil {
+			return s, err
+		}
+		s.Weight, s.hasWeight = weight, true
+	}
+	trigger, err := p.string()
+	if err != nil {
+		return s, err
+	}
+	s.HasTrigger, s.Trigger = code(trigger)
+	p.accept("THEN")
+	p.accept("BEGIN")
+	if s.label, err = p.label(); err != nil {
+		return s, err
+	}
+	if err := p.expect("SAY"); err != nil {
+		return s, err
+	}
+	if s.TextRef, err = p.text(); err != nil {
+		return s, err
+	}
+	if t, ok := p.peek(); ok && t.kind == dTokenWord && t.text == "=" {
+		return s, p.errorf("multiple SAY texts are not supported")
+	}
+
+	for !p.accept("END") {
+		t, err := p.transition()
+		if err != nil {
+			return s, err
+		}
+		s.transitions = append(s.transitions, t)
+	}
+	return s, nil
+}
+
+// Parses a transition: IF ~trigger~ [THEN] features next, or the short
+// forms + ~trigger~ + text features next and ++ text features next.
+func (p *dParser) transition() (dTransition, error) {
+	t := dTransition{DlgWriteTransition: NewEmptyDlgTransition()}
+	if next, ok := p.peek(); ok {
+		t.line = next.line
+	}
+
+	switch {
+	case p.accept("IF"):
+		trigger, err := p.string()
+		if err != nil {
+			return t, err
+		}
+		t.HasTrigger, t.Trigger = code(trigger)
+		p.accept("THEN")
+	case p.accept("+"):
+		trigger, err := p.string()
+		if err != nil {
+			return t, err
+		}
+		t.HasTrigger, t.Trigger = code(trigger)
+		if err := p.expect("+"); err != nil {
+			return t, err
+		}
+		if t.TextRef, err = p.text(); err != nil {
+			return t, err
+		}
+		t.HasText = true
+	case p.accept("++"):
+		var err error
+		if t.TextRef, err = p.text(); err != nil {
+			return t, err
+		}
+		t.HasText = true
+	default:
+		if next, ok := p.peek(); ok {
+			return t, p.errorf("expected a transition or END, got %s", next.text)
+		}
+		return t, p.errorf("expected END")
+	}
+
+	var err error
+	for {
+		switch {
+		case p.accept("REPLY"):
+			if t.TextRef, err = p.text(); err != nil {
+				return t, err
+			}
+			t.HasText = true
+		case p.accept("DO"):
+			if t.Action, err = p.string(); err != nil {
+				return t, err
+			}
+			t.HasAction = true
+		case p.accept("JOURNAL"), p.accept("SOLVED_JOURNAL"), p.accept("UNSOLVED_JOURNAL"):
+			t.Flags |= journalKeywordFlags(strings.ToUpper(p.tokens[p.pos-1].text))
+			if t.JournalTextRef, err = p.text(); err != nil {
+				return t, err
+			}
+			t.HasJournalText = true
+		case p.accept("FLAGS"):
+			flags, err := p.number()
+			if err != nil {
+				return t, err
+			}
+			t.Flags |= flags &^ dlgContentFlags
+		case p.accept("GOTO"):
+			t.label, err = p.label()
+			return t, err
+		case p.accept("EXTERN"):
+			p.accept("IF_FILE_EXISTS")
+			if t.NextStateResource, err = p.label(); err != nil {
+				return t, err
+			}
+			t.NextStateResource = strings.ToUpper(t.NextStateResource)
+			t.label, err = p.label()
+			return t, err
+		case p.accept("EXIT"):
+			t.IsDialogEnd = true
+			return t, nil
+		default:
+			if next, ok := p.peek(); ok {
+				return t, p.errorf("unexpected %s in a transition", next.text)
+			}
+			return t, p.errorf("expected GOTO, EXTERN or EXIT")
+		}
+	}
+}
+
+// Compiles a WeiDU D file with a BEGIN action into a DLG file. States get
+// indexes in the order they appear, so states of a D file written by WriteD
+// keep their indexes. Other actions, like APPEND or CHAIN, are not
+// supported. Returns the name of the DLG file and its content.
+func CompileD(content string, opts DCompileOptions) (string, *DlgWriteFile, error) {
+	tokens, err := tokenizeD(content)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &dParser{tokens: tokens, opts: opts}
+
+	if err := p.expect("BEGIN"); err != nil {
+		if t, ok := p.peek(); ok && t.kind == dTokenWord {
+			return "", nil, p.errorf("%s is not supported, only BEGIN", t.text)
+		}
+		return "", nil, err
+	}
+	name, err := p.label()
+	if err != nil {
+		return "", nil, err
+	}
+	name = strings.ToUpper(name)
+
+	f := &DlgWriteFile{}
+	if t, ok := p.peek(); ok && t.kind == dTokenWord && !strings.EqualFold(t.text, "IF") {
+		if f.ThreatFlags, err = p.number(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var states []dState
+	labels := make(map[string]uint32)
+	for p.pos < len(p.tokens) {
+		if t, _ := p.peek(); t.kind == dTokenWord && !strings.EqualFold(t.text, "IF") {
+			return "", nil, p.errorf("%s is not supported, only states of BEGIN", t.text)
+		}
+		s, err := p.state()
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := labels[s.label]; ok {
+			return "", nil, p.errorf("state %s is defined more than once", s.label)
+		}
+		labels[s.label] = uint32(len(states))
+		states = append(states, s)
+	}
+
+	for i, s := range states {
+		// States without WEIGHT keep their order.
+		if !s.hasWeight {
+			s.Weight = uint32(i)
+		}
+		s.FirstTransition = uint32(len(f.Transitions))
+		s.NumTransitions = uint32(len(s.transitions))
+		for _, t := range s.transitions {
+			if !t.IsDialogEnd {
+				if t.NextStateResource == "" || t.NextStateResource == name {
+					index, ok := labels[t.label]
+					if !ok {
+						return "", nil, fmt.Errorf("line %d: state %s is not defined", t.line, t.label)
+					}
+					t.NextStateResource, t.NextStateIndex = name, index
+				} else {
+					index, err := strconv.ParseUint(t.label, 10, 32)
+					if err != nil {
+						return "", nil, fmt.Errorf("line %d: state %s of %s must be an index", t.line, t.label, t.NextStateResource)
+					}
+					t.NextStateIndex = uint32(index)
+				}
+			}
+			f.Transitions = append(f.Transitions, t.DlgWriteTransition)
+		}
+		f.States = append(f.States, s.DlgWriteState)
+	}
+
+	return name, f, nil
+}
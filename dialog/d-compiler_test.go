@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/sbtlocalization/sbt-infinity/fs"
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+func TestDRoundTrip(t *testing.T) {
+	tlk := testTlk()
+	tlk[12] = `Say "~hi~"`
+	sounds := map[uint32]string{10: "ABC01"}
+	females := map[uint32]string{11: "Who is there, lady?"}
+
+	want := testDlgFile()
+	want.Transitions[0].HasJournalText, want.Transitions[0].JournalTextRef = true, 12
+	want.Transitions[0].Flags |= dlgAddSolvedQuest | dlgClearActions
+	want.Transitions[1].HasTrigger, want.Transitions[1].Trigger = true, "!Global(\"Y\",\"LOCALS\",0) // 100%\n"
+
+	text := func(strref uint32) (string, string) { return tlk[strref], sounds[strref] }
+	femaleText := func(strref uint32) string { return females[strref] }
+	resolve := func(s, female, sound string) (uint32, error) {
+		for i, t := range tlk {
+			if t == s && females[uint32(i)] == female && sounds[uint32(i)] == sound {
+				return uint32(i), nil
+			}
+		}
+		return 0, fmt.Errorf("unknown text %q %q", s, female)
+	}
+
+	tests := []struct {
+		name      string
+		mode      DTextMode
+		wantTexts []string
+	}{
+		{"strref", DTextStrref, []string{`SAY #10 /* ~Hello & welcome~ [ABC01] */`, `REPLY #11 /* ~Who is there?~ ~Who is there, lady?~ */`}},
+		{"tra", DTextTra, []string{`SAY @0`, `REPLY @1`}},
+		{"inline", DTextInline, []string{`SAY ~Hello & welcome~ [ABC01]`, `REPLY ~Who is there?~ ~Who is there, lady?~`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			entries, err := WriteD(&buf, "ABC", want, DWriteOptions{Mode: tt.mode, Text: text, FemaleText: femaleText})
+			if err != nil {
+				t.Fatalf("WriteD() error = %v", err)
+			}
+			d := buf.String()
+			for _, s := range append(tt.wantTexts, "BEGIN ~ABC~ 1", "IF WEIGHT #1 ~Global(\"X\",\"GLOBAL\",1)\n~ THEN BEGIN 0", "SOLVED_JOURNAL", "FLAGS 1056", "EXTERN ~XYZ~ 2", "EXIT") {
+				if !strings.Contains(d, s) {
+					t.Errorf("D file does not contain %q:\n%s", s, d)
+				}
+			}
+			if tt.mode == DTextTra && (len(entries) != 4 || entries[1].FemaleText != females[11]) {
+				t.Errorf("TRA entries = %+v, want 4 with the female variant of @1", entries)
+			}
+
+			tra := make(map[uint32]*parser.TraEntry)
+			for i := range entries {
+				tra[entries[i].ID] = &entries[i]
+			}
+			name, got, err := CompileD(d, DCompileOptions{Tra: tra, Resolve: resolve})
+			if err != nil {
+				t.Fatalf("CompileD() error = %v\n%s", err, d)
+			}
+			if name != "ABC" {
+				t.Errorf("name = %s, want ABC", name)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCompileD(t *testing.T) {
+	var texts, females []string
+	resolve := func(s, female, sound string) (uint32, error) {
+		if i := slices.Index(texts, s); i >= 0 {
+			return uint32(i), nil
+		}
+		texts, females = append(texts, s), append(females, female)
+		return uint32(len(texts) - 1), nil
+	}
+
+	_, f, err := CompileD(`
+BEGIN KHALID // a comment
+
+IF ~NumTimesTalkedTo(0)~ greeting
+  SAY ~Hello.~ ~Hello!~ [KHAL01]
+  ++ ~Bye.~ EXIT
+  + ~Global("X","GLOBAL",1)~ + #5 DO ~SetGlobal("X","GLOBAL",2)~ GOTO next
+  IF ~~ THEN EXTERN ~JAHEIRA~ 3
+END
+
+IF ~~ next /* no trigger */
+  SAY #7
+  IF ~~ GOTO greeting
+END
+`, DCompileOptions{Resolve: resolve})
+	if err != nil {
+		t.Fatalf("CompileD() error = %v", err)
+	}
+
+	if len(f.States) != 2 || len(f.Transitions) != 4 {
+		t.Fatalf("got %d states, %d transitions, want 2, 4", len(f.States), len(f.Transitions))
+	}
+	if s := f.States[0]; texts[s.TextRef] != "Hello." || females[s.TextRef] != "Hello!" {
+		t.Errorf("state 0 text = %q %q, want the female variant", texts[s.TextRef], females[s.TextRef])
+	}
+	if s := f.States[1]; s.HasTrigger || s.TextRef != 7 || s.FirstTransition != 3 || s.NumTransitions != 1 {
+		t.Errorf("state 1 = %+v", s)
+	}
+	if tr := f.Transitions[0]; !tr.HasText || texts[tr.TextRef] != "Bye." || !tr.IsDialogEnd {
+		t.Errorf("transition 0 = %+v", tr)
+	}
+	if tr := f.Transitions[1]; tr.TextRef != 5 || !tr.HasTrigger || !tr.HasAction || tr.NextStateResource != "KHALID" || tr.NextStateIndex != 1 {
+		t.Errorf("transition 1 = %+v", tr)
+	}
+	if tr := f.Transitions[2]; tr.HasText || tr.NextStateResource != "JAHEIRA" || tr.NextStateIndex != 3 {
+		t.Errorf("transition 2 = %+v", tr)
+	}
+}
+
+func TestCompileDErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"append", "APPEND KHALID END", "APPEND is not supported"},
+		{"undefined label", "BEGIN A IF ~~ x SAY #1 IF ~~ GOTO y END", "state y is not defined"},
+		{"duplicate label", "BEGIN A IF ~~ x SAY #1 END IF ~~ x SAY #2 END", "defined more than once"},
+		{"missing END", "BEGIN A IF ~~ x SAY #1", "expected END"},
+		{"unknown TRA", "BEGIN A IF ~~ x SAY @3 END", "@3 is not in the TRA file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := CompileD(tt.content, DCompileOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CompileD() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Decompiles and compiles back every DLG file of a game. The game is given
+// by the path of its chitin.key in SBT_INF_KEY; the test is skipped
+// without it.
+func TestDRoundTripGame(t *testing.T) {
+	keyPath := os.Getenv("SBT_INF_KEY")
+	if keyPath == "" {
+		t.Skip("SBT_INF_KEY is not set")
+	}
+
+	dlgFs := fs.NewInfinityFs(keyPath, fs.WithTypeFilter(fs.FileType_DLG))
+	dir, err := dlgFs.Open("DLG")
+	if err != nil {
+		t.Fatalf("unable to list DLG files: %v", err)
+	}
+	names, err := dir.Readdirnames(0)
+	dir.Close()
+	if err != nil {
+		t.Fatalf("unable to list DLG files: %v", err)
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			file, err := dlgFs.Open(name)
+			if err != nil {
+				t.Fatalf("unable to open %s: %v", name, err)
+			}
+			defer file.Close()
+
+			dlg := parser.NewDlg()
+			if err := dlg.Read(kaitai.NewStream(file), nil, dlg); err != nil {
+				t.Skipf("unable to parse %s: %v", name, err)
+			}
+			want, err := DlgWriteFileFromDlg(dlg)
+			if err != nil {
+				t.Skipf("unable to read %s: %v", name, err)
+			}
+
+			var buf bytes.Buffer
+			dlgName := strings.TrimSuffix(strings.ToUpper(name), ".DLG")
+			if _, err := WriteD(&buf, dlgName, want, DWriteOptions{Mode: DTextStrref}); err != nil {
+				t.Fatalf("WriteD() error = %v", err)
+			}
+			_, got, err := CompileD(buf.String(), DCompileOptions{})
+			if err != nil {
+				t.Fatalf("CompileD() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(dStates(got), dStates(want)) {
+				t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", dStates(got), dStates(want))
+			}
+		})
+	}
+}
+
+// A state of a DLG file as written to D files: with its transitions instead
+// of their indexes, and the order of its trigger instead of the weight.
+type dStateContent struct {
+	DlgWriteState
+	TriggerOrder int
+	Transitions  []DlgWriteTransition
+}
+
+func dStates(f *DlgWriteFile) []dStateContent {
+	weights := stateWeights(f)
+	states := make([]dStateContent, len(f.States))
+	for i, s := range f.States {
+		order, ok := weights[i]
+		if !ok && s.HasTrigger {
+			order = i
+		}
+		transitions := slices.Clone(f.Transitions[s.FirstTransition : s.FirstTransition+s.NumTransitions])
+		for j := range transitions {
+			transitions[j].NextStateResource = strings.ToUpper(transitions[j].NextStateResource)
+		}
+		s.Weight, s.FirstTransition = 0, 0
+		states[i] = dStateContent{DlgWriteState: s, TriggerOrder: order, Transitions: transitions}
+	}
+	return states
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/sbtlocalization/sbt-infinity/tra"
+)
+
+// DTextMode selects how texts are written in WeiDU D files.
+type DTextMode int
+
+const (
+	// Strrefs like #123, with the texts in comments if known.
+	DTextStrref DTextMode = iota
+	// References like @0 into a generated TRA file.
+	DTextTra
+	// Texts like ~Hello~ [SOUND].
+	DTextInline
+)
+
+type DWriteOptions struct {
+	Mode DTextMode
+	// Returns the text and the sound of a strref. Required by DTextTra and
+	// DTextInline; with DTextStrref the texts are written in comments.
+	Text func(strref uint32) (string, string)
+	// Returns the female variant of a strref, or an empty string if it has
+	// none. Optional.
+	FemaleText func(strref uint32) string
+}
+
+type dWriter struct {
+	*bufio.Writer
+	opts       DWriteOptions
+	traIds     map[uint32]uint32
+	traEntries []parser.TraEntry
+}
+
+// Returns the text, its female variant if it differs, and the sound of a
+// strref.
+func (w *dWriter) texts(strref uint32) (string, string, string) {
+	text, sound := w.opts.Text(strref)
+	female := ""
+	if w.opts.FemaleText != nil {
+		if female = w.opts.FemaleText(strref); female == text {
+			female = ""
+		}
+	}
+	return text, female, sound
+}
+
+func (w *dWriter) text(strref uint32) string {
+	if strref == dlgNoIndex {
+		return "#-1"
+	}
+	switch w.opts.Mode {
+	case DTextTra:
+		id, ok := w.traIds[strref]
+		if !ok {
+			id = uint32(len(w.traEntries))
+			text, female, sound := w.texts(strref)
+			w.traIds[strref] = id
+			w.traEntries = append(w.traEntries, parser.TraEntry{ID: id, MaleText: text, FemaleText: female, SoundFile: sound})
+		}
+		return fmt.Sprintf("@%d", id)
+	case DTextInline:
+		return inlineText(w.texts(strref))
+	default:
+		if w.opts.Text == nil {
+			return fmt.Sprintf("#%d", strref)
+		}
+		comment := strings.ReplaceAll(inlineText(w.texts(strref)), "*/", "* /")
+		return fmt.Sprintf("#%d /* %s */", strref, comment)
+	}
+}
+
+// Formats a text as ~text~ ~female~ [SOUND].
+func inlineText(text, female, sound string) string {
+	s := tra.WrapWithDelimiters(text)
+	if female != "" {
+		s += " " + tra.WrapWithDelimiters(female)
+	}
+	if sound != "" {
+		s += fmt.Sprintf(" [%s]", sound)
+	}
+	return s
+}
+
+// Returns the weights of the states in the WEIGHT #n form, i.e. the indexes
+// in the state trigger table, or nil if the triggers are in the order of
+// states and need no weights.
+func stateWeights(f *DlgWriteFile) map[int]int {
+	var triggered []int
+	for i, s := range f.States {
+		if s.HasTrigger {
+			triggered = append(triggered, i)
+		}
+	}
+	sorted := slices.Clone(triggered)
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		return cmp.Compare(f.States[a].Weight, f.States[b].Weight)
+	})
+	if slices.Equal(triggered, sorted) {
+		return nil
+	}
+
+	weights := make(map[int]int, len(sorted))
+	for weight, i := range sorted {
+		weights[i] = weight
+	}
+	return weights
+}
+
+// Writes the DLG file as a WeiDU D file with a BEGIN action. States are
+// labeled with their indexes, so compiling the D file keeps them. Returns
+// the entries of the TRA file with DTextTra.
+func WriteD(w io.Writer, name string, f *DlgWriteFile, opts DWriteOptions) ([]parser.TraEntry, error) {
+	if opts.Mode != DTextStrref && opts.Text == nil {
+		return nil, fmt.Errorf("texts are required to write texts of %s", name)
+	}
+	dw := &dWriter{Writer: bufio.NewWriter(w), opts: opts, traIds: make(map[uint32]uint32)}
+	weights := stateWeights(f)
+
+	fmt.Fprintf(dw, "// creator  : sbt-inf\n// argument : %s.DLG\n\n", name)
+	fmt.Fprintf(dw, "BEGIN %s", tra.WrapWithDelimiters(name))
+	if f.ThreatFlags != 0 {
+		fmt.Fprintf(dw, " %d", f.ThreatFlags)
+	}
+	dw.WriteString("\n")
+
+	for i, s := range f.States {
+		trigger := ""
+		if s.HasTrigger {
+			trigger = s.Trigger
+		}
+		dw.WriteString("\nIF ")
+		if weight, ok := weights[i]; ok {
+			fmt.Fprintf(dw, "WEIGHT #%d ", weight)
+		}
+		fmt.Fprintf(dw, "%s THEN BEGIN %d\n", tra.WrapWithDelimiters(trigger), i)
+		fmt.Fprintf(dw, "  SAY %s\n", dw.text(s.TextRef))
+
+		if uint64(s.FirstTransition)+uint64(s.NumTransitions) > uint64(len(f.Transitions)) {
+			return nil, fmt.Errorf("transitions of state %d are out of range", i)
+		}
+		for _, t := range f.Transitions[s.FirstTransition : s.FirstTransition+s.NumTransitions] {
+			dw.writeTransition(name, t)
+		}
+		dw.WriteString("END\n")
+	}
+
+	if err := dw.Flush(); err != nil {
+		return nil, fmt.Errorf("error writing D file of %s: %w", name, err)
+	}
+	return dw.traEntries, nil
+}
+
+func (w *dWriter) writeTransition(name string, t DlgWriteTransition) {
+	trigger := ""
+	if t.HasTrigger {
+		trigger = t.Trigger
+	}
+	fmt.Fprintf(w, "  IF %s THEN", tra.WrapWithDelimiters(trigger))
+	if t.HasText {
+		fmt.Fprintf(w, " REPLY %s", w.text(t.TextRef))
+	}
+	if t.HasAction {
+		fmt.Fprintf(w, " DO %s", tra.WrapWithDelimiters(t.Action))
+	}
+
+	flags := t.Flags &^ dlgContentFlags
+	if t.HasJournalText {
+		keyword := "JOURNAL"
+		switch {
+		case flags&dlgAddSolvedQuest != 0:
+			keyword = "SOLVED_JOURNAL"
+		case flags&dlgAddUnsolvedQuest != 0:
+			keyword = "UNSOLVED_JOURNAL"
+		}
+		fmt.Fprintf(w, " %s %s", keyword, w.text(t.JournalTextRef))
+		flags &^= journalKeywordFlags(keyword)
+	}
+	if flags != 0 {
+		fmt.Fprintf(w, " FLAGS %d", flags)
+	}
+
+	switch {
+	case t.IsDialogEnd:
+		w.WriteString(" EXIT\n")
+	case strings.EqualFold(t.NextStateResource, name):
+		fmt.Fprintf(w, " GOTO %d\n", t.NextStateIndex)
+	default:
+		fmt.Fprintf(w, " EXTERN %s %d\n", tra.WrapWithDelimiters(t.NextStateResource), t.NextStateIndex)
+	}
+}
+
+// Returns the flags set by a JOURNAL keyword.
+func journalKeywordFlags(keyword string) uint32 {
+	switch keyword {
+	case "SOLVED_JOURNAL":
+		return dlgAddSolvedQuest
+	case "UNSOLVED_JOURNAL":
+		return dlgAddUnsolvedQuest
+	}
+	return 0
+}
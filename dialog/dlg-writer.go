@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+const (
+	dlgHeaderSize     = 0x34
+	dlgStateSize      = 16
+	dlgTransitionSize = 32
+	dlgTextEntrySize  = 8
+	dlgNoIndex        = 0xFFFFFFFF
+)
+
+// Transition flags of DLG V1.0.
+const (
+	dlgWithText uint32 = 1 << iota
+	dlgWithTrigger
+	dlgWithAction
+	dlgDialogEnd
+	dlgWithJournal
+	dlgInterrupt
+	dlgAddUnsolvedQuest
+	dlgAddJournalNote
+	dlgAddSolvedQuest
+	dlgImmediateAction
+	dlgClearActions
+
+	// Flags set from the fields of DlgWriteTransition.
+	dlgContentFlags = dlgWithText | dlgWithTrigger | dlgWithAction | dlgDialogEnd | dlgWithJournal
+)
+
+type DlgWriteState struct {
+	TextRef    uint32
+	HasTrigger bool
+	Trigger    string
+	// Order of the trigger in the state trigger table, which is the order
+	// the engine checks the triggers of root states. States of the same
+	// weight keep their order.
+	Weight          uint32
+	FirstTransition uint32
+	NumTransitions  uint32
+}
+
+type DlgWriteTransition struct {
+	// Flags other than the ones set from the fields, e.g. the journal
+	// entry type, including bits unknown to this package.
+	Flags             uint32
+	HasText           bool
+	TextRef           uint32
+	HasJournalText    bool
+	JournalTextRef    uint32
+	HasTrigger        bool
+	Trigger           string
+	HasAction         bool
+	Action            string
+	IsDialogEnd       bool
+	NextStateResource string // max 8 chars, will be null-padded/truncated
+	NextStateIndex    uint32
+}
+
+// DlgWriteFile is the content of a DLG V1.0 file for writing.
+type DlgWriteFile struct {
+	ThreatFlags uint32
+	States      []DlgWriteState
+	Transitions []DlgWriteTransition
+}
+
+func NewEmptyDlgTransition() DlgWriteTransition {
+	return DlgWriteTransition{
+		TextRef:        dlgNoIndex,
+		JournalTextRef: dlgNoIndex,
+	}
+}
+
+// Converts a parsed DLG file into content for writing, so the file can be
+// patched and written back with the same state and transition indexes.
+func DlgWriteFileFromDlg(dlg *p.Dlg) (*DlgWriteFile, error) {
+	f := &DlgWriteFile{ThreatFlags: dlg.RawThreatFlags()}
+
+	states, err := dlg.States()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read states: %w", err)
+	}
+	for i, state := range states {
+		s := DlgWriteState{
+			TextRef:         state.TextRef,
+			Weight:          state.StateTriggerIndex,
+			FirstTransition: state.FirstTransitionIndex,
+			NumTransitions:  state.NumTransitions,
+		}
+		trigger, err := state.Trigger()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trigger of state %d: %w", i, err)
+		}
+		if trigger != nil {
+			if s.Trigger, err = trigger.Text(); err != nil {
+				return nil, fmt.Errorf("failed to read trigger of state %d: %w", i, err)
+			}
+			s.HasTrigger = true
+		}
+		f.States = append(f.States, s)
+	}
+
+	transitions, err := dlg.Transitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transitions: %w", err)
+	}
+	for i, transition := range transitions {
+		flags := transition.Flags
+		t := DlgWriteTransition{
+			Flags:             transition.RawFlags() &^ dlgContentFlags,
+			HasText:           flags.WithText,
+			TextRef:           transition.TextRef,
+			HasJournalText:    flags.WithJournalEntry,
+			JournalTextRef:    transition.JournalTextRef,
+			HasTrigger:        flags.WithTrigger,
+			HasAction:         flags.WithAction,
+			IsDialogEnd:       flags.DialogEnd,
+			NextStateResource: transition.NextStateResource,
+			NextStateIndex:    transition.NextStateIndex,
+		}
+		if t.HasTrigger {
+			trigger, err := transition.Trigger()
+			if err == nil {
+				t.Trigger, err = trigger.Text()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read trigger of transition %d: %w", i, err)
+			}
+		}
+		if t.HasAction {
+			action, err := transition.Action()
+			if err == nil {
+				t.Action, err = action.Text()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read action of transition %d: %w", i, err)
+			}
+		}
+		f.Transitions = append(f.Transitions, t)
+	}
+
+	return f, nil
+}
+
+func WriteDlgFile(path string, f *DlgWriteFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create DLG file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteDlg(file, f)
+}
+
+// Writes a DLG V1.0 file: the header, states, transitions, tables of state
+// triggers, transition triggers and actions, and their texts. The tables
+// are built from the triggers and actions of states and transitions.
+func WriteDlg(w io.Writer, f *DlgWriteFile) error {
+	for i, s := range f.States {
+		if uint64(s.FirstTransition)+uint64(s.NumTransitions) > uint64(len(f.Transitions)) {
+			return fmt.Errorf("transitions %d+%d of state %d are out of range", s.FirstTransition, s.NumTransitions, i)
+		}
+	}
+
+	// State triggers in the order of weights.
+	triggerStates := make([]int, 0, len(f.States))
+	for i, s := range f.States {
+		if s.HasTrigger {
+			triggerStates = append(triggerStates, i)
+		}
+	}
+	slices.SortStableFunc(triggerStates, func(a, b int) int {
+		return cmp.Compare(f.States[a].Weight, f.States[b].Weight)
+	})
+	stateTriggerIndex := make(map[int]uint32, len(triggerStates))
+	stateTriggers := make([]string, len(triggerStates))
+	for index, i := range triggerStates {
+		stateTriggerIndex[i] = uint32(index)
+		stateTriggers[index] = f.States[i].Trigger
+	}
+
+	var transitionTriggers, actions []string
+	transitionTriggerIndex := make([]uint32, len(f.Transitions))
+	actionIndex := make([]uint32, len(f.Transitions))
+	for i, t := range f.Transitions {
+		transitionTriggerIndex[i], actionIndex[i] = dlgNoIndex, dlgNoIndex
+		if t.HasTrigger {
+			transitionTriggerIndex[i] = uint32(len(transitionTriggers))
+			transitionTriggers = append(transitionTriggers, t.Trigger)
+		}
+		if t.HasAction {
+			actionIndex[i] = uint32(len(actions))
+			actions = append(actions, t.Action)
+		}
+	}
+
+	ofsStates := uint32(dlgHeaderSize)
+	ofsTransitions := ofsStates + dlgStateSize*uint32(len(f.States))
+	ofsStateTriggers := ofsTransitions + dlgTransitionSize*uint32(len(f.Transitions))
+	ofsTransitionTriggers := ofsStateTriggers + dlgTextEntrySize*uint32(len(stateTriggers))
+	ofsActions := ofsTransitionTriggers + dlgTextEntrySize*uint32(len(transitionTriggers))
+	ofsStrings := ofsActions + dlgTextEntrySize*uint32(len(actions))
+
+	buf := &bytes.Buffer{}
+	put := func(values ...uint32) {
+		for _, v := range values {
+			binary.Write(buf, binary.LittleEndian, v)
+		}
+	}
+
+	buf.WriteString("DLG V1.0")
+	put(uint32(len(f.States)), ofsStates,
+		uint32(len(f.Transitions)), ofsTransitions,
+		ofsStateTriggers, uint32(len(stateTriggers)),
+		ofsTransitionTriggers, uint32(len(transitionTriggers)),
+		ofsActions, uint32(len(actions)),
+		f.ThreatFlags)
+
+	for i, s := range f.States {
+		triggerIndex, ok := stateTriggerIndex[i]
+		if !ok {
+			triggerIndex = dlgNoIndex
+		}
+		put(s.TextRef, s.FirstTransition, s.NumTransitions, triggerIndex)
+	}
+
+	for i, t := range f.Transitions {
+		flags := t.Flags &^ dlgContentFlags
+		for _, bit := range []struct {
+			set  bool
+			flag uint32
+		}{
+			{t.HasText, dlgWithText},
+			{t.HasTrigger, dlgWithTrigger},
+			{t.HasAction, dlgWithAction},
+			{t.IsDialogEnd, dlgDialogEnd},
+			{t.HasJournalText, dlgWithJournal},
+		} {
+			if bit.set {
+				flags |= bit.flag
+			}
+		}
+		put(flags, t.TextRef, t.JournalTextRef, transitionTriggerIndex[i], actionIndex[i])
+		resource := padResref(t.NextStateResource)
+		buf.Write(resource[:])
+		put(t.NextStateIndex)
+	}
+
+	// Texts with deduplication, in the order of the tables.
+	stringOffsets := make(map[string]uint32)
+	stringData := bytes.Buffer{}
+	for _, table := range [][]string{stateTriggers, transitionTriggers, actions} {
+		for _, s := range table {
+			offset, exists := stringOffsets[s]
+			if !exists {
+				offset = ofsStrings + uint32(stringData.Len())
+				stringOffsets[s] = offset
+				stringData.WriteString(s)
+			}
+			put(offset, uint32(len(s)))
+		}
+	}
+	buf.Write(stringData.Bytes())
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write DLG file: %w", err)
+	}
+	return nil
+}
+
+// Pads or truncates the resource name to exactly 8 bytes.
+func padResref(name string) [8]byte {
+	var result [8]byte
+	copy(result[:], name)
+	return result
+}
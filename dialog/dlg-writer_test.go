@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// A DLG file with the ABC part of testDialog.
+func testDlgFile() *DlgWriteFile {
+	end := NewEmptyDlgTransition()
+	end.HasAction, end.Action, end.IsDialogEnd = true, "EscapeArea()\n", true
+
+	return &DlgWriteFile{
+		ThreatFlags: 1,
+		States: []DlgWriteState{
+			{TextRef: 10, HasTrigger: true, Trigger: "Global(\"X\",\"GLOBAL\",1)\n", Weight: 1, FirstTransition: 0, NumTransitions: 2},
+			{TextRef: 13, HasTrigger: true, Trigger: "True()\n", Weight: 0, FirstTransition: 2, NumTransitions: 0},
+		},
+		Transitions: []DlgWriteTransition{
+			{Flags: dlgInterrupt, HasText: true, TextRef: 11, JournalTextRef: dlgNoIndex, NextStateResource: "XYZ", NextStateIndex: 2},
+			end,
+		},
+	}
+}
+
+func readDlg(t *testing.T, data []byte) *DlgWriteFile {
+	t.Helper()
+	dlg := p.NewDlg()
+	if err := dlg.Read(kaitai.NewStream(bytes.NewReader(data)), nil, dlg); err != nil {
+		t.Fatalf("failed to parse written DLG: %v", err)
+	}
+	f, err := DlgWriteFileFromDlg(dlg)
+	if err != nil {
+		t.Fatalf("DlgWriteFileFromDlg() error = %v", err)
+	}
+	return f
+}
+
+func TestWriteDlgRoundTrip(t *testing.T) {
+	want := testDlgFile()
+	// Bits not modelled by the parser are kept.
+	want.ThreatFlags |= 1 << 8
+	want.Transitions[0].Flags |= 1 << 20
+
+	var buf bytes.Buffer
+	if err := WriteDlg(&buf, want); err != nil {
+		t.Fatalf("WriteDlg() error = %v", err)
+	}
+	got := readDlg(t, buf.Bytes())
+
+	// The weights become the indexes in the state trigger table.
+	if got.States[0].Weight != 1 || got.States[1].Weight != 0 {
+		t.Errorf("weights = %d, %d, want 1, 0", got.States[0].Weight, got.States[1].Weight)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestWriteDlgOutOfRange(t *testing.T) {
+	f := testDlgFile()
+	f.States[1].NumTransitions = 1
+	if err := WriteDlg(&bytes.Buffer{}, f); err == nil {
+		t.Error("WriteDlg() error = nil, want transitions out of range")
+	}
+}
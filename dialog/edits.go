@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+)
+
+// CanvasText is a text of a dCanvas node with its strref.
+type CanvasText struct {
+	StrRef uint32
+	Text   string
+	Source string // file of the canvas
+	NodeId string
+}
+
+// TextEdit is a text changed in dCanvas files.
+type TextEdit struct {
+	StrRef   uint32
+	Text     string
+	Original string
+	Sources  []CanvasText
+}
+
+// TextConflict is a strref changed to different texts in dCanvas files.
+type TextConflict struct {
+	StrRef   uint32
+	Original string
+	Texts    []CanvasText
+}
+
+type TextEdits struct {
+	Edits     []TextEdit
+	Conflicts []TextConflict
+	// Texts with strrefs that are not in the TLK file.
+	Unknown []CanvasText
+}
+
+// Returns the texts and journal texts of the nodes with x-textId and
+// x-journalTextId.
+func CanvasTexts(canvas *dcanvas.Canvas, source string) []CanvasText {
+	var texts []CanvasText
+	for _, n := range canvas.Nodes {
+		if strref, ok := parseTextId(n.TextId); ok {
+			texts = append(texts, CanvasText{StrRef: strref, Text: n.Text, Source: source, NodeId: n.NodeId})
+		}
+		if strref, ok := parseTextId(n.JournalTextId); ok {
+			texts = append(texts, CanvasText{StrRef: strref, Text: n.JournalText, Source: source, NodeId: n.NodeId})
+		}
+	}
+	return texts
+}
+
+// Compares the texts of dCanvas files with the texts of the TLK file. Line
+// endings are not compared, as text editors may change them. A strref
+// changed to different texts is a conflict and not an edit.
+func CompareTexts(texts []CanvasText, tlkText func(strref uint32) (string, bool)) *TextEdits {
+	result := &TextEdits{}
+	changed := make(map[uint32][]CanvasText)
+	originals := make(map[uint32]string)
+
+	for _, t := range texts {
+		original, ok := tlkText(t.StrRef)
+		if !ok {
+			result.Unknown = append(result.Unknown, t)
+			continue
+		}
+		if normalizeLineEndings(t.Text) != normalizeLineEndings(original) {
+			changed[t.StrRef] = append(changed[t.StrRef], t)
+			originals[t.StrRef] = original
+		}
+	}
+
+	for _, strref := range slices.Sorted(maps.Keys(changed)) {
+		edits := changed[strref]
+		distinct := slices.CompactFunc(slices.SortedStableFunc(slices.Values(edits), func(a, b CanvasText) int {
+			return cmp.Compare(a.Text, b.Text)
+		}), func(a, b CanvasText) bool { return a.Text == b.Text })
+
+		if len(distinct) > 1 {
+			result.Conflicts = append(result.Conflicts, TextConflict{StrRef: strref, Original: originals[strref], Texts: edits})
+			continue
+		}
+		result.Edits = append(result.Edits, TextEdit{StrRef: strref, Text: edits[0].Text, Original: originals[strref], Sources: edits})
+	}
+
+	return result
+}
+
+func normalizeLineEndings(s string) string {
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
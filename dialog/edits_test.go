@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"testing"
+)
+
+func TestCompareTexts(t *testing.T) {
+	first := testDialog().ToDCanvas(FormatOptions{})
+	second := testDialog().ToDCanvas(FormatOptions{})
+	for _, n := range first.Nodes {
+		switch n.ID {
+		case "state-ABC[0]":
+			n.Text = "Hello and welcome"
+		case "transition-ABC[0]":
+			n.Text = "Who's there?"
+		case "state-XYZ[2]":
+			n.Text = "It's\nme."
+		}
+	}
+	for _, n := range second.Nodes {
+		switch n.ID {
+		case "transition-ABC[0]":
+			n.Text = "Who is here?"
+		case "state-XYZ[2]":
+			n.Text = "It's\r\nme."
+		}
+	}
+
+	tlk := map[uint32]string{10: "Hello & welcome", 11: "Who is there?", 12: "It's\nme."}
+	texts := append(CanvasTexts(first, "first.d.canvas"), CanvasTexts(second, "second.d.canvas")...)
+	result := CompareTexts(texts, func(strref uint32) (string, bool) {
+		s, ok := tlk[strref]
+		return s, ok
+	})
+
+	if len(result.Edits) != 1 || result.Edits[0].StrRef != 10 || result.Edits[0].Text != "Hello and welcome" ||
+		result.Edits[0].Original != "Hello & welcome" || result.Edits[0].Sources[0].Source != "first.d.canvas" {
+		t.Errorf("edits = %+v, want only #10 from first.d.canvas", result.Edits)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].StrRef != 11 || len(result.Conflicts[0].Texts) != 2 {
+		t.Errorf("conflicts = %+v, want #11 with two texts", result.Conflicts)
+	}
+	if len(result.Unknown) != 0 {
+		t.Errorf("unknown = %+v, want none", result.Unknown)
+	}
+}
@@ -39,6 +39,26 @@ func formatSound(name, prefix, suffix string) string {
 }
 
 func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
+	return d.Layout(opts).Canvas
+}
+
+// Layout is the canvas of a dialog with the IDs of its loop edges, which
+// lead back to already visited states and are not used to place nodes.
+type Layout struct {
+	Canvas *dcanvas.Canvas
+	Loops  map[string]bool
+}
+
+// Builds the canvas of the dialog and places its nodes.
+func (d *Dialog) Layout(opts FormatOptions) *Layout {
+	l, layoutEdges := d.graph(opts)
+	d.placeNodes(l.Canvas, layoutEdges)
+	return l
+}
+
+// Builds the canvas of the dialog with all nodes at the origin. Returns
+// also the edges used to place the nodes.
+func (d *Dialog) graph(opts FormatOptions) (*Layout, [][]string) {
 	c := &dcanvas.Canvas{
 		Version: "2.0",
 		Nodes:   []*dcanvas.Node{},
@@ -51,7 +71,7 @@ func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
 	colorIndex := 0
 
 	edges := make(map[string]*dcanvas.Edge)
-	nodes := make(map[string]*dcanvas.Node)
+	loops := make(map[string]bool)
 	layoutEdges := make([][]string, 0)
 	for _, dNode := range d.All() {
 		if dNode.IsEmptyTransition() {
@@ -68,7 +88,6 @@ func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
 			cNode := newNode(d, dNode, dlgNameToColor, opts)
 			if cNode != nil {
 				c.Nodes = append(c.Nodes, cNode)
-				nodes[cNode.ID] = cNode
 			}
 
 			if dNode.Parent != nil {
@@ -78,7 +97,9 @@ func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
 				}
 
 				edges[cEdge.ID] = cEdge
-				if !loop {
+				if loop {
+					loops[cEdge.ID] = true
+				} else {
 					layoutEdges = append(layoutEdges, []string{cEdge.FromNode, cEdge.ToNode})
 				}
 				c.Edges = append(c.Edges, cEdge)
@@ -86,10 +107,20 @@ func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
 		}
 	}
 
+	return &Layout{Canvas: c, Loops: loops}, layoutEdges
+}
+
+// Places the nodes of the canvas along the layout edges.
+func (d *Dialog) placeNodes(c *dcanvas.Canvas, layoutEdges [][]string) {
 	// Validate graph structure before layout
-	if len(nodes) == 0 {
+	if len(c.Nodes) == 0 {
 		fmt.Printf("warning(%s): no nodes to layout, skipping autolayout", d.Id)
-		return c
+		return
+	}
+
+	nodes := make(map[string]*dcanvas.Node, len(c.Nodes))
+	for _, n := range c.Nodes {
+		nodes[n.ID] = n
 	}
 
 	// Add panic recovery for the autog.Layout call
@@ -125,8 +156,6 @@ func (d *Dialog) ToDCanvas(opts FormatOptions) *dcanvas.Canvas {
 			}
 		}
 	}
-
-	return c
 }
 
 func newNode(d *Dialog, node *Node, dlgNameToColor map[string]string, opts FormatOptions) *dcanvas.Node {
@@ -238,14 +267,5 @@ func newCharacter(name, portrait, gender string) *dcanvas.Character {
 }
 
 func (n *Node) ToUrl(baseUrl string) string {
-	dialogName := strings.TrimSuffix(strings.ToLower(n.Dialog.Id.DlgName), ".dlg")
-	dialogId := fmt.Sprintf("%s-%d", dialogName, n.Dialog.Id.Index)
-	return fmt.Sprintf(
-		"%s/dialog/%s#%s-%s-%d-",
-		strings.TrimRight(baseUrl, "/"),
-		dialogId,
-		n.Type,
-		strings.ToLower(n.Origin.DlgName),
-		n.Origin.Index,
-	)
+	return fmt.Sprintf("%s/dialog/%s#%s", strings.TrimRight(baseUrl, "/"), n.Dialog.PageName(), n.Anchor())
 }
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type LinkKind string
+
+const (
+	// A transition handing the dialog over to another DLG file.
+	ExternLink LinkKind = "extern"
+	// A transition into another DLG file which returns to this one, like
+	// the interjection of a companion, and the transition returning.
+	InterjectionLink LinkKind = "interjection"
+	// A transition from or to a banter file.
+	BanterLink LinkKind = "banter"
+)
+
+// DlgLink is a transition from a state of one DLG file to a state of
+// another one.
+type DlgLink struct {
+	From       NodeOrigin
+	Transition uint32
+	To         NodeOrigin
+	Kind       LinkKind
+}
+
+// DlgGraph is the graph of transitions between DLG files.
+type DlgGraph struct {
+	Names []string  // DLG files with links, sorted
+	Links []DlgLink // sorted by state and transition
+}
+
+type linkKey struct {
+	from       NodeOrigin
+	transition uint32
+}
+
+// Builds the graph of links between the DLG files, keyed by their names.
+// Links from or to the banter files are banters.
+func BuildDlgGraph(files map[string]*DlgWriteFile, banters map[string]bool) *DlgGraph {
+	g := &DlgGraph{}
+	index := make(map[linkKey]int)
+	names := make(map[string]bool)
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		f := files[name]
+		for s, state := range f.States {
+			for _, i := range stateTransitions(f, state) {
+				t := f.Transitions[i]
+				if t.IsDialogEnd || t.NextStateResource == "" || strings.EqualFold(t.NextStateResource, name) {
+					continue
+				}
+				link := DlgLink{
+					From:       NewNodeOrigin(strings.ToUpper(name), uint32(s)),
+					Transition: i,
+					To:         NewNodeOrigin(strings.ToUpper(t.NextStateResource), t.NextStateIndex),
+					Kind:       ExternLink,
+				}
+				index[linkKey{link.From, i}] = len(g.Links)
+				g.Links = append(g.Links, link)
+				names[link.From.DlgName], names[link.To.DlgName] = true, true
+			}
+		}
+	}
+
+	upper := make(map[string]*DlgWriteFile, len(files))
+	for name, f := range files {
+		upper[strings.ToUpper(name)] = f
+	}
+	for i := range g.Links {
+		link := &g.Links[i]
+		if banters[link.From.DlgName] || banters[link.To.DlgName] {
+			link.Kind = BanterLink
+			continue
+		}
+		returns := returnLinks(upper[link.To.DlgName], link.To, link.From.DlgName)
+		if len(returns) == 0 {
+			continue
+		}
+		link.Kind = InterjectionLink
+		for _, r := range returns {
+			if j, ok := index[r]; ok {
+				g.Links[j].Kind = InterjectionLink
+			}
+		}
+	}
+
+	g.Names = slices.Sorted(maps.Keys(names))
+	return g
+}
+
+// Returns the indexes of the transitions of a state which are in range.
+func stateTransitions(f *DlgWriteFile, state DlgWriteState) []uint32 {
+	var indexes []uint32
+	for i := state.FirstTransition; i < state.FirstTransition+state.NumTransitions && int(i) < len(f.Transitions); i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// Returns the transitions to the target DLG file reachable from the state
+// without leaving its DLG file.
+func returnLinks(f *DlgWriteFile, start NodeOrigin, target string) []linkKey {
+	if f == nil {
+		return nil
+	}
+	var returns []linkKey
+	visited := map[uint32]bool{start.Index: true}
+	queue := []uint32{start.Index}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		if int(s) >= len(f.States) {
+			continue
+		}
+		for _, i := range stateTransitions(f, f.States[s]) {
+			t := f.Transitions[i]
+			switch {
+			case t.IsDialogEnd:
+			case strings.EqualFold(t.NextStateResource, target):
+				returns = append(returns, linkKey{NewNodeOrigin(start.DlgName, s), i})
+			case strings.EqualFold(t.NextStateResource, start.DlgName) && !visited[t.NextStateIndex]:
+				visited[t.NextStateIndex] = true
+				queue = append(queue, t.NextStateIndex)
+			}
+		}
+	}
+	return returns
+}
+
+// Returns the graph of the links from or to the DLG files.
+func (g *DlgGraph) Subgraph(names ...string) *DlgGraph {
+	sub := &DlgGraph{}
+	keep := make(map[string]bool)
+	for _, name := range names {
+		keep[strings.ToUpper(name)] = true
+	}
+	linked := make(map[string]bool)
+	for _, l := range g.Links {
+		if keep[l.From.DlgName] || keep[l.To.DlgName] {
+			sub.Links = append(sub.Links, l)
+			linked[l.From.DlgName], linked[l.To.DlgName] = true, true
+		}
+	}
+	sub.Names = slices.Sorted(maps.Keys(linked))
+	return sub
+}
+
+// Returns the links from states of the DLG file.
+func (g *DlgGraph) Outbound(name string) []DlgLink {
+	var links []DlgLink
+	for _, l := range g.Links {
+		if strings.EqualFold(l.From.DlgName, name) {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// Returns the links to states of the DLG file, sorted by the target state.
+func (g *DlgGraph) Inbound(name string) []DlgLink {
+	var links []DlgLink
+	for _, l := range g.Links {
+		if strings.EqualFold(l.To.DlgName, name) {
+			links = append(links, l)
+		}
+	}
+	slices.SortStableFunc(links, func(a, b DlgLink) int {
+		return cmp.Compare(a.To.Index, b.To.Index)
+	})
+	return links
+}
+
+type jsonDlgLink struct {
+	From       string   `json:"from"`
+	Transition uint32   `json:"transition"`
+	To         string   `json:"to"`
+	Kind       LinkKind `json:"kind"`
+}
+
+type jsonDlgFile struct {
+	Name     string        `json:"name"`
+	Outbound []jsonDlgLink `json:"outbound"`
+	Inbound  []jsonDlgLink `json:"inbound"`
+}
+
+func toJsonLinks(links []DlgLink) []jsonDlgLink {
+	result := make([]jsonDlgLink, 0, len(links))
+	for _, l := range links {
+		result = append(result, jsonDlgLink{From: l.From.String(), Transition: l.Transition, To: l.To.String(), Kind: l.Kind})
+	}
+	return result
+}
+
+// Writes the graph as JSON: the DLG files with their outbound and inbound
+// links.
+func (g *DlgGraph) WriteJson(w io.Writer) error {
+	files := make([]jsonDlgFile, 0, len(g.Names))
+	for _, name := range g.Names {
+		files = append(files, jsonDlgFile{
+			Name:     name,
+			Outbound: toJsonLinks(g.Outbound(name)),
+			Inbound:  toJsonLinks(g.Inbound(name)),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(struct {
+		Files []jsonDlgFile `json:"files"`
+	}{files}); err != nil {
+		return fmt.Errorf("error writing JSON of dialog graph: %w", err)
+	}
+	return nil
+}
+
+// Writes the graph as a Graphviz DOT graph with an edge for each pair of
+// DLG files and link kind, labeled with the states it links. Interjections
+// are dashed and banters dotted.
+func (g *DlgGraph) WriteDot(w io.Writer) error {
+	type edge struct {
+		from, to string
+		kind     LinkKind
+	}
+	var edges []edge
+	states := make(map[edge][]string)
+	for _, l := range g.Links {
+		e := edge{l.From.DlgName, l.To.DlgName, l.Kind}
+		if _, ok := states[e]; !ok {
+			edges = append(edges, e)
+		}
+		states[e] = append(states[e], fmt.Sprintf("%d→%d", l.From.Index, l.To.Index))
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph \"dialogs\" {\n")
+	bw.WriteString("  node [shape=box, style=rounded, fontname=\"sans-serif\", fontsize=11];\n")
+	bw.WriteString("  edge [fontname=\"sans-serif\", fontsize=9];\n")
+	for _, name := range g.Names {
+		fmt.Fprintf(bw, "  \"%s\";\n", dotEscaper.Replace(name))
+	}
+	for _, e := range edges {
+		attrs := []string{fmt.Sprintf("label=\"%s\"", dotEscaper.Replace(truncate(strings.Join(states[e], ", "), edgeLabelLength)))}
+		switch e.kind {
+		case InterjectionLink:
+			attrs = append(attrs, "style=dashed")
+		case BanterLink:
+			attrs = append(attrs, "style=dotted")
+		}
+		fmt.Fprintf(bw, "  \"%s\" -> \"%s\" [%s];\n", dotEscaper.Replace(e.from), dotEscaper.Replace(e.to), strings.Join(attrs, ", "))
+	}
+	bw.WriteString("}\n")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing DOT of dialog graph: %w", err)
+	}
+	return nil
+}
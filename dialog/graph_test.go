@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A DLG file with a state for each list of next states. An empty origin
+// ends the dialog.
+func testLinkedDlg(next ...[]NodeOrigin) *DlgWriteFile {
+	f := &DlgWriteFile{}
+	for _, targets := range next {
+		f.States = append(f.States, DlgWriteState{FirstTransition: uint32(len(f.Transitions)), NumTransitions: uint32(len(targets))})
+		for _, target := range targets {
+			t := NewEmptyDlgTransition()
+			t.NextStateResource, t.NextStateIndex = target.DlgName, target.Index
+			t.IsDialogEnd = target.DlgName == ""
+			f.Transitions = append(f.Transitions, t)
+		}
+	}
+	return f
+}
+
+func TestBuildDlgGraph(t *testing.T) {
+	files := map[string]*DlgWriteFile{
+		// An interjection of JAHEIRJ returning to state 2 of MAIN through
+		// state 1 of JAHEIRJ.
+		"MAIN": testLinkedDlg(
+			[]NodeOrigin{{"MAIN", 1}, {"JAHEIRJ", 0}},
+			[]NodeOrigin{{"OTHER", 4}},
+			[]NodeOrigin{{}},
+		),
+		"JAHEIRJ": testLinkedDlg(
+			[]NodeOrigin{{"JAHEIRJ", 1}},
+			[]NodeOrigin{{"MAIN", 2}},
+		),
+		"BJAHEIR": testLinkedDlg([]NodeOrigin{{"BKHALID", 0}}),
+	}
+
+	g := BuildDlgGraph(files, map[string]bool{"BJAHEIR": true, "BKHALID": true})
+
+	want := []DlgLink{
+		{From: NodeOrigin{"BJAHEIR", 0}, Transition: 0, To: NodeOrigin{"BKHALID", 0}, Kind: BanterLink},
+		{From: NodeOrigin{"JAHEIRJ", 1}, Transition: 1, To: NodeOrigin{"MAIN", 2}, Kind: InterjectionLink},
+		{From: NodeOrigin{"MAIN", 0}, Transition: 1, To: NodeOrigin{"JAHEIRJ", 0}, Kind: InterjectionLink},
+		{From: NodeOrigin{"MAIN", 1}, Transition: 2, To: NodeOrigin{"OTHER", 4}, Kind: ExternLink},
+	}
+	if !reflect.DeepEqual(g.Links, want) {
+		t.Errorf("links:\ngot  %+v\nwant %+v", g.Links, want)
+	}
+	if want := []string{"BJAHEIR", "BKHALID", "JAHEIRJ", "MAIN", "OTHER"}; !reflect.DeepEqual(g.Names, want) {
+		t.Errorf("names = %v, want %v", g.Names, want)
+	}
+	if in := g.Inbound("MAIN"); len(in) != 1 || in[0].From.DlgName != "JAHEIRJ" {
+		t.Errorf("inbound of MAIN = %+v", in)
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteJson(&buf); err != nil {
+		t.Fatalf("WriteJson() error = %v", err)
+	}
+	var decoded struct {
+		Files []jsonDlgFile `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Files) != 5 || decoded.Files[3].Outbound[0].To != "JAHEIRJ[0]" {
+		t.Errorf("unexpected JSON:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := g.WriteDot(&buf); err != nil {
+		t.Fatalf("WriteDot() error = %v", err)
+	}
+	for _, s := range []string{`"MAIN" -> "JAHEIRJ" [label="0→0", style=dashed]`, `"BJAHEIR" -> "BKHALID" [label="0→0", style=dotted]`, `"MAIN" -> "OTHER" [label="1→4"]`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("DOT does not contain %q:\n%s", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Returns the page name of the dialog used in URLs: "<dlg>-<root state>".
+func (d *Dialog) PageName() string {
+	dialogName := strings.TrimSuffix(strings.ToLower(d.Id.DlgName), ".dlg")
+	return fmt.Sprintf("%s-%d", dialogName, d.Id.Index)
+}
+
+// Returns the anchor of the node on the dialog page, as used by ToUrl.
+func (n *Node) Anchor() string {
+	return fmt.Sprintf("%s-%s-%d-", n.Type, strings.ToLower(n.Origin.DlgName), n.Origin.Index)
+}
+
+// Returns the name of the speaker of the DLG file: the creature name if
+// known, otherwise the DLG name.
+func (d *Dialog) Speaker(dlgName string) string {
+	if cre, ok := d.AllCreatures[dlgName]; ok && cre.LongName != "" {
+		return cre.LongName
+	}
+	return strings.ToUpper(dlgName)
+}
+
+// SiteLinker resolves links between pages of a dialog site. Dialog pages
+// are <root>/dialog/<page name>/index.html.
+type SiteLinker struct {
+	// Dialogs by root state.
+	roots map[NodeOrigin]*Dialog
+	// Portrait image paths relative to the site root by creature portrait
+	// name; empty if not exported.
+	Portraits map[string]string
+}
+
+func NewSiteLinker(collection *DialogCollection) *SiteLinker {
+	l := &SiteLinker{
+		roots:     make(map[NodeOrigin]*Dialog, len(collection.Dialogs)),
+		Portraits: make(map[string]string),
+	}
+	for _, d := range collection.Dialogs {
+		l.roots[NewNodeOrigin(strings.ToUpper(d.Id.DlgName), d.Id.Index)] = d
+	}
+	return l
+}
+
+// Returns the link from a dialog page to the other dialog starting at the
+// state, or to the DLG section of the index if there is no such dialog.
+func (l *SiteLinker) stateLink(origin NodeOrigin) (string, string) {
+	if d, ok := l.roots[NewNodeOrigin(strings.ToUpper(origin.DlgName), origin.Index)]; ok {
+		return "../" + d.PageName() + "/", d.Id.String()
+	}
+	return "../../index.html#dlg-" + strings.ToLower(origin.DlgName), strings.ToUpper(origin.DlgName)
+}
+
+type htmlNode struct {
+	Kind     string
+	Anchor   string
+	Origin   string
+	Speaker  string
+	Portrait string
+	TextRef  string
+	Text     string
+	Sound    string
+	Trigger  string
+	Action   string
+	Journal  string
+	End      bool
+	Extern   string // DLG name of the next state in another file
+	Link     string
+	LinkText string
+	Children []*htmlNode
+}
+
+func (l *SiteLinker) newHtmlNode(d *Dialog, node *Node) *htmlNode {
+	h := &htmlNode{
+		Kind:   node.Type.String(),
+		Anchor: node.Anchor(),
+		Origin: node.Origin.String(),
+	}
+
+	switch node.Type {
+	case StateNodeType:
+		h.Speaker = d.Speaker(node.Origin.DlgName)
+		if cre, ok := d.AllCreatures[node.Origin.DlgName]; ok && l.Portraits[cre.Portrait] != "" {
+			h.Portrait = "../../" + l.Portraits[cre.Portrait]
+		}
+		h.TextRef = fmt.Sprintf("#%d", node.State.TextRef)
+		h.Text = node.State.Text
+		h.Sound = node.State.Sound
+		h.Trigger = strings.TrimSpace(node.State.Trigger)
+		if node.Parent != nil && node.Parent.Origin.DlgName != node.Origin.DlgName {
+			h.Extern = strings.ToUpper(node.Origin.DlgName)
+			h.Link, h.LinkText = l.stateLink(node.Origin)
+		}
+
+	case TransitionNodeType:
+		t := node.Transition
+		if t.HasText {
+			h.TextRef = fmt.Sprintf("#%d", t.TextRef)
+			h.Text = t.Text
+			h.Sound = t.Sound
+		}
+		if t.HasJournalText {
+			h.Journal = t.JournalText
+		}
+		h.Trigger = strings.TrimSpace(t.Trigger)
+		h.Action = strings.TrimSpace(t.Action)
+		h.End = t.IsDialogEnd
+
+	case LoopNodeType:
+		// The state is already on the page under the same anchor.
+		h.Kind = "loop"
+		h.Anchor = ""
+		h.Link = "#" + node.Anchor()
+		h.LinkText = node.Origin.String()
+
+	case ErrorNodeType:
+		h.Text = "Error loading state"
+	}
+
+	for _, child := range node.Children {
+		if child != nil {
+			h.Children = append(h.Children, l.newHtmlNode(d, child))
+		}
+	}
+	return h
+}
+
+// Writes the HTML page of the dialog. Node anchors match Node.ToUrl.
+func (l *SiteLinker) WriteDialogHtml(w io.Writer, d *Dialog) error {
+	if d.RootState == nil {
+		return fmt.Errorf("dialog %s has no root state", d.Id)
+	}
+
+	data := struct {
+		Title   string
+		Speaker string
+		Root    *htmlNode
+	}{
+		Title:   d.Id.String(),
+		Speaker: d.Speaker(d.Id.DlgName),
+		Root:    l.newHtmlNode(d, d.RootState),
+	}
+
+	if err := dialogPageTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("error writing page of dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
+
+type htmlIndexDialog struct {
+	Title  string
+	Href   string
+	Text   string
+	Search string
+}
+
+type htmlIndexDlg struct {
+	Name    string
+	Anchor  string
+	Dialogs []htmlIndexDialog
+}
+
+type htmlIndexSpeaker struct {
+	Name     string
+	Portrait string
+	Dlgs     []*htmlIndexDlg
+}
+
+// Writes the index page listing dialogs per speaker, with a search box.
+func (l *SiteLinker) WriteIndexHtml(w io.Writer, title string, collection *DialogCollection) error {
+	speakers := make(map[string]*htmlIndexSpeaker)
+	dlgs := make(map[string]*htmlIndexDlg)
+
+	for _, d := range collection.Dialogs {
+		name := d.Speaker(d.Id.DlgName)
+		speaker, ok := speakers[name]
+		if !ok {
+			speaker = &htmlIndexSpeaker{Name: name}
+			if cre, ok := d.AllCreatures[d.Id.DlgName]; ok {
+				speaker.Portrait = l.Portraits[cre.Portrait]
+			}
+			speakers[name] = speaker
+		}
+
+		dlgName := strings.ToUpper(d.Id.DlgName)
+		dlg, ok := dlgs[dlgName]
+		if !ok {
+			dlg = &htmlIndexDlg{Name: dlgName, Anchor: "dlg-" + strings.ToLower(dlgName)}
+			dlgs[dlgName] = dlg
+			speaker.Dlgs = append(speaker.Dlgs, dlg)
+		}
+
+		text := ""
+		if d.RootState != nil && d.RootState.State != nil {
+			text = d.RootState.State.Text
+		}
+		dlg.Dialogs = append(dlg.Dialogs, htmlIndexDialog{
+			Title:  d.Id.String(),
+			Href:   "dialog/" + d.PageName() + "/",
+			Text:   text,
+			Search: strings.ToLower(strings.Join([]string{name, d.Id.String(), text}, " ")),
+		})
+	}
+
+	sorted := make([]*htmlIndexSpeaker, 0, len(speakers))
+	for _, speaker := range speakers {
+		slices.SortFunc(speaker.Dlgs, func(a, b *htmlIndexDlg) int { return cmp.Compare(a.Name, b.Name) })
+		sorted = append(sorted, speaker)
+	}
+	slices.SortFunc(sorted, func(a, b *htmlIndexSpeaker) int { return cmp.Compare(a.Name, b.Name) })
+
+	data := struct {
+		Title    string
+		Count    int
+		Speakers []*htmlIndexSpeaker
+	}{title, len(collection.Dialogs), sorted}
+
+	if err := indexPageTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("error writing index page: %w", err)
+	}
+	return nil
+}
+
+const siteStyle = `
+body { font-family: sans-serif; max-width: 60em; margin: 0 auto; padding: 1em; color: #222; }
+a { color: #2a5db0; }
+.node { border-left: 3px solid #ccc; margin: .5em 0 .5em 1em; padding: .3em .6em; }
+.node:target { background: #fff6d5; }
+.state { border-color: #4a90d9; }
+.transition { border-color: #7cb342; }
+.transition.end { border-color: #e57373; }
+.loop, .error { border-color: #999; font-style: italic; }
+.head { font-size: .85em; color: #666; }
+.head .anchor { text-decoration: none; color: #aaa; }
+.speaker { font-weight: bold; color: #222; }
+.portrait { float: left; height: 3.5em; margin-right: .6em; }
+.text { white-space: pre-wrap; margin: .3em 0; clear: none; }
+.code { font-family: monospace; white-space: pre-wrap; font-size: .85em; background: #f4f4f4; padding: .2em .4em; margin: .2em 0; }
+.code b { font-family: sans-serif; font-weight: normal; color: #666; }
+.journal { color: #6d4c41; }
+.children { clear: both; }
+.hidden { display: none; }
+input[type=search] { width: 100%; font-size: 1.1em; padding: .3em; box-sizing: border-box; }
+.dialogs li { margin: .2em 0; }
+.dialogs .snippet { color: #666; }
+`
+
+var dialogPageTemplate = template.Must(template.New("dialog").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Speaker}} — {{.Title}}</title>
+<style>` + siteStyle + `</style>
+</head>
+<body>
+<p><a href="../../index.html">Index</a></p>
+<h1>{{.Speaker}} <small>{{.Title}}</small></h1>
+{{template "node" .Root}}
+</body>
+</html>
+{{define "node"}}<div class="node {{.Kind}}{{if .End}} end{{end}}"{{if .Anchor}} id="{{.Anchor}}"{{end}}>
+{{- if eq .Kind "loop"}}
+<div class="head">↻ back to <a href="{{.Link}}">{{.LinkText}}</a></div>
+{{- else}}
+<div class="head">
+{{- if .Portrait}}<img class="portrait" src="{{.Portrait}}" alt="">{{end}}
+{{- if .Speaker}}<span class="speaker">{{.Speaker}}</span> {{end}}
+{{- if .Extern}}EXTERN <a href="{{.Link}}">{{.LinkText}}</a> {{end}}
+{{- .Kind}} {{.Origin}}{{if .TextRef}} · {{.TextRef}}{{end}}{{if .Sound}} · ♪ {{.Sound}}{{end}}
+ <a class="anchor" href="#{{.Anchor}}">¶</a></div>
+{{- if .Trigger}}<div class="code"><b>if</b> {{.Trigger}}</div>{{end}}
+{{- if .Text}}<div class="text">{{.Text}}</div>{{else if eq .Kind "transition"}}<div class="text head">(no text)</div>{{end}}
+{{- if .Journal}}<div class="text journal">📖 {{.Journal}}</div>{{end}}
+{{- if .Action}}<div class="code"><b>do</b> {{.Action}}</div>{{end}}
+{{- if .End}}<div class="head">■ end of dialog</div>{{end}}
+{{- end}}
+{{- if .Children}}<div class="children">{{range .Children}}{{template "node" .}}{{end}}</div>{{end}}
+</div>
+{{end}}`))
+
+var indexPageTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>` + siteStyle + `</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Count}} dialogs</p>
+<input type="search" id="search" placeholder="Search speakers, dialogs and first lines" autofocus>
+{{range .Speakers}}<section class="speaker-section">
+<h2>{{if .Portrait}}<img class="portrait" src="{{.Portrait}}" alt="">{{end}}{{.Name}}</h2>
+{{range .Dlgs}}<h3 id="{{.Anchor}}">{{.Name}}</h3>
+<ul class="dialogs">
+{{range .Dialogs}}<li data-search="{{.Search}}"><a href="{{.Href}}">{{.Title}}</a> <span class="snippet">{{.Text}}</span></li>
+{{end}}</ul>
+{{end}}</section>
+{{end}}
+<script>
+document.getElementById("search").addEventListener("input", function (e) {
+  var query = e.target.value.toLowerCase();
+  document.querySelectorAll(".speaker-section").forEach(function (section) {
+    var visible = 0;
+    section.querySelectorAll("li").forEach(function (li) {
+      var match = li.dataset.search.indexOf(query) >= 0;
+      li.classList.toggle("hidden", !match);
+      if (match) visible++;
+    });
+    section.classList.toggle("hidden", visible === 0);
+  });
+});
+</script>
+</body>
+</html>
+`))
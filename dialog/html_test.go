@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"strings"
+	"testing"
+)
+
+// ABC[0] -> ABC[0] -> XYZ[2] -> XYZ[5] -> loop to ABC[0]
+//
+//	`-> ABC[1] (end)
+func testDialog() *Dialog {
+	d := NewDialog(NewNodeOrigin("ABC.DLG", 0))
+	d.AllCreatures["ABC"] = &Creature{LongName: "Abbot <Brother>", Portrait: "ABBOT.BMP"}
+
+	root := &Node{Type: StateNodeType, Origin: NewNodeOrigin("ABC", 0), Dialog: d,
+		State: &StateData{TextRef: 10, Text: "Hello & welcome", HasTrigger: true, Trigger: "Global(\"X\",\"GLOBAL\",1)\n"}}
+	toXyz := &Node{Type: TransitionNodeType, Origin: NewNodeOrigin("ABC", 0), Dialog: d, Parent: root,
+		Transition: &TransitionData{HasText: true, TextRef: 11, Text: "Who is there?", NextStateOrigin: NewNodeOrigin("XYZ", 2)}}
+	end := &Node{Type: TransitionNodeType, Origin: NewNodeOrigin("ABC", 1), Dialog: d, Parent: root,
+		Transition: &TransitionData{HasAction: true, Action: "EscapeArea()", IsDialogEnd: true}}
+	xyz := &Node{Type: StateNodeType, Origin: NewNodeOrigin("XYZ", 2), Dialog: d, Parent: toXyz,
+		State: &StateData{TextRef: 12, Text: "Me."}}
+	back := &Node{Type: TransitionNodeType, Origin: NewNodeOrigin("XYZ", 5), Dialog: d, Parent: xyz,
+		Transition: &TransitionData{NextStateOrigin: NewNodeOrigin("ABC", 0)}}
+	loop := &Node{Type: LoopNodeType, Origin: NewNodeOrigin("ABC", 0), Dialog: d, Parent: back}
+
+	root.Children = []*Node{toXyz, end}
+	toXyz.Children = []*Node{xyz}
+	xyz.Children = []*Node{back}
+	back.Children = []*Node{loop}
+	d.RootState = root
+	for _, origin := range []NodeOrigin{root.Origin, xyz.Origin} {
+		d.AllStates[origin] = struct{}{}
+	}
+	return d
+}
+
+func TestWriteDialogHtml(t *testing.T) {
+	d := testDialog()
+	other := NewDialog(NewNodeOrigin("XYZ", 2))
+	collection := &DialogCollection{Dialogs: []*Dialog{d, other}}
+
+	linker := NewSiteLinker(collection)
+	linker.Portraits["ABBOT.BMP"] = "portraits/ABBOT.png"
+
+	var sb strings.Builder
+	if err := linker.WriteDialogHtml(&sb, d); err != nil {
+		t.Fatalf("WriteDialogHtml() error = %v", err)
+	}
+	page := sb.String()
+
+	xyzUrl := d.RootState.Children[0].Children[0].ToUrl("https://example.com/")
+	if want := "https://example.com/dialog/abc-0#state-xyz-2-"; xyzUrl != want {
+		t.Errorf("ToUrl() = %s, want %s", xyzUrl, want)
+	}
+
+	for _, want := range []string{
+		`id="state-abc-0-"`,
+		`id="transition-abc-1-"`,
+		`id="state-xyz-2-"`,
+		`<span class="speaker">Abbot &lt;Brother&gt;</span>`,
+		`src="../../portraits/ABBOT.png"`,
+		`Hello &amp; welcome`,
+		`EXTERN <a href="../xyz-2/">XYZ[2]</a>`,
+		`back to <a href="#state-abc-0-">ABC[0]</a>`,
+		`<b>do</b> EscapeArea()`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("WriteDialogHtml() = %s\nwant to contain %s", page, want)
+		}
+	}
+	if n := strings.Count(page, `id="state-abc-0-"`); n != 1 {
+		t.Errorf("WriteDialogHtml() has %d state-abc-0- anchors, want 1", n)
+	}
+
+	sb.Reset()
+	if err := linker.WriteIndexHtml(&sb, "BG", &DialogCollection{Dialogs: []*Dialog{d}}); err != nil {
+		t.Fatalf("WriteIndexHtml() error = %v", err)
+	}
+	for _, want := range []string{`<h3 id="dlg-abc">ABC</h3>`, `href="dialog/abc-0/"`, `data-search="abbot &lt;brother&gt; abc[0] hello &amp; welcome"`} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("WriteIndexHtml() = %s\nwant to contain %s", sb.String(), want)
+		}
+	}
+}
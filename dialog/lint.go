@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/bcs"
+)
+
+type LintKind string
+
+const (
+	// A state not reachable from any root state, in any DLG file.
+	OrphanState LintKind = "orphan-state"
+	// A transition to a missing DLG file or state, or a state with
+	// transitions out of range.
+	DanglingTransition LintKind = "dangling-transition"
+	// A state without text, or with an empty text.
+	EmptyState LintKind = "empty-state"
+	// A state from which the dialog never ends, as all its transitions lead
+	// back into a loop.
+	EndlessLoop LintKind = "endless-loop"
+	// A trigger or action which cannot be parsed or does not match
+	// TRIGGER.IDS or ACTION.IDS.
+	InvalidScript LintKind = "invalid-script"
+)
+
+type LintOptions struct {
+	// Returns the text of a strref, to report states with empty texts.
+	Text func(strref uint32) (string, bool)
+	// Functions of TRIGGER.IDS and ACTION.IDS, to check triggers and
+	// actions.
+	Triggers, Actions *bcs.Functions
+}
+
+// LintIssue is a problem of a state or a transition.
+type LintIssue struct {
+	Kind    LintKind
+	Type    NodeType // StateNodeType or TransitionNodeType
+	Origin  NodeOrigin
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s-%s: %s: %s", i.Type, i.Origin, i.Kind, i.Message)
+}
+
+// Checks the DLG files, keyed by their names, together, as transitions
+// lead from one file to another. Root states are the states with triggers.
+// Texts and scripts are checked if the options have them. Issues are sorted
+// by file, state or transition, and kind.
+func LintDlgFiles(files map[string]*DlgWriteFile, opts LintOptions) []LintIssue {
+	upper := make(map[string]*DlgWriteFile, len(files))
+	for name, f := range files {
+		upper[strings.ToUpper(name)] = f
+	}
+
+	var issues []LintIssue
+	// Next states of each state which exist, and whether the state ends the
+	// dialog or leads out of the known states.
+	next := make(map[NodeOrigin][]NodeOrigin)
+	ends := make(map[NodeOrigin]bool)
+
+	for _, name := range slices.Sorted(maps.Keys(upper)) {
+		f := upper[name]
+		for s, state := range f.States {
+			origin := NewNodeOrigin(name, uint32(s))
+
+			if uint64(state.FirstTransition)+uint64(state.NumTransitions) > uint64(len(f.Transitions)) {
+				issues = append(issues, LintIssue{DanglingTransition, StateNodeType, origin,
+					fmt.Sprintf("transitions %d+%d are out of range", state.FirstTransition, state.NumTransitions)})
+			}
+			if state.TextRef == dlgNoIndex {
+				issues = append(issues, LintIssue{EmptyState, StateNodeType, origin, "no text"})
+			} else if opts.Text != nil {
+				if content, ok := opts.Text(state.TextRef); !ok {
+					issues = append(issues, LintIssue{EmptyState, StateNodeType, origin, fmt.Sprintf("strref %d is not in the TLK file", state.TextRef)})
+				} else if strings.TrimSpace(content) == "" {
+					issues = append(issues, LintIssue{EmptyState, StateNodeType, origin, fmt.Sprintf("text of strref %d is empty", state.TextRef)})
+				}
+			}
+
+			if state.HasTrigger {
+				issues = append(issues, lintScript(StateNodeType, origin, "trigger", state.Trigger, opts.Triggers)...)
+			}
+
+			transitions := stateTransitions(f, state)
+			if len(transitions) == 0 {
+				ends[origin] = true
+			}
+			for _, i := range transitions {
+				t := f.Transitions[i]
+				if t.HasTrigger {
+					issues = append(issues, lintScript(TransitionNodeType, NewNodeOrigin(name, i), "trigger", t.Trigger, opts.Triggers)...)
+				}
+				if t.HasAction {
+					issues = append(issues, lintScript(TransitionNodeType, NewNodeOrigin(name, i), "action", t.Action, opts.Actions)...)
+				}
+				if t.IsDialogEnd {
+					ends[origin] = true
+					continue
+				}
+				target := NewNodeOrigin(strings.ToUpper(t.NextStateResource), t.NextStateIndex)
+				targetFile, ok := upper[target.DlgName]
+				switch {
+				case !ok:
+					issues = append(issues, LintIssue{DanglingTransition, TransitionNodeType, NewNodeOrigin(name, i),
+						fmt.Sprintf("%s.DLG is not loaded", target.DlgName)})
+					ends[origin] = true
+				case int(target.Index) >= len(targetFile.States):
+					issues = append(issues, LintIssue{DanglingTransition, TransitionNodeType, NewNodeOrigin(name, i),
+						fmt.Sprintf("state %s is out of range", target)})
+					ends[origin] = true
+				default:
+					next[origin] = append(next[origin], target)
+				}
+			}
+		}
+	}
+
+	// States reachable from root states.
+	reachable := make(map[NodeOrigin]bool)
+	var queue []NodeOrigin
+	for name, f := range upper {
+		for s, state := range f.States {
+			if state.HasTrigger {
+				origin := NewNodeOrigin(name, uint32(s))
+				reachable[origin] = true
+				queue = append(queue, origin)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		origin := queue[0]
+		queue = queue[1:]
+		for _, n := range next[origin] {
+			if !reachable[n] {
+				reachable[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	// States that can end the dialog, found backwards from the ends.
+	previous := make(map[NodeOrigin][]NodeOrigin)
+	for origin, targets := range next {
+		for _, n := range targets {
+			previous[n] = append(previous[n], origin)
+		}
+	}
+	ending := make(map[NodeOrigin]bool)
+	for origin := range ends {
+		ending[origin] = true
+		queue = append(queue, origin)
+	}
+	for len(queue) > 0 {
+		origin := queue[0]
+		queue = queue[1:]
+		for _, p := range previous[origin] {
+			if !ending[p] {
+				ending[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	for name, f := range upper {
+		for s := range f.States {
+			origin := NewNodeOrigin(name, uint32(s))
+			if !reachable[origin] {
+				issues = append(issues, LintIssue{OrphanState, StateNodeType, origin, "not reachable from any root state"})
+			}
+			if !ending[origin] {
+				issues = append(issues, LintIssue{EndlessLoop, StateNodeType, origin, "the dialog never ends from this state"})
+			}
+		}
+	}
+
+	kinds := []LintKind{OrphanState, DanglingTransition, EmptyState, EndlessLoop, InvalidScript}
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Origin.DlgName, b.Origin.DlgName),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Origin.Index, b.Origin.Index),
+			cmp.Compare(slices.Index(kinds, a.Kind), slices.Index(kinds, b.Kind)),
+		)
+	})
+	return issues
+}
+
+// Checks a trigger or action against the functions, if there are any.
+func lintScript(typ NodeType, origin NodeOrigin, what, code string, funcs *bcs.Functions) []LintIssue {
+	if funcs == nil {
+		return nil
+	}
+	script, err := bcs.Parse(code)
+	if err != nil {
+		return []LintIssue{{InvalidScript, typ, origin, fmt.Sprintf("%s: %v", what, err)}}
+	}
+	var issues []LintIssue
+	for _, p := range script.Resolve(funcs) {
+		issues = append(issues, LintIssue{InvalidScript, typ, origin, fmt.Sprintf("%s: %s", what, p)})
+	}
+	return issues
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/bcs"
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+func TestLintDlgFiles(t *testing.T) {
+	a := testLinkedDlg(
+		[]NodeOrigin{{"A", 1}, {"B", 0}},
+		[]NodeOrigin{{}},
+		[]NodeOrigin{{"A", 3}},
+		[]NodeOrigin{{"A", 2}},
+		[]NodeOrigin{{"B", 5}, {"C", 0}},
+	)
+	a.States[0].HasTrigger, a.States[0].Trigger = true, "NumTimesTalkedTo(0)\n"
+	a.States[4].HasTrigger, a.States[4].Trigger = true, "Globl(\"X\",\"GLOBAL\",1)\n"
+	a.Transitions[0].HasAction, a.Transitions[0].Action = true, "SetGlobal(\"X\",\"GLOBAL\")"
+	a.States[1].TextRef = dlgNoIndex
+	a.States[4].TextRef = 1
+	b := testLinkedDlg([]NodeOrigin{})
+
+	text := func(strref uint32) (string, bool) {
+		return []string{"Hello", " "}[strref], true
+	}
+	triggers, err := p.ParseIds(strings.NewReader("0x4023 Global(S:Name*,S:Area*,I:Value*)\n0x400E NumTimesTalkedTo(I:Num*)\n"))
+	if err != nil {
+		t.Fatalf("ParseIds() error = %v", err)
+	}
+	actions, err := p.ParseIds(strings.NewReader("30 SetGlobal(S:Name*,S:Area*,I:Value*)\n"))
+	if err != nil {
+		t.Fatalf("ParseIds() error = %v", err)
+	}
+
+	issues := LintDlgFiles(map[string]*DlgWriteFile{"a": a, "B": b}, LintOptions{
+		Text:     text,
+		Triggers: bcs.NewFunctions(triggers),
+		Actions:  bcs.NewFunctions(actions),
+	})
+
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	want := []string{
+		"state-A[1]: empty-state: no text",
+		"state-A[2]: orphan-state: not reachable from any root state",
+		"state-A[2]: endless-loop: the dialog never ends from this state",
+		"state-A[3]: orphan-state: not reachable from any root state",
+		"state-A[3]: endless-loop: the dialog never ends from this state",
+		"state-A[4]: empty-state: text of strref 1 is empty",
+		"state-A[4]: invalid-script: trigger: line 1: unknown function Globl",
+		"transition-A[0]: invalid-script: action: line 1: SetGlobal takes 3 arguments, got 2",
+		"transition-A[5]: dangling-transition: state B[5] is out of range",
+		"transition-A[6]: dangling-transition: C.DLG is not loaded",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues:\ngot  %q\nwant %q", got, want)
+	}
+}
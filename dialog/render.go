@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sbtlocalization/sbt-infinity/dcanvas"
+)
+
+// Colors of the JSON Canvas color presets used for nodes: border and fill.
+var canvasColors = map[string][2]string{
+	"1": {"#e03e3e", "#fbe4e4"},
+	"2": {"#d9730d", "#faebdd"},
+	"3": {"#c29b00", "#fbf3db"},
+	"4": {"#0f7b6c", "#ddedea"},
+	"5": {"#0b6e99", "#ddebf1"},
+	"6": {"#6940a5", "#eae4f2"},
+}
+
+const (
+	loopColor       = "#888888"
+	svgMargin       = 40
+	svgFontSize     = 14
+	svgLineHeight   = 18
+	svgPadding      = 10
+	svgCharWidth    = 7.5
+	dotLineWidth    = 40
+	edgeLabelLength = 60
+)
+
+func nodeColors(color string) (string, string) {
+	if c, ok := canvasColors[color]; ok {
+		return c[0], c[1]
+	}
+	return "#555555", "#ffffff"
+}
+
+// Returns the title and the body lines of a node: trigger, text, journal
+// text and action.
+func nodeContent(n *dcanvas.Node) (string, []string) {
+	title := n.NodeId
+	if n.Character != nil && n.Character.Name != "" {
+		title = n.Character.Name
+	}
+	if n.TextId != "" {
+		title += " · " + n.TextId
+	}
+
+	var body []string
+	if n.NodeRole == "state" && n.Trigger != "" {
+		body = append(body, "if "+n.Trigger)
+	}
+	if n.Text != "" {
+		body = append(body, n.Text)
+	}
+	if n.JournalText != "" {
+		body = append(body, "journal: "+n.JournalText)
+	}
+	if n.Action != "" {
+		body = append(body, "do "+n.Action)
+	}
+	return title, body
+}
+
+// Wraps the text to lines of at most width characters, breaking at spaces
+// where possible.
+func wrapText(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func truncate(s string, length int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length-1]) + "…"
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\l`)
+
+// Writes the dialog as a Graphviz DOT graph. Nodes are placed by Graphviz;
+// loop edges are dashed and do not affect the ranking of nodes.
+func (d *Dialog) WriteDot(w io.Writer, opts FormatOptions) error {
+	l, _ := d.graph(opts)
+	c, loops := l.Canvas, l.Loops
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "digraph \"%s\" {\n", dotEscaper.Replace(d.Id.String()))
+	bw.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"sans-serif\", fontsize=11];\n")
+	bw.WriteString("  edge [fontname=\"sans-serif\", fontsize=9];\n")
+
+	for _, n := range c.Nodes {
+		title, body := nodeContent(n)
+		lines := []string{title, ""}
+		for _, part := range body {
+			lines = append(lines, wrapText(part, dotLineWidth)...)
+		}
+		border, fill := nodeColors(n.Color)
+		fmt.Fprintf(bw, "  \"%s\" [label=\"%s\\l\", color=\"%s\", fillcolor=\"%s\"];\n",
+			dotEscaper.Replace(n.ID), dotEscaper.Replace(strings.Join(lines, "\n")), border, fill)
+	}
+
+	for _, e := range c.Edges {
+		attrs := []string{}
+		if e.Condition != "" {
+			attrs = append(attrs, fmt.Sprintf("label=\"%s\"", dotEscaper.Replace(truncate(e.Condition, edgeLabelLength))))
+		}
+		if loops[e.ID] {
+			attrs = append(attrs, "style=dashed", "constraint=false", fmt.Sprintf("color=\"%s\"", loopColor))
+		}
+		fmt.Fprintf(bw, "  \"%s\" -> \"%s\"", dotEscaper.Replace(e.FromNode), dotEscaper.Replace(e.ToNode))
+		if len(attrs) > 0 {
+			fmt.Fprintf(bw, " [%s]", strings.Join(attrs, ", "))
+		}
+		bw.WriteString(";\n")
+	}
+
+	bw.WriteString("}\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing DOT for dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
+
+// Writes the laid out dialog (see Layout) as a standalone SVG image. Texts
+// that do not fit a node are cut; the full text is in the node tooltip.
+// Loop edges are dashed and grey.
+func (d *Dialog) WriteSvg(w io.Writer, l *Layout) error {
+	c, loops := l.Canvas, l.Loops
+	bw := bufio.NewWriter(w)
+
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	nodes := make(map[string]*dcanvas.Node, len(c.Nodes))
+	for i, n := range c.Nodes {
+		nodes[n.ID] = n
+		if i == 0 || n.X < minX {
+			minX = n.X
+		}
+		if i == 0 || n.Y < minY {
+			minY = n.Y
+		}
+		maxX = max(maxX, n.X+n.Width)
+		maxY = max(maxY, n.Y+n.Height)
+	}
+	offsetX, offsetY := svgMargin-minX, svgMargin-minY
+	width, height := maxX-minX+2*svgMargin, maxY-minY+2*svgMargin
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`+"\n",
+		width, height, width, height, svgFontSize)
+	fmt.Fprintf(bw, "<title>%s</title>\n", html.EscapeString(d.Id.String()))
+	bw.WriteString(`<defs>` +
+		`<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#555"/></marker>` +
+		`<marker id="loop-arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="` + loopColor + `"/></marker>` +
+		"</defs>\n")
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	// Edges go below the nodes.
+	for _, e := range c.Edges {
+		from, to := nodes[e.FromNode], nodes[e.ToNode]
+		if from == nil || to == nil {
+			continue
+		}
+		x1, y1 := float64(from.X+from.Width/2+offsetX), float64(from.Y+from.Height+offsetY)
+		x2, y2 := float64(to.X+to.Width/2+offsetX), float64(to.Y+offsetY)
+		bend := math.Max(60, math.Abs(y2-y1)/2)
+
+		stroke, marker, dash := "#555", "arrow", ""
+		cx1, cy1, cx2, cy2 := x1, y1+bend, x2, y2-bend
+		if loops[e.ID] {
+			// Back to an upper node: go around on the right side.
+			stroke, marker, dash = loopColor, "loop-arrow", ` stroke-dasharray="8,5"`
+			side := float64(from.Width)/2 + 80
+			cx1, cx2 = x1+side, x2+side
+		}
+
+		fmt.Fprintf(bw, `<g class="edge"><path d="M%.0f,%.0f C%.0f,%.0f %.0f,%.0f %.0f,%.0f" fill="none" stroke="%s" stroke-width="2"%s marker-end="url(#%s)"/>`,
+			x1, y1, cx1, cy1, cx2, cy2, x2, y2, stroke, dash, marker)
+		if e.Condition != "" {
+			// Midpoint of the bezier curve.
+			mx, my := (x1+3*cx1+3*cx2+x2)/8, (y1+3*cy1+3*cy2+y2)/8
+			fmt.Fprintf(bw, `<text x="%.0f" y="%.0f" font-size="11" font-family="monospace" fill="#333" text-anchor="middle" paint-order="stroke" stroke="#fff" stroke-width="4"><title>%s</title>%s</text>`,
+				mx, my, html.EscapeString(e.Condition), html.EscapeString(truncate(e.Condition, edgeLabelLength)))
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	for _, n := range c.Nodes {
+		x, y := n.X+offsetX, n.Y+offsetY
+		maxChars := int(float64(n.Width-2*svgPadding) / svgCharWidth)
+		border, fill := nodeColors(n.Color)
+		title, body := nodeContent(n)
+
+		fmt.Fprintf(bw, `<g class="node" id="%s"><title>%s</title>`, html.EscapeString(n.ID), html.EscapeString(strings.Join(append([]string{title}, body...), "\n")))
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="%s" stroke="%s" stroke-width="2"/>`, x, y, n.Width, n.Height, fill, border)
+
+		lineY := y + svgPadding + svgFontSize
+		fmt.Fprintf(bw, `<text x="%d" y="%d" font-weight="bold">%s</text>`, x+svgPadding, lineY, html.EscapeString(truncate(title, maxChars)))
+
+		maxLines := (n.Height-2*svgPadding)/svgLineHeight - 1
+		var lines []string
+		for _, part := range body {
+			lines = append(lines, wrapText(part, maxChars)...)
+		}
+		if len(lines) > maxLines {
+			lines = append(lines[:max(maxLines-1, 0)], "…")[:max(maxLines, 0)]
+		}
+		for _, line := range lines {
+			lineY += svgLineHeight
+			fmt.Fprintf(bw, `<text x="%d" y="%d" xml:space="preserve">%s</text>`, x+svgPadding, lineY, html.EscapeString(line))
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing SVG for dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
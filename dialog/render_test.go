@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDotAndSvg(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(d *Dialog, sb *strings.Builder) error
+		want  []string
+	}{
+		{
+			name:  "dot",
+			write: func(d *Dialog, sb *strings.Builder) error { return d.WriteDot(sb, FormatOptions{}) },
+			want: []string{
+				`digraph "ABC[0]"`,
+				`style=dashed, constraint=false`,
+				`"state-ABC[0]" -> "transition-ABC[0]" [label="PartyHasItem(\"KEY\")"]`,
+				`Hello & welcome`,
+			},
+		},
+		{
+			name:  "svg",
+			write: func(d *Dialog, sb *strings.Builder) error { return d.WriteSvg(sb, d.Layout(FormatOptions{})) },
+			want: []string{
+				`<svg xmlns="http://www.w3.org/2000/svg"`,
+				`stroke-dasharray="8,5"`,
+				`<title>PartyHasItem(&#34;KEY&#34;)</title>`,
+				`Hello &amp; welcome`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			d := testDialog()
+			answer := d.RootState.Children[0].Transition
+			answer.HasTrigger, answer.Trigger = true, "PartyHasItem(\"KEY\")\n"
+
+			if err := tt.write(d, &sb); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sb.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, sb.String())
+				}
+			}
+		})
+	}
+}
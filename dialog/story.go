@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Name of the Twee passage that ends the dialog.
+const tweeEndPassage = "end"
+
+// Returns the name of the state as an Ink knot, Yarn node or Twee passage:
+// "<dlg>_<index>" in lower case.
+func storyName(origin NodeOrigin) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, origin.DlgName)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "dlg_" + name
+	}
+	return fmt.Sprintf("%s_%d", name, origin.Index)
+}
+
+// Returns the non-empty lines of a trigger or action.
+func codeLines(code string) []string {
+	var lines []string
+	for _, line := range strings.Split(code, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// A transition as a choice of the player.
+type storyChoice struct {
+	Text    string // single line
+	TextRef string
+	Journal string
+	Trigger []string
+	Action  []string
+	Extern  string // DLG name of the next state in another file
+	Target  string // story name of the next state, empty for the end
+}
+
+// Returns the states of the dialog, each once, in the order of the tree.
+// Loops refer to the states, so they are not included.
+func (d *Dialog) storyStates() []*Node {
+	var states []*Node
+	for _, node := range d.All() {
+		if node.Type == StateNodeType || node.Type == ErrorNodeType {
+			states = append(states, node)
+		}
+	}
+	return states
+}
+
+func storyChoices(state *Node) []storyChoice {
+	var choices []storyChoice
+	for _, node := range state.Children {
+		if node == nil || node.Type != TransitionNodeType {
+			continue
+		}
+		t := node.Transition
+		c := storyChoice{
+			Trigger: codeLines(t.Trigger),
+			Action:  codeLines(t.Action),
+		}
+
+		switch {
+		case t.HasText:
+			c.Text = strings.Join(strings.Fields(t.Text), " ")
+			c.TextRef = fmt.Sprintf("%d", t.TextRef)
+		case t.IsDialogEnd:
+			c.Text = "(End dialog)"
+		default:
+			c.Text = "(Continue)"
+		}
+		if t.HasJournalText {
+			c.Journal = strings.Join(strings.Fields(t.JournalText), " ")
+		}
+
+		if !t.IsDialogEnd && len(node.Children) > 0 && node.Children[0] != nil {
+			next := node.Children[0]
+			c.Target = storyName(next.Origin)
+			if !strings.EqualFold(next.Origin.DlgName, node.Origin.DlgName) {
+				c.Extern = strings.ToUpper(next.Origin.DlgName)
+			}
+		}
+		choices = append(choices, c)
+	}
+	return choices
+}
+
+// Writes comments with tags, one per line of the values.
+func writeTaggedComments(w *bufio.Writer, indent, tag string, lines ...string) {
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s// %s: %s\n", indent, tag, line)
+	}
+}
+
+func writeChoiceComments(w *bufio.Writer, indent string, c storyChoice) {
+	writeTaggedComments(w, indent, "trigger", c.Trigger...)
+	if c.Journal != "" {
+		writeTaggedComments(w, indent, "journal", c.Journal)
+	}
+	writeTaggedComments(w, indent, "action", c.Action...)
+	if c.Extern != "" {
+		writeTaggedComments(w, indent, "extern", c.Extern)
+	}
+}
+
+var inkEscaper = strings.NewReplacer(
+	`\`, `\\`, `{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`, `|`, `\|`, `#`, `\#`,
+	`//`, `\/\/`, `/*`, `\/*`, `->`, `-\>`, `<>`, `<\>`,
+)
+
+// Escapes a line of text for Ink, including the marks of choices, gathers
+// and logic at the start of the line.
+func escapeInk(s string) string {
+	s = inkEscaper.Replace(s)
+	if s != "" && strings.ContainsRune("*+-=~", rune(s[0])) {
+		s = `\` + s
+	}
+	return s
+}
+
+// Writes the dialog as an Ink story. States are knots and transitions are
+// choices diverting to the knots of the next states; triggers, actions and
+// journal entries are tagged comments.
+func (d *Dialog) WriteInk(w io.Writer) error {
+	if d.RootState == nil {
+		return fmt.Errorf("dialog %s has no root state", d.Id)
+	}
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "// Dialog %s\n-> %s\n", d.Id, storyName(d.RootState.Origin))
+
+	for _, state := range d.storyStates() {
+		fmt.Fprintf(bw, "\n=== %s ===\n", storyName(state.Origin))
+		if state.Type == ErrorNodeType {
+			writeTaggedComments(bw, "", "error", "unable to load state "+state.Origin.String())
+			bw.WriteString("-> END\n")
+			continue
+		}
+
+		writeTaggedComments(bw, "", "trigger", codeLines(state.State.Trigger)...)
+		for i, line := range strings.Split(state.State.Text, "\n") {
+			if i == 0 {
+				fmt.Fprintf(bw, "%s: %s #strref:%d\n", escapeInk(d.Speaker(state.Origin.DlgName)), inkEscaper.Replace(line), state.State.TextRef)
+			} else if line != "" {
+				fmt.Fprintf(bw, "%s\n", escapeInk(line))
+			}
+		}
+
+		choices := storyChoices(state)
+		for _, c := range choices {
+			fmt.Fprintf(bw, "+ [%s]", inkEscaper.Replace(c.Text))
+			if c.TextRef != "" {
+				fmt.Fprintf(bw, " #strref:%s", c.TextRef)
+			}
+			bw.WriteString("\n")
+			writeChoiceComments(bw, "    ", c)
+			if c.Target != "" {
+				fmt.Fprintf(bw, "    -> %s\n", c.Target)
+			} else {
+				bw.WriteString("    -> END\n")
+			}
+		}
+		if len(choices) == 0 {
+			bw.WriteString("-> END\n")
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing Ink for dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
+
+var yarnEscaper = strings.NewReplacer(
+	`\`, `\\`, `{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`, `#`, `\#`, `<`, `\<`, `>`, `\>`, `//`, `\/\/`,
+)
+
+// Writes the dialog as a Yarn Spinner script. States are nodes and
+// transitions are options jumping to the nodes of the next states;
+// triggers, actions and journal entries are tagged comments.
+func (d *Dialog) WriteYarn(w io.Writer) error {
+	if d.RootState == nil {
+		return fmt.Errorf("dialog %s has no root state", d.Id)
+	}
+	bw := bufio.NewWriter(w)
+
+	for i, state := range d.storyStates() {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "title: %s\n", storyName(state.Origin))
+		if !strings.EqualFold(state.Origin.DlgName, d.Id.DlgName) {
+			fmt.Fprintf(bw, "tags: extern\n")
+		}
+		bw.WriteString("---\n")
+
+		if state.Type == ErrorNodeType {
+			writeTaggedComments(bw, "", "error", "unable to load state "+state.Origin.String())
+			bw.WriteString("<<stop>>\n===\n")
+			continue
+		}
+
+		writeTaggedComments(bw, "", "trigger", codeLines(state.State.Trigger)...)
+		for i, line := range strings.Split(state.State.Text, "\n") {
+			if i == 0 {
+				fmt.Fprintf(bw, "%s: %s #strref:%d\n", yarnEscaper.Replace(d.Speaker(state.Origin.DlgName)), yarnEscaper.Replace(line), state.State.TextRef)
+			} else if line != "" {
+				fmt.Fprintf(bw, "%s\n", yarnEscaper.Replace(line))
+			}
+		}
+
+		choices := storyChoices(state)
+		for _, c := range choices {
+			fmt.Fprintf(bw, "-> %s", yarnEscaper.Replace(c.Text))
+			if c.TextRef != "" {
+				fmt.Fprintf(bw, " #strref:%s", c.TextRef)
+			}
+			bw.WriteString("\n")
+			writeChoiceComments(bw, "    ", c)
+			if c.Target != "" {
+				fmt.Fprintf(bw, "    <<jump %s>>\n", c.Target)
+			} else {
+				bw.WriteString("    <<stop>>\n")
+			}
+		}
+		if len(choices) == 0 {
+			bw.WriteString("<<stop>>\n")
+		}
+		bw.WriteString("===\n")
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing Yarn for dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
+
+// Returns a stable IFID of the dialog in the UUID v4 format Twine expects.
+func (d *Dialog) ifid() string {
+	sum := sha1.Sum([]byte(d.Id.String()))
+	sum[6] = sum[6]&0x0f | 0x40
+	sum[8] = sum[8]&0x3f | 0x80
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16]))
+}
+
+// Escapes the text of a Harlowe passage or link.
+func escapeTwee(s string) string {
+	s = html.EscapeString(s)
+	return strings.NewReplacer("->", "-&gt;", "[", "&#91;", "]", "&#93;", "|", "&#124;", "''", "&#39;&#39;").Replace(s)
+}
+
+func writeTweeComments(w *bufio.Writer, tag string, lines ...string) {
+	for _, line := range lines {
+		fmt.Fprintf(w, "<!-- %s: %s -->\n", tag, strings.ReplaceAll(line, "--", "- -"))
+	}
+}
+
+// Writes the dialog as a Twine 3 story in Twee for the Harlowe format.
+// States are passages and transitions are links to the passages of the
+// next states; triggers, actions and journal entries are tagged HTML
+// comments.
+func (d *Dialog) WriteTwee(w io.Writer) error {
+	if d.RootState == nil {
+		return fmt.Errorf("dialog %s has no root state", d.Id)
+	}
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, ":: StoryTitle\n%s\n\n", d.Id)
+	fmt.Fprintf(bw, ":: StoryData\n{\n  \"ifid\": \"%s\",\n  \"format\": \"Harlowe\",\n  \"format-version\": \"3.3.9\",\n  \"start\": \"%s\"\n}\n",
+		d.ifid(), storyName(d.RootState.Origin))
+
+	for _, state := range d.storyStates() {
+		tags := "state"
+		if !strings.EqualFold(state.Origin.DlgName, d.Id.DlgName) {
+			tags += " extern"
+		}
+		fmt.Fprintf(bw, "\n:: %s [%s]\n", storyName(state.Origin), tags)
+
+		if state.Type == ErrorNodeType {
+			writeTweeComments(bw, "error", "unable to load state "+state.Origin.String())
+			fmt.Fprintf(bw, "[[(End dialog)->%s]]\n", tweeEndPassage)
+			continue
+		}
+
+		writeTweeComments(bw, "trigger", codeLines(state.State.Trigger)...)
+		fmt.Fprintf(bw, "''%s:'' %s\n", escapeTwee(d.Speaker(state.Origin.DlgName)), escapeTwee(state.State.Text))
+		writeTweeComments(bw, "strref", fmt.Sprintf("%d", state.State.TextRef))
+		bw.WriteString("\n")
+
+		choices := storyChoices(state)
+		for _, c := range choices {
+			writeTweeComments(bw, "trigger", c.Trigger...)
+			if c.Journal != "" {
+				writeTweeComments(bw, "journal", c.Journal)
+			}
+			writeTweeComments(bw, "action", c.Action...)
+			if c.Extern != "" {
+				writeTweeComments(bw, "extern", c.Extern)
+			}
+			if c.TextRef != "" {
+				writeTweeComments(bw, "strref", c.TextRef)
+			}
+			target := c.Target
+			if target == "" {
+				target = tweeEndPassage
+			}
+			fmt.Fprintf(bw, "[[%s->%s]]\n", escapeTwee(c.Text), target)
+		}
+		if len(choices) == 0 {
+			fmt.Fprintf(bw, "[[(End dialog)->%s]]\n", tweeEndPassage)
+		}
+	}
+
+	fmt.Fprintf(bw, "\n:: %s [end]\n''The end.''\n", tweeEndPassage)
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing Twee for dialog %s: %w", d.Id, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteStory(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(d *Dialog, w io.Writer) error
+		want  []string
+	}{
+		{
+			name:  "ink",
+			write: (*Dialog).WriteInk,
+			want: []string{
+				"-> abc_0\n",
+				"=== abc_0 ===\n// trigger: Global(\"X\",\"GLOBAL\",1)\nAbbot <Brother>: Hello & welcome #strref:10\n",
+				"+ [Who is there?] #strref:11\n    // trigger: True()\n    // extern: XYZ\n    -> xyz_2\n",
+				"+ [(End dialog)]\n    // action: EscapeArea()\n    -> END\n",
+				"=== xyz_2 ===\nXYZ: Me. #strref:12\n+ [(Continue)]\n    // extern: ABC\n    -> abc_0\n",
+			},
+		},
+		{
+			name:  "yarn",
+			write: (*Dialog).WriteYarn,
+			want: []string{
+				"title: abc_0\n---\n// trigger: Global(\"X\",\"GLOBAL\",1)\nAbbot \\<Brother\\>: Hello & welcome #strref:10\n",
+				"-> Who is there? #strref:11\n    // trigger: True()\n    // extern: XYZ\n    <<jump xyz_2>>\n",
+				"-> (End dialog)\n    // action: EscapeArea()\n    <<stop>>\n===\n",
+				"title: xyz_2\ntags: extern\n---\n",
+			},
+		},
+		{
+			name:  "twee",
+			write: (*Dialog).WriteTwee,
+			want: []string{
+				":: StoryTitle\nABC[0]\n",
+				`"start": "abc_0"`,
+				":: abc_0 [state]\n<!-- trigger: Global(\"X\",\"GLOBAL\",1) -->\n''Abbot &lt;Brother&gt;:'' Hello &amp; welcome\n",
+				"<!-- trigger: True() -->\n<!-- extern: XYZ -->\n<!-- strref: 11 -->\n[[Who is there?->xyz_2]]\n",
+				"<!-- action: EscapeArea() -->\n[[(End dialog)->end]]\n",
+				":: xyz_2 [state extern]\n",
+				":: end [end]\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := testDialog()
+			answer := d.RootState.Children[0].Transition
+			answer.HasTrigger, answer.Trigger = true, "True()\n"
+
+			var sb strings.Builder
+			if err := tt.write(d, &sb); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sb.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, sb.String())
+				}
+			}
+		})
+	}
+}
+
+func TestEscapeInk(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Plain text.", "Plain text."},
+		{"- Not a gather", `\- Not a gather`},
+		{"{CHARNAME} -> away", `\{CHARNAME\} -\> away`},
+		{"A // B #tag", `A \/\/ B \#tag`},
+	}
+	for _, tt := range tests {
+		if got := escapeInk(tt.in); got != tt.want {
+			t.Errorf("escapeInk(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/bcs"
+)
+
+type VarAccessKind string
+
+const (
+	VarCheck VarAccessKind = "check" // in a trigger
+	VarSet   VarAccessKind = "set"   // in an action
+)
+
+// Operations of the triggers and actions on their variables, one per
+// variable of the function. Which arguments are the variables, their areas
+// and the value is read from the signatures of TRIGGER.IDS and ACTION.IDS.
+var (
+	triggerVarOps = map[string][]string{
+		"global":                    {"="},
+		"globalgt":                  {">"},
+		"globallt":                  {"<"},
+		"globalband":                {"&"},
+		"bitcheck":                  {"bit"},
+		"globalsequal":              {"=", "="},
+		"globalsgt":                 {">", "<"},
+		"globalslt":                 {"<", ">"},
+		"globaltimerexpired":        {"timer expired"},
+		"globaltimernotexpired":     {"timer not expired"},
+		"globaltimerstarted":        {"timer started"},
+		"globaltimerexact":          {"timer exactly expired"},
+		"realglobaltimerexpired":    {"real timer expired"},
+		"realglobaltimernotexpired": {"real timer not expired"},
+	}
+	actionVarOps = map[string][]string{
+		"setglobal":           {"="},
+		"setglobalrandom":     {"random"},
+		"incrementglobal":     {"+="},
+		"incrementglobalonce": {"+=", "once flag of"},
+		"bitglobal":           {"bit"},
+		"globalbor":           {"|="},
+		"globalband":          {"&="},
+		"globalmax":           {"max"},
+		"globalmin":           {"min"},
+		"setglobaltimer":      {"timer"},
+		"setglobaltimeronce":  {"timer once"},
+		"realsetglobaltimer":  {"real timer"},
+	}
+)
+
+// VarAccess is a check or a change of a variable in a dialog.
+type VarAccess struct {
+	Kind     VarAccessKind
+	Function string // as written, e.g. SetGlobal
+	Negated  bool
+	Op       string // e.g. "=", "+=" or "timer expired", empty if unknown
+	Value    string // as written, or the other variables of the function
+	Node     string // state or transition, like state-ABC[0]
+	Dialog   DialogID
+	Url      string // of the node on the dialog site, if there is a base URL
+}
+
+func (a VarAccess) Condition() string {
+	s := strings.TrimSpace(a.Op + " " + a.Value)
+	if a.Negated {
+		s = "not " + s
+	}
+	return s
+}
+
+// Var is a variable with the places it is set and checked at.
+type Var struct {
+	Area     string // GLOBAL, LOCALS or an area like AR0602
+	Name     string
+	Accesses []VarAccess
+}
+
+func (v *Var) String() string {
+	return fmt.Sprintf("%s %q", v.Area, v.Name)
+}
+
+// Returns the accesses of the kind.
+func (v *Var) Filter(kind VarAccessKind) []VarAccess {
+	var accesses []VarAccess
+	for _, a := range v.Accesses {
+		if a.Kind == kind {
+			accesses = append(accesses, a)
+		}
+	}
+	return accesses
+}
+
+// VarIndex is the index of variables used in triggers and actions of
+// dialogs, sorted by area and name.
+type VarIndex struct {
+	Vars []*Var
+}
+
+// VarIndexOptions are the options of BuildVarIndex.
+type VarIndexOptions struct {
+	// Base URL of the dialog site, to link accesses to their nodes.
+	BaseUrl string
+	// Functions of TRIGGER.IDS and ACTION.IDS. Calls which do not resolve
+	// against them are skipped.
+	Triggers, Actions *bcs.Functions
+}
+
+// Builds the index of variables of the dialogs. A state or transition
+// shared by several dialogs is indexed once, with the first dialog.
+// Returns the errors of triggers and actions which cannot be parsed; the
+// other scripts are indexed anyway.
+func BuildVarIndex(collection *DialogCollection, opts VarIndexOptions) (*VarIndex, []error) {
+	vars := make(map[string]*Var)
+	seen := make(map[string]bool)
+	var errs []error
+
+	add := func(node *Node, d *Dialog, kind VarAccessKind, code string) {
+		script, err := bcs.Parse(code)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s of %s: %w", kind.script(), node, err))
+			return
+		}
+		funcs, ops := opts.Triggers, triggerVarOps
+		if kind == VarSet {
+			funcs, ops = opts.Actions, actionVarOps
+		}
+		script.Resolve(funcs)
+
+		for _, c := range script.All() {
+			refs, values := varArgs(c, ops)
+			for i, ref := range refs {
+				key := ref.area + ":" + strings.ToUpper(ref.name)
+				v, ok := vars[key]
+				if !ok {
+					v = &Var{Area: ref.area, Name: ref.name}
+					vars[key] = v
+				}
+
+				access := VarAccess{Kind: kind, Function: c.Name, Negated: c.Negated, Node: node.String(), Dialog: d.Id}
+				if fnOps := ops[strings.ToLower(c.Name)]; i < len(fnOps) {
+					access.Op = fnOps[i]
+				}
+				if i == 0 && len(values) > 0 {
+					access.Value = strings.Join(values, ", ")
+				} else {
+					var others []string
+					for j, other := range refs {
+						if j != i {
+							others = append(others, other.String())
+						}
+					}
+					access.Value = strings.Join(others, ", ")
+				}
+				if opts.BaseUrl != "" {
+					access.Url = node.ToUrl(opts.BaseUrl)
+				}
+				v.Accesses = append(v.Accesses, access)
+			}
+		}
+	}
+
+	for _, d := range collection.Dialogs {
+		for _, node := range d.All() {
+			if seen[node.String()] {
+				continue
+			}
+			switch node.Type {
+			case StateNodeType:
+				seen[node.String()] = true
+				if node.State.HasTrigger {
+					add(node, d, VarCheck, node.State.Trigger)
+				}
+			case TransitionNodeType:
+				seen[node.String()] = true
+				if node.Transition.HasTrigger {
+					add(node, d, VarCheck, node.Transition.Trigger)
+				}
+				if node.Transition.HasAction {
+					add(node, d, VarSet, node.Transition.Action)
+				}
+			}
+		}
+	}
+
+	index := &VarIndex{}
+	for _, v := range vars {
+		index.Vars = append(index.Vars, v)
+	}
+	slices.SortFunc(index.Vars, func(a, b *Var) int {
+		return cmp.Or(cmp.Compare(a.Area, b.Area), cmp.Compare(strings.ToUpper(a.Name), strings.ToUpper(b.Name)))
+	})
+	return index, errs
+}
+
+func (k VarAccessKind) script() string {
+	if k == VarSet {
+		return "action"
+	}
+	return "trigger"
+}
+
+type varRef struct {
+	area, name string
+}
+
+func (r varRef) String() string {
+	return fmt.Sprintf("%s %q", r.area, r.name)
+}
+
+// Returns the variables of a resolved call and its other arguments as
+// written. A variable is a string parameter followed by its area, like
+// S:Name*,S:Area* of SetGlobal. Functions of ops without area parameters,
+// like GlobalsEqual(S:Name1*,S:Name2*) of some games, take names with the
+// area in front, e.g. "GLOBALCount".
+func varArgs(c *bcs.Call, ops map[string][]string) ([]varRef, []string) {
+	if c.Signature == nil {
+		return nil, nil
+	}
+	isArea := func(p bcs.Param) bool {
+		return p.Type == bcs.StringParam && strings.Contains(strings.ToLower(p.Name), "area")
+	}
+	_, known := ops[strings.ToLower(c.Name)]
+	scoped := known && !slices.ContainsFunc(c.Signature.Params, isArea)
+
+	var refs []varRef
+	var values []string
+	for i := 0; i < len(c.Args); i++ {
+		param, arg := c.Signature.Params[i], c.Args[i]
+		switch {
+		case param.Type == bcs.StringParam && arg.Kind == bcs.StringArg && i+1 < len(c.Args) &&
+			isArea(c.Signature.Params[i+1]) && c.Args[i+1].Kind == bcs.StringArg:
+			refs = append(refs, varRef{strings.ToUpper(c.Args[i+1].Text), arg.Text})
+			i++
+		case scoped && param.Type == bcs.StringParam && arg.Kind == bcs.StringArg && len(arg.Text) > 6:
+			refs = append(refs, varRef{strings.ToUpper(arg.Text[:6]), arg.Text[6:]})
+		default:
+			values = append(values, arg.String())
+		}
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	return refs, values
+}
+
+type jsonVarAccess struct {
+	Function  string `json:"function"`
+	Condition string `json:"condition"`
+	Node      string `json:"node"`
+	Dialog    string `json:"dialog"`
+	Url       string `json:"url,omitempty"`
+}
+
+type jsonVar struct {
+	Area   string          `json:"area"`
+	Name   string          `json:"name"`
+	Set    []jsonVarAccess `json:"set"`
+	Checks []jsonVarAccess `json:"checks"`
+}
+
+func toJsonAccesses(accesses []VarAccess) []jsonVarAccess {
+	result := make([]jsonVarAccess, 0, len(accesses))
+	for _, a := range accesses {
+		result = append(result, jsonVarAccess{a.Function, a.Condition(), a.Node, a.Dialog.String(), a.Url})
+	}
+	return result
+}
+
+// Writes the index as JSON: the variables with the places they are set and
+// checked at.
+func (i *VarIndex) WriteJson(w io.Writer) error {
+	vars := make([]jsonVar, 0, len(i.Vars))
+	for _, v := range i.Vars {
+		vars = append(vars, jsonVar{v.Area, v.Name, toJsonAccesses(v.Filter(VarSet)), toJsonAccesses(v.Filter(VarCheck))})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(struct {
+		Vars []jsonVar `json:"vars"`
+	}{vars}); err != nil {
+		return fmt.Errorf("error writing JSON of variables: %w", err)
+	}
+	return nil
+}
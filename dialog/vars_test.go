@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package dialog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/bcs"
+)
+
+const (
+	testVarTriggers = `IDS V1.0
+0x400F Global(S:Name*,S:Area*,I:Value*)
+0x4034 GlobalGT(S:Name*,S:Area*,I:Value*)
+0x4085 GlobalsEqual(S:Name1*,S:Name2*)
+0x40A9 RealGlobalTimerExpired(S:Name*,S:Area*)
+`
+	testVarActions = `IDS V1.0
+1 ActionOverride(O:Actor*,A:Action*)
+30 SetGlobal(S:Name*,S:Area*,I:Value*)
+109 IncrementGlobal(S:Name*,S:Area*,I:Value*)
+106 EscapeArea()
+268 IncrementGlobalOnce(S:Name*,S:Area*,S:Global*,S:Area*,I:Value*)
+320 SetGlobalRandom(S:Name*,S:Area*,I:Min*,I:Max*)
+`
+)
+
+// Returns the options with the functions of testVarTriggers and testVarActions.
+func testVarOptions(t *testing.T) VarIndexOptions {
+	t.Helper()
+	triggers, err := bcs.ParseFunctions(strings.NewReader(testVarTriggers))
+	if err != nil {
+		t.Fatalf("ParseFunctions() error = %v", err)
+	}
+	actions, err := bcs.ParseFunctions(strings.NewReader(testVarActions))
+	if err != nil {
+		t.Fatalf("ParseFunctions() error = %v", err)
+	}
+	return VarIndexOptions{Triggers: triggers, Actions: actions}
+}
+
+func TestBuildVarIndex(t *testing.T) {
+	d := testDialog()
+	toXyz, end := d.RootState.Children[0], d.RootState.Children[1]
+	toXyz.Transition.HasTrigger, toXyz.Transition.Trigger = true, "!GlobalGT(\"count\",\"locals\",3)\n"
+	end.Transition.Action = "SetGlobal(\"X\",\"GLOBAL\",2)\nActionOverride(\"Imoen\",IncrementGlobal(\"Count\",\"LOCALS\",1))\n"
+
+	// The second dialog shares the nodes, which are indexed once.
+	opts := testVarOptions(t)
+	opts.BaseUrl = "https://example.com"
+	index, errs := BuildVarIndex(&DialogCollection{Dialogs: []*Dialog{d, testDialog()}}, opts)
+	if len(errs) > 0 {
+		t.Fatalf("BuildVarIndex() errors = %v", errs)
+	}
+
+	if len(index.Vars) != 2 {
+		t.Fatalf("got %d variables, want 2: %+v", len(index.Vars), index.Vars)
+	}
+	x, count := index.Vars[0], index.Vars[1]
+	if x.String() != `GLOBAL "X"` || count.String() != `LOCALS "count"` {
+		t.Errorf("variables = %s, %s", x, count)
+	}
+
+	checks, sets := x.Filter(VarCheck), x.Filter(VarSet)
+	if len(checks) != 1 || checks[0].Node != "state-ABC[0]" || checks[0].Condition() != "= 1" {
+		t.Errorf("checks of X = %+v", checks)
+	}
+	if len(sets) != 1 || sets[0].Node != "transition-ABC[1]" || sets[0].Condition() != "= 2" ||
+		sets[0].Url != "https://example.com/dialog/abc-0#transition-abc-1-" {
+		t.Errorf("sets of X = %+v", sets)
+	}
+	if checks := count.Filter(VarCheck); len(checks) != 1 || checks[0].Condition() != "not > 3" {
+		t.Errorf("checks of count = %+v", checks)
+	}
+	if sets := count.Filter(VarSet); len(sets) != 1 || sets[0].Function != "IncrementGlobal" || sets[0].Condition() != "+= 1" {
+		t.Errorf("sets of count = %+v", sets)
+	}
+
+	var buf bytes.Buffer
+	if err := index.WriteJson(&buf); err != nil {
+		t.Fatalf("WriteJson() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"condition": "= 2"`) {
+		t.Errorf("WriteJson() = %s", buf.String())
+	}
+}
+
+func TestBuildVarIndexFunctions(t *testing.T) {
+	opts := testVarOptions(t)
+
+	tests := []struct {
+		trigger, action string
+		want            []string // area, name, kind, function and condition of each access
+	}{
+		{
+			action: `IncrementGlobalOnce("Gold","GLOBAL","GoldOnce","LOCALS",50)`,
+			want: []string{
+				`GLOBAL "Gold": set IncrementGlobalOnce += 50`,
+				`LOCALS "GoldOnce": set IncrementGlobalOnce once flag of GLOBAL "Gold"`,
+			},
+		},
+		{
+			trigger: `GlobalsEqual("GLOBALA","GLOBALB")`,
+			want: []string{
+				`GLOBAL "A": check GlobalsEqual = GLOBAL "B"`,
+				`GLOBAL "B": check GlobalsEqual = GLOBAL "A"`,
+			},
+		},
+		{
+			trigger: `!RealGlobalTimerExpired("Wait","MYAREA")`,
+			action:  `SetGlobalRandom("Roll","LOCALS",1,6)`,
+			want: []string{
+				`LOCALS "Roll": set SetGlobalRandom random 1, 6`,
+				`MYAREA "Wait": check RealGlobalTimerExpired not real timer expired`,
+			},
+		},
+		// Unknown functions and wrong arguments are skipped.
+		{action: `SetGlobl("X","GLOBAL",1)` + "\n" + `SetGlobal("X","GLOBAL")`},
+	}
+	for _, tt := range tests {
+		d := testDialog()
+		end := d.RootState.Children[1]
+		d.RootState.State.Trigger = tt.trigger
+		d.RootState.State.HasTrigger = tt.trigger != ""
+		end.Transition.Action = tt.action
+
+		index, errs := BuildVarIndex(&DialogCollection{Dialogs: []*Dialog{d}}, opts)
+		if len(errs) > 0 {
+			t.Fatalf("BuildVarIndex(%q, %q) errors = %v", tt.trigger, tt.action, errs)
+		}
+		var got []string
+		for _, v := range index.Vars {
+			for _, a := range v.Accesses {
+				got = append(got, fmt.Sprintf("%s: %s %s %s", v, a.Kind, a.Function, a.Condition()))
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BuildVarIndex(%q, %q) = %q, want %q", tt.trigger, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestBuildVarIndexErrors(t *testing.T) {
+	d := testDialog()
+	d.RootState.State.Trigger = `Global("X","GLOBAL"`
+	d.RootState.Children[1].Transition.Action = `SetGlobal("Y","GLOBAL",1)`
+
+	index, errs := BuildVarIndex(&DialogCollection{Dialogs: []*Dialog{d}}, testVarOptions(t))
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "trigger of state-ABC[0]: ") {
+		t.Errorf("BuildVarIndex() errors = %v", errs)
+	}
+	if len(index.Vars) != 1 || index.Vars[0].String() != `GLOBAL "Y"` {
+		t.Errorf("BuildVarIndex() vars = %v", index.Vars)
+	}
+}
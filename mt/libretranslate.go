@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package mt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LibreTranslate is a provider speaking the LibreTranslate HTTP API
+// (POST /translate), e.g. a self-hosted instance.
+type LibreTranslate struct {
+	BaseUrl string
+	ApiKey  string
+	Client  *http.Client
+}
+
+func NewLibreTranslate(baseUrl, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		BaseUrl: strings.TrimSuffix(baseUrl, "/"),
+		ApiKey:  apiKey,
+		Client:  &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (l *LibreTranslate) Name() string {
+	return "LibreTranslate"
+}
+
+type libreTranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	ApiKey string   `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText json.RawMessage `json:"translatedText"`
+	Error          string          `json:"error"`
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, texts []string, source, target string) ([]string, error) {
+	body, err := json.Marshal(libreTranslateRequest{
+		Q:      texts,
+		Source: source,
+		Target: target,
+		Format: "text",
+		ApiKey: l.ApiKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.BaseUrl+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %w", err)
+	}
+
+	var result libreTranslateResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unexpected response (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, result.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	// translatedText is a list for a list of texts, but a single string
+	// for one text in some versions.
+	var translated []string
+	if err := json.Unmarshal(result.TranslatedText, &translated); err != nil {
+		var single string
+		if err := json.Unmarshal(result.TranslatedText, &single); err != nil {
+			return nil, fmt.Errorf("unexpected translatedText in response: %s", result.TranslatedText)
+		}
+		translated = []string{single}
+	}
+	return translated, nil
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package mt pre-fills translations with machine translation providers.
+package mt
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Provider translates texts from the source to the target language.
+// The result has the same length and order as the input.
+type Provider interface {
+	Name() string
+	Translate(ctx context.Context, texts []string, source, target string) ([]string, error)
+}
+
+// Engine tokens (<CHARNAME>), EE color tags (^0xFFRRGGBB ... ^-), markup
+// in square or curly brackets ([b], {i}).
+var tokenPattern = regexp.MustCompile(`<[^<>\s]*>|\^0x[0-9A-Fa-f]{8}|\^-|\[/?[A-Za-z]+\]|\{/?[A-Za-z]+\}`)
+
+// Placeholders sent instead of tokens. Providers may add spaces inside.
+var placeholderPattern = regexp.MustCompile(`\[\s*T\s*(\d+)\s*\]`)
+
+// Replaces engine tokens with placeholders [T0], [T1], … Returns the text
+// and the replaced tokens.
+func Protect(s string) (string, []string) {
+	var tokens []string
+	protected := tokenPattern.ReplaceAllStringFunc(s, func(token string) string {
+		tokens = append(tokens, token)
+		return fmt.Sprintf("[T%d]", len(tokens)-1)
+	})
+	return protected, tokens
+}
+
+// Puts the tokens back in place of the placeholders. Returns an error if a
+// token is missing, duplicated or unknown in the translation.
+func Restore(s string, tokens []string) (string, error) {
+	seen := make([]bool, len(tokens))
+	var restoreErr error
+
+	restored := placeholderPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+		idx, _ := strconv.Atoi(placeholderPattern.FindStringSubmatch(placeholder)[1])
+		if idx >= len(tokens) {
+			restoreErr = fmt.Errorf("unknown token placeholder %s", placeholder)
+			return placeholder
+		}
+		if seen[idx] {
+			restoreErr = fmt.Errorf("token %s is duplicated", tokens[idx])
+		}
+		seen[idx] = true
+		return tokens[idx]
+	})
+
+	if restoreErr != nil {
+		return "", restoreErr
+	}
+	for i, ok := range seen {
+		if !ok {
+			return "", fmt.Errorf("token %s is lost", tokens[i])
+		}
+	}
+	return restored, nil
+}
+
+// Translation is the result for a single text.
+type Translation struct {
+	Text string
+	Err  error
+}
+
+// Translates the texts in batches of the given size, protecting engine
+// tokens. A text whose tokens were damaged by the provider gets an error
+// instead of the translation. Returns an error if a request fails.
+func TranslateTexts(ctx context.Context, p Provider, texts []string, source, target string, batchSize int) ([]Translation, error) {
+	if batchSize <= 0 {
+		batchSize = len(texts)
+	}
+
+	results := make([]Translation, 0, len(texts))
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+
+		protected := make([]string, 0, end-start)
+		tokens := make([][]string, 0, end-start)
+		for _, s := range texts[start:end] {
+			text, textTokens := Protect(s)
+			protected = append(protected, text)
+			tokens = append(tokens, textTokens)
+		}
+
+		translated, err := p.Translate(ctx, protected, source, target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name(), err)
+		}
+		if len(translated) != len(protected) {
+			return nil, fmt.Errorf("%s: got %d translations for %d texts", p.Name(), len(translated), len(protected))
+		}
+
+		for i, s := range translated {
+			restored, err := Restore(s, tokens[i])
+			results = append(results, Translation{Text: restored, Err: err})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package mt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProtectRestore(t *testing.T) {
+	protected, tokens := Protect("Hello, <CHARNAME>! ^0xFF00FF00Gold^- [b]now[/b]")
+	if want := "Hello, [T0]! [T1]Gold[T2] [T3]now[T4]"; protected != want {
+		t.Fatalf("Protect() = %q, want %q", protected, want)
+	}
+
+	restored, err := Restore("Привіт, [ T0 ]! [T1]Золото[T2] [T3]зараз[T4]", tokens)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if want := "Привіт, <CHARNAME>! ^0xFF00FF00Золото^- [b]зараз[/b]"; restored != want {
+		t.Errorf("Restore() = %q, want %q", restored, want)
+	}
+
+	for _, broken := range []string{"Привіт!", "[T0] [T0]", "[T0] [T1] [T2] [T3] [T4] [T5]"} {
+		if _, err := Restore(broken, tokens); err == nil {
+			t.Errorf("Restore(%q) error = nil, want error", broken)
+		}
+	}
+}
+
+func TestLibreTranslate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req libreTranslateRequest
+		if r.URL.Path != "/translate" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad request"})
+			return
+		}
+		if req.ApiKey != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid API key"})
+			return
+		}
+
+		translated := make([]string, len(req.Q))
+		for i, q := range req.Q {
+			// Drops the tokens of texts containing "lose".
+			if strings.Contains(q, "lose") {
+				q = "lost"
+			}
+			translated[i] = req.Target + ":" + strings.ReplaceAll(q, "[T0]", "[ T0 ]")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"translatedText": translated})
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslate(server.URL+"/", "secret")
+	results, err := TranslateTexts(context.Background(), provider, []string{"Hi, <CHARNAME>", "Ale", "lose <GABBER>"}, "en", "uk", 2)
+	if err != nil {
+		t.Fatalf("TranslateTexts() error = %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("TranslateTexts() = %v, want 3 results", results)
+	}
+	if results[0].Text != "uk:Hi, <CHARNAME>" || results[0].Err != nil {
+		t.Errorf("results[0] = %+v, want token restored", results[0])
+	}
+	if results[1].Text != "uk:Ale" || results[1].Err != nil {
+		t.Errorf("results[1] = %+v, want uk:Ale", results[1])
+	}
+	if results[2].Err == nil {
+		t.Errorf("results[2] = %+v, want lost token error", results[2])
+	}
+
+	provider.ApiKey = "wrong"
+	if _, err := TranslateTexts(context.Background(), provider, []string{"Ale"}, "en", "uk", 0); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("TranslateTexts() error = %v, want API key error", err)
+	}
+}
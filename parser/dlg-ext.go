@@ -6,6 +6,7 @@
 package parser
 
 import (
+	"encoding/binary"
 	"log"
 
 	"github.com/spf13/afero"
@@ -24,6 +25,18 @@ func (d *DlgFile) Close() error {
 	return d.File.Close()
 }
 
+// Returns the whole threat flags word, including the bits not modelled by
+// Dlg_HeaderFlags.
+func (d *Dlg) RawThreatFlags() uint32 {
+	return binary.LittleEndian.Uint32(d._raw_ThreatFlags)
+}
+
+// Returns the whole flags word of the transition, including the bits not
+// modelled by Dlg_TransitionEntry_Flags.
+func (t *Dlg_TransitionEntry) RawFlags() uint32 {
+	return binary.LittleEndian.Uint32(t._raw_Flags)
+}
+
 func (s *Dlg_StateEntry) GetTriggerText() (string, bool) {
 	trigger, err := s.Trigger()
 	if trigger == nil || err != nil {
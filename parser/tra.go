@@ -19,10 +19,59 @@ type TraEntry struct {
 	MaleText   string
 	FemaleText string // empty if no female variant
 	SoundFile  string // optional
+
+	Syntax *TraEntrySyntax // nil if the entry was not parsed from a file
 }
 
 type TraFile struct {
 	Entries []TraEntry
+
+	// Concrete syntax tree: entries, comments and whitespace in the
+	// original order. Empty if the file was not parsed.
+	Nodes []TraNode
+}
+
+type TraNodeKind int
+
+const (
+	TraWhitespace TraNodeKind = iota
+	TraLineComment
+	TraBlockComment
+	TraEntryNode
+)
+
+// TraNode is a top-level element of a TRA file.
+type TraNode struct {
+	Kind  TraNodeKind
+	Raw   string // original text of comments and whitespace
+	Entry int    // index in TraFile.Entries for TraEntryNode
+}
+
+// TraSpan is a byte range [Start, End) of TraEntrySyntax.Raw.
+// Start is -1 if the part is absent.
+type TraSpan struct {
+	Start, End int
+}
+
+func (s TraSpan) IsEmpty() bool {
+	return s.Start < 0
+}
+
+// TraTextSyntax is a text expression as written in the file.
+type TraTextSyntax struct {
+	Span      TraSpan
+	Delimiter string // delimiter of the first string: ~, %, " or ~~~~~
+	Value     string // text at parse time
+}
+
+// TraEntrySyntax is an entry as written in the file, e.g.
+// "@0012 = ~Hi~ ^ ~there~ [SND]".
+type TraEntrySyntax struct {
+	Raw        string
+	MaleText   TraTextSyntax
+	FemaleText TraTextSyntax
+	SoundFile  TraSpan // including brackets
+	SoundValue string  // sound file at parse time
 }
 
 func ParseTra(r io.Reader) (*TraFile, error) {
@@ -49,10 +98,11 @@ func parseTra(content string) (*TraFile, error) {
 	pos := 0
 
 	for pos < len(content) {
-		pos = skipWhitespaceAndComments(content, pos)
+		pos = scanTrivia(tf, content, pos)
 		if pos >= len(content) {
 			break
 		}
+		entryStart := pos
 
 		// Expect @ or !
 		if content[pos] != '@' && content[pos] != '!' {
@@ -91,12 +141,19 @@ func parseTra(content string) (*TraFile, error) {
 			pos++
 		}
 
+		syntax := &TraEntrySyntax{
+			FemaleText: TraTextSyntax{Span: TraSpan{-1, -1}},
+			SoundFile:  TraSpan{-1, -1},
+		}
+
 		// Parse first text expression (male text)
 		maleText, newPos, err := parseTextExpression(content, pos)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse male text at position %d: %w", pos, err)
 		}
+		syntax.MaleText = textSyntax(content, entryStart, pos, newPos, maleText)
 		pos = newPos
+		entryEnd := pos
 
 		// Skip whitespace
 		for pos < len(content) && unicode.IsSpace(rune(content[pos])) {
@@ -114,7 +171,9 @@ func parseTra(content string) (*TraFile, error) {
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse female text at position %d: %w", pos, err)
 				}
+				syntax.FemaleText = textSyntax(content, entryStart, pos, newPos, femaleText)
 				pos = newPos
+				entryEnd = pos
 
 				// Skip whitespace
 				for pos < len(content) && unicode.IsSpace(rune(content[pos])) {
@@ -128,37 +187,52 @@ func parseTra(content string) (*TraFile, error) {
 				if err != nil {
 					return nil, fmt.Errorf("failed to parse sound file at position %d: %w", pos, err)
 				}
+				syntax.SoundFile = TraSpan{pos - entryStart, newPos - entryStart}
+				syntax.SoundValue = soundFile
 				pos = newPos
+				entryEnd = pos
 			}
 		}
 
+		// Whitespace after the entry belongs to the next trivia node.
+		pos = entryEnd
+		syntax.Raw = content[entryStart:entryEnd]
+
+		tf.Nodes = append(tf.Nodes, TraNode{Kind: TraEntryNode, Entry: len(tf.Entries)})
 		tf.Entries = append(tf.Entries, TraEntry{
 			ID:         uint32(idVal),
 			MaleText:   maleText,
 			FemaleText: femaleText,
 			SoundFile:  soundFile,
+			Syntax:     syntax,
 		})
 	}
 
 	return tf, nil
 }
 
-// Skips whitespace and C++ style comments.
+// Skips whitespace and C++ style comments, adding them to the file nodes.
 // Returns the new position after skipping.
-func skipWhitespaceAndComments(content string, pos int) int {
+func scanTrivia(tf *TraFile, content string, pos int) int {
 	for pos < len(content) {
+		start := pos
+
 		// Skip whitespace
 		if unicode.IsSpace(rune(content[pos])) {
-			pos++
+			for pos < len(content) && unicode.IsSpace(rune(content[pos])) {
+				pos++
+			}
+			tf.Nodes = append(tf.Nodes, TraNode{Kind: TraWhitespace, Raw: content[start:pos]})
 			continue
 		}
 
 		// Check for single-line comment
 		if pos+1 < len(content) && content[pos] == '/' && content[pos+1] == '/' {
 			pos += 2
-			for pos < len(content) && content[pos] != '\n' {
+			for pos < len(content) && content[pos] != '\n' && content[pos] != '\r' {
 				pos++
 			}
+			tf.Nodes = append(tf.Nodes, TraNode{Kind: TraLineComment, Raw: content[start:pos]})
 			continue
 		}
 
@@ -170,7 +244,10 @@ func skipWhitespaceAndComments(content string, pos int) int {
 			}
 			if pos+1 < len(content) {
 				pos += 2 // Skip */
+			} else {
+				pos = len(content)
 			}
+			tf.Nodes = append(tf.Nodes, TraNode{Kind: TraBlockComment, Raw: content[start:pos]})
 			continue
 		}
 
@@ -179,6 +256,26 @@ func skipWhitespaceAndComments(content string, pos int) int {
 	return pos
 }
 
+// Returns the syntax of the text expression content[start:end] of the
+// entry starting at entryStart.
+func textSyntax(content string, entryStart, start, end int, value string) TraTextSyntax {
+	for start < end && unicode.IsSpace(rune(content[start])) {
+		start++
+	}
+	delimiter := "~"
+	if start < end {
+		delimiter = content[start : start+1]
+	}
+	if strings.HasPrefix(content[start:], "~~~~~") {
+		delimiter = "~~~~~"
+	}
+	return TraTextSyntax{
+		Span:      TraSpan{start - entryStart, end - entryStart},
+		Delimiter: delimiter,
+		Value:     value,
+	}
+}
+
 // Checks if position starts a text delimiter.
 func isDelimiterStart(content string, pos int) bool {
 	if pos >= len(content) {
@@ -189,7 +286,7 @@ func isDelimiterStart(content string, pos int) bool {
 }
 
 // Parses a text expression which may include concatenation.
-// Returns the parsed text, new position, and any error.
+// Returns the parsed text, the position after its last string, and any error.
 func parseTextExpression(content string, pos int) (string, int, error) {
 	var result strings.Builder
 
@@ -211,14 +308,13 @@ func parseTextExpression(content string, pos int) (string, int, error) {
 		result.WriteString(text)
 		pos = newPos
 
-		// Skip whitespace
-		for pos < len(content) && unicode.IsSpace(rune(content[pos])) {
-			pos++
-		}
-
 		// Check for concatenation operator
-		if pos < len(content) && content[pos] == '^' {
-			pos++
+		next := pos
+		for next < len(content) && unicode.IsSpace(rune(content[next])) {
+			next++
+		}
+		if next < len(content) && content[next] == '^' {
+			pos = next + 1
 			continue
 		}
 
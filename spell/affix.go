@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package spell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type flagMode int
+
+const (
+	flagShort flagMode = iota
+	flagLong
+	flagNum
+	flagUtf8
+)
+
+type flagSet map[string]struct{}
+
+func (s flagSet) has(flag string) bool {
+	if flag == "" {
+		return false
+	}
+	_, ok := s[flag]
+	return ok
+}
+
+// affixRule is a single PFX or SFX rule of the .aff file.
+type affixRule struct {
+	flag     string
+	prefix   bool
+	cross    bool
+	strip    string
+	add      string
+	addFlags flagSet
+	cond     condition
+}
+
+// affixes holds the subset of the Hunspell .aff file needed to check words.
+type affixes struct {
+	encoding    string
+	mode        flagMode
+	aliases     []flagSet
+	prefixes    map[string][]*affixRule
+	suffixes    map[string][]*affixRule
+	needAffix   string
+	forbidden   string
+	keepCase    string
+	onlyInComp  string
+	ignore      string
+	iconv       [][2]string
+	crossByFlag map[string]bool
+}
+
+func parseAff(r io.Reader) (*affixes, error) {
+	aff := &affixes{
+		encoding:    "UTF-8",
+		prefixes:    make(map[string][]*affixRule),
+		suffixes:    make(map[string][]*affixRule),
+		crossByFlag: make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	aliasHeader := false
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "SET":
+			if len(fields) > 1 {
+				aff.encoding = fields[1]
+			}
+		case "FLAG":
+			if len(fields) > 1 {
+				switch fields[1] {
+				case "long":
+					aff.mode = flagLong
+				case "num":
+					aff.mode = flagNum
+				case "UTF-8":
+					aff.mode = flagUtf8
+				}
+			}
+		case "AF":
+			// The first AF line holds the number of aliases.
+			if !aliasHeader {
+				aliasHeader = true
+				continue
+			}
+			if len(fields) > 1 {
+				aff.aliases = append(aff.aliases, aff.parseFlagsNoAlias(fields[1]))
+			}
+		case "NEEDAFFIX", "PSEUDOROOT":
+			if len(fields) > 1 {
+				aff.needAffix = fields[1]
+			}
+		case "FORBIDDENWORD":
+			if len(fields) > 1 {
+				aff.forbidden = fields[1]
+			}
+		case "KEEPCASE":
+			if len(fields) > 1 {
+				aff.keepCase = fields[1]
+			}
+		case "ONLYINCOMPOUND":
+			if len(fields) > 1 {
+				aff.onlyInComp = fields[1]
+			}
+		case "IGNORE":
+			if len(fields) > 1 {
+				aff.ignore = fields[1]
+			}
+		case "ICONV":
+			if len(fields) > 2 {
+				aff.iconv = append(aff.iconv, [2]string{fields[1], fields[2]})
+			}
+		case "PFX", "SFX":
+			if err := aff.parseAffixLine(fields); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return aff, nil
+}
+
+func (aff *affixes) parseAffixLine(fields []string) error {
+	prefix := fields[0] == "PFX"
+	if len(fields) < 4 {
+		return fmt.Errorf("invalid %s line", fields[0])
+	}
+
+	flag := fields[1]
+
+	// Header line: PFX <flag> <cross product Y/N> <count>
+	if _, err := strconv.Atoi(fields[3]); err == nil && len(fields) == 4 && (fields[2] == "Y" || fields[2] == "N") {
+		aff.crossByFlag[flag] = fields[2] == "Y"
+		return nil
+	}
+
+	rule := &affixRule{
+		flag:   flag,
+		prefix: prefix,
+		cross:  aff.crossByFlag[flag],
+	}
+
+	if fields[2] != "0" {
+		rule.strip = fields[2]
+	}
+
+	add := fields[3]
+	if idx := strings.IndexByte(add, '/'); idx >= 0 {
+		rule.addFlags = aff.parseFlags(add[idx+1:])
+		add = add[:idx]
+	}
+	if add != "0" {
+		rule.add = aff.removeIgnored(add)
+	}
+
+	condText := "."
+	if len(fields) > 4 {
+		condText = fields[4]
+	}
+	cond, err := parseCondition(condText)
+	if err != nil {
+		return err
+	}
+	rule.cond = cond
+
+	if prefix {
+		aff.prefixes[rule.add] = append(aff.prefixes[rule.add], rule)
+	} else {
+		aff.suffixes[rule.add] = append(aff.suffixes[rule.add], rule)
+	}
+	return nil
+}
+
+// Parses flags of a dictionary word or an affix continuation, resolving AF aliases.
+func (aff *affixes) parseFlags(s string) flagSet {
+	if len(aff.aliases) > 0 {
+		if idx, err := strconv.Atoi(s); err == nil && idx >= 1 && idx <= len(aff.aliases) {
+			return aff.aliases[idx-1]
+		}
+		return make(flagSet)
+	}
+	return aff.parseFlagsNoAlias(s)
+}
+
+func (aff *affixes) parseFlagsNoAlias(s string) flagSet {
+	flags := make(flagSet)
+
+	switch aff.mode {
+	case flagLong:
+		runes := []rune(s)
+		for i := 0; i+1 < len(runes); i += 2 {
+			flags[string(runes[i:i+2])] = struct{}{}
+		}
+	case flagNum:
+		for _, flag := range strings.Split(s, ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				flags[flag] = struct{}{}
+			}
+		}
+	default:
+		for _, r := range s {
+			flags[string(r)] = struct{}{}
+		}
+	}
+	return flags
+}
+
+func (aff *affixes) removeIgnored(s string) string {
+	if aff.ignore == "" {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(aff.ignore, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Applies ICONV conversions and removes IGNORE characters of the input word.
+func (aff *affixes) normalize(word string) string {
+	for _, conv := range aff.iconv {
+		word = strings.ReplaceAll(word, conv[0], conv[1])
+	}
+	return aff.removeIgnored(word)
+}
+
+// condition is a simplified Hunspell affix condition: a sequence of
+// characters, character classes ([abc], [^abc]) and dots.
+type condition []charClass
+
+type charClass struct {
+	any    bool
+	negate bool
+	chars  string
+}
+
+func parseCondition(s string) (condition, error) {
+	if s == "." {
+		return nil, nil
+	}
+
+	var cond condition
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			cond = append(cond, charClass{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated character class in condition %q", s)
+			}
+			class := charClass{chars: string(runes[i+1 : end])}
+			if strings.HasPrefix(class.chars, "^") {
+				class.negate = true
+				class.chars = class.chars[1:]
+			}
+			cond = append(cond, class)
+			i = end
+		default:
+			cond = append(cond, charClass{chars: string(runes[i])})
+		}
+	}
+	return cond, nil
+}
+
+func (c charClass) match(r rune) bool {
+	if c.any {
+		return true
+	}
+	return strings.ContainsRune(c.chars, r) != c.negate
+}
+
+// Checks the condition against the start of the stem (for prefixes).
+func (c condition) matchStart(stem string) bool {
+	runes := []rune(stem)
+	if len(runes) < len(c) {
+		return false
+	}
+	for i, class := range c {
+		if !class.match(runes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks the condition against the end of the stem (for suffixes).
+func (c condition) matchEnd(stem string) bool {
+	runes := []rune(stem)
+	if len(runes) < len(c) {
+		return false
+	}
+	offset := len(runes) - len(c)
+	for i, class := range c {
+		if !class.match(runes[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,334 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package spell is a minimal Hunspell-compatible spell checker.
+//
+// It supports prefixes and suffixes (including the cross product and one
+// level of suffix continuation), FLAG modes, AF aliases, NEEDAFFIX,
+// FORBIDDENWORD, KEEPCASE, ICONV and IGNORE. Compounding and suggestions
+// are not supported.
+package spell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+type Dictionary struct {
+	aff   *affixes
+	words map[string][]flagSet
+}
+
+var setLine = regexp.MustCompile(`(?m)^SET\s+(\S+)`)
+
+// Loads a Hunspell dictionary from the .aff and .dic files.
+func Load(affPath, dicPath string) (*Dictionary, error) {
+	affData, err := os.ReadFile(affPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read affix file: %w", err)
+	}
+	dicData, err := os.ReadFile(dicPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read dictionary file: %w", err)
+	}
+	return Parse(affData, dicData)
+}
+
+// Parses a Hunspell dictionary from the contents of the .aff and .dic files,
+// decoding them from the encoding given in the SET directive.
+func Parse(affData, dicData []byte) (*Dictionary, error) {
+	if m := setLine.FindSubmatch(affData); m != nil && !strings.EqualFold(string(m[1]), "UTF-8") {
+		enc, err := ianaindex.IANA.Encoding(string(m[1]))
+		if err != nil || enc == nil {
+			return nil, fmt.Errorf("unsupported dictionary encoding %q", m[1])
+		}
+		if affData, err = enc.NewDecoder().Bytes(affData); err != nil {
+			return nil, fmt.Errorf("unable to decode affix file: %w", err)
+		}
+		if dicData, err = enc.NewDecoder().Bytes(dicData); err != nil {
+			return nil, fmt.Errorf("unable to decode dictionary file: %w", err)
+		}
+	}
+
+	aff, err := parseAff(bytes.NewReader(affData))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse affix file: %w", err)
+	}
+
+	d := &Dictionary{aff: aff, words: make(map[string][]flagSet)}
+	if err := d.readWords(bytes.NewReader(dicData), true); err != nil {
+		return nil, fmt.Errorf("unable to parse dictionary file: %w", err)
+	}
+	return d, nil
+}
+
+// Adds words of a personal dictionary: one word per line, optionally with
+// flags after a slash. Lines starting with # are comments.
+func (d *Dictionary) LoadPersonal(r io.Reader) error {
+	return d.readWords(r, false)
+}
+
+// Adds a word without affixes, e.g. a proper name.
+func (d *Dictionary) AddWord(word string) {
+	word = d.aff.normalize(word)
+	if word != "" {
+		d.words[word] = append(d.words[word], flagSet{})
+	}
+}
+
+func (d *Dictionary) readWords(r io.Reader, hasCount bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	first := true
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			first = false
+			line = strings.TrimPrefix(line, "\uFEFF")
+			if hasCount {
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Morphological fields follow the word after a tab or a space.
+		if idx := strings.IndexAny(line, "\t "); idx >= 0 {
+			line = line[:idx]
+		}
+
+		word, flags := splitWordFlags(line)
+		word = d.aff.normalize(word)
+		if word == "" {
+			continue
+		}
+		d.words[word] = append(d.words[word], d.aff.parseFlags(flags))
+	}
+
+	return scanner.Err()
+}
+
+// Splits "word/flags", honoring the escaped slash "\/" in the word.
+func splitWordFlags(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '/' {
+			i++
+			continue
+		}
+		if line[i] == '/' {
+			return strings.ReplaceAll(line[:i], `\/`, "/"), line[i+1:]
+		}
+	}
+	return strings.ReplaceAll(line, `\/`, "/"), ""
+}
+
+// Reports whether the word is spelled correctly. A word written with
+// a capital letter or in upper case is also accepted in lower case.
+// Hyphenated words are accepted if every part is correct.
+func (d *Dictionary) Check(word string) bool {
+	word = d.aff.normalize(word)
+	if word == "" {
+		return true
+	}
+
+	if d.checkCased(word) {
+		return true
+	}
+
+	if strings.Contains(word, "-") {
+		parts := strings.Split(word, "-")
+		for _, part := range parts {
+			if part != "" && !d.checkCased(part) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func (d *Dictionary) checkCased(word string) bool {
+	if d.checkWord(word, false) {
+		return true
+	}
+
+	switch caseOf(word) {
+	case caseUpper:
+		if d.checkWord(capitalize(strings.ToLower(word)), true) {
+			return true
+		}
+		return d.checkWord(strings.ToLower(word), true)
+	case caseCapitalized:
+		return d.checkWord(strings.ToLower(word), true)
+	default:
+		return false
+	}
+}
+
+// Checks the word as a root or a root with affixes. If recased is set,
+// roots with KEEPCASE flag are not accepted.
+func (d *Dictionary) checkWord(word string, recased bool) bool {
+	if d.forbidden(word) {
+		return false
+	}
+
+	valid := func(flags flagSet) bool {
+		return !flags.has(d.aff.forbidden) && !(recased && flags.has(d.aff.keepCase))
+	}
+
+	for _, flags := range d.words[word] {
+		if valid(flags) && !flags.has(d.aff.needAffix) && !flags.has(d.aff.onlyInComp) {
+			return true
+		}
+	}
+
+	if d.checkSuffix(word, nil, valid, true) {
+		return true
+	}
+
+	for i := 0; i <= len(word); i++ {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, pfx := range d.aff.prefixes[word[:i]] {
+			stem := pfx.strip + word[i:]
+			if stem == "" || !pfx.cond.matchStart(stem) {
+				continue
+			}
+			for _, flags := range d.words[stem] {
+				if flags.has(pfx.flag) && valid(flags) {
+					return true
+				}
+			}
+			if pfx.cross && d.checkSuffix(stem, pfx, valid, false) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Checks the word as a root with a suffix. If pfx is not nil, the root
+// must allow the prefix as well. With twofold set, a suffix attached to
+// another suffix (continuation class) is also accepted.
+func (d *Dictionary) checkSuffix(word string, pfx *affixRule, valid func(flagSet) bool, twofold bool) bool {
+	for i := 0; i <= len(word); i++ {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, sfx := range d.aff.suffixes[word[i:]] {
+			if pfx != nil && !sfx.cross {
+				continue
+			}
+			stem := word[:i] + sfx.strip
+			if stem == "" || !sfx.cond.matchEnd(stem) {
+				continue
+			}
+
+			for _, flags := range d.words[stem] {
+				if !flags.has(sfx.flag) || !valid(flags) {
+					continue
+				}
+				if pfx == nil || flags.has(pfx.flag) || sfx.addFlags.has(pfx.flag) {
+					return true
+				}
+			}
+
+			if twofold && pfx == nil && d.checkContinuation(stem, sfx.flag, valid) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Checks the word as a root with a suffix that allows continuation with flag.
+func (d *Dictionary) checkContinuation(word, flag string, valid func(flagSet) bool) bool {
+	for i := 0; i <= len(word); i++ {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, sfx := range d.aff.suffixes[word[i:]] {
+			if !sfx.addFlags.has(flag) {
+				continue
+			}
+			stem := word[:i] + sfx.strip
+			if stem == "" || !sfx.cond.matchEnd(stem) {
+				continue
+			}
+			for _, flags := range d.words[stem] {
+				if flags.has(sfx.flag) && valid(flags) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (d *Dictionary) forbidden(word string) bool {
+	if d.aff.forbidden == "" {
+		return false
+	}
+	for _, flags := range d.words[word] {
+		if flags.has(d.aff.forbidden) {
+			return true
+		}
+	}
+	return false
+}
+
+type wordCase int
+
+const (
+	caseLower wordCase = iota
+	caseCapitalized
+	caseUpper
+	caseMixed
+)
+
+func caseOf(word string) wordCase {
+	upper, lower := 0, 0
+	firstUpper := false
+	for i, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+			if i == 0 {
+				firstUpper = true
+			}
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return caseLower
+	case lower == 0:
+		return caseUpper
+	case firstUpper && upper == 1:
+		return caseCapitalized
+	default:
+		return caseMixed
+	}
+}
+
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
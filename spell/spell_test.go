@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package spell
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testAff = `SET UTF-8
+FLAG long
+ICONV 1
+ICONV ’ '
+FORBIDDENWORD Fb
+KEEPCASE Kc
+
+PFX Pn Y 1
+PFX Pn 0 не .
+
+SFX Sa Y 3
+SFX Sa а і а
+SFX Sa а у а
+SFX Sa а ою/Sb а
+
+SFX Sb Y 1
+SFX Sb 0 сь ю
+`
+
+const testDic = `6
+книга/PnSa
+місто
+мить
+Київ/Kc
+ім'я
+книгу/Fb
+`
+
+func TestCheck(t *testing.T) {
+	d, err := Parse([]byte(testAff), []byte(testDic))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	d.AddWord("Імоен")
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"книга", true},
+		{"книгі", true},
+		{"книгою", true},
+		{"книгоюсь", true},
+		{"некнига", true},
+		{"некнигі", true},
+		{"немісто", false},
+		{"книгу", false},
+		{"Книга", true},
+		{"КНИГА", true},
+		{"київ", false},
+		{"Київ", true},
+		{"ім’я", true},
+		{"Імоен", true},
+		{"книга-місто", true},
+		{"кника", false},
+	}
+
+	for _, tt := range tests {
+		if got := d.Check(tt.word); got != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestLoadPersonal(t *testing.T) {
+	d, err := Parse([]byte(testAff), []byte(testDic))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if err := d.LoadPersonal(strings.NewReader("# names\nБалдур\nсвиток/Sa\n")); err != nil {
+		t.Fatalf("LoadPersonal() error = %v", err)
+	}
+
+	for _, word := range []string{"Балдур", "свиток"} {
+		if !d.Check(word) {
+			t.Errorf("Check(%q) = false, want true", word)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("<CHARNAME>, ^0xFF00FF00ім'я^- — це [b]книга-місто[/b]! 10 золотих, X2 і 'лапки'.")
+	want := []string{"ім'я", "це", "книга-місто", "золотих", "і", "лапки"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Words() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package spell
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Engine tokens (<CHARNAME>), EE color tags (^0xFFRRGGBB ... ^-) and
+// markup in square or curly brackets written without spaces ([b], {i}).
+var markup = regexp.MustCompile(`<[^<>\s]*>|\^0x[0-9A-Fa-f]{8}|\^-|\[/?[A-Za-z]+\]|\{/?[A-Za-z]+\}`)
+
+// Returns the words of the text, skipping engine tokens, markup and words
+// with digits. Apostrophes and hyphens inside a word are kept.
+func Words(text string) []string {
+	text = markup.ReplaceAllString(text, " ")
+
+	var words []string
+	runes := []rune(text)
+	start := -1
+	hasDigit := false
+
+	flush := func(end int) {
+		if start >= 0 {
+			word := strings.TrimRight(string(runes[start:end]), "-'’ʼ")
+			if word != "" && !hasDigit {
+				words = append(words, word)
+			}
+		}
+		start = -1
+		hasDigit = false
+	}
+
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			if start < 0 {
+				start = i
+			}
+		case unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			hasDigit = true
+		case isInnerPunct(r) && start >= 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			// part of the word
+		default:
+			flush(i)
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+func isInnerPunct(r rune) bool {
+	switch r {
+	case '-', '\'', '’', 'ʼ':
+		return true
+	default:
+		return false
+	}
+}
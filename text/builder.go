@@ -7,6 +7,7 @@ package text
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -24,6 +25,7 @@ type TextEntry struct {
 	VolumeVariance uint32
 	PitchVariance  uint32
 	Labels         map[string]struct{}
+	Resources      map[string]struct{}
 	Context        map[ContextType]map[string][]string
 }
 
@@ -46,6 +48,9 @@ const (
 	ContextWorldMap
 )
 
+// Context key of the ARE resref of an area name on a world map.
+const worldMapAreaKey = "Area resref"
+
 const (
 	lb_ability             = "ability"
 	lb_area                = "area"
@@ -102,6 +107,7 @@ func NewTextCollection(tlk *p.Tlk) *TextCollection {
 			VolumeVariance: entry.VolumeVariance,
 			PitchVariance:  entry.PitchVariance,
 			Labels:         make(map[string]struct{}),
+			Resources:      make(map[string]struct{}),
 			Context:        make(map[ContextType]map[string][]string),
 		}
 		collection.Entries[id] = tEntry
@@ -152,6 +158,29 @@ func (c *TextCollection) AddLabel(id uint32, label string) {
 	}
 }
 
+// Remembers the area a world map name belongs to, e.g. for the glossary.
+// The name is not attributed to the ARE resource: it is not used there.
+func (c *TextCollection) addAreaResref(id uint32, resref string) {
+	resref = strings.ToUpper(strings.TrimRight(resref, "\x00"))
+	if resref == "" {
+		return
+	}
+	if entry, ok := c.Entries[id]; ok && !slices.Contains(entry.Context[ContextWorldMap][worldMapAreaKey], resref) {
+		c.AddContext(id, ContextWorldMap, worldMapAreaKey, resref)
+	}
+}
+
+// Remembers the game resource (e.g. AR0202.ARE) the text is used in.
+func (c *TextCollection) AddResource(id uint32, resource string) {
+	if id == 0 || id == 0xFFFFFFFF {
+		return
+	}
+
+	if entry, ok := c.Entries[id]; ok {
+		entry.Resources[strings.ToUpper(resource)] = struct{}{}
+	}
+}
+
 func (c *TextCollection) LoadContextFromDialogs(baseUrl string, dlg *dialog.DialogCollection) {
 	const labelFormat = "dialog %d @ %s"
 
@@ -160,6 +189,7 @@ func (c *TextCollection) LoadContextFromDialogs(baseUrl string, dlg *dialog.Dial
 			switch node.Type {
 			case dialog.StateNodeType:
 				ref := node.State.TextRef
+				c.AddResource(ref, node.Origin.DlgName+".DLG")
 				c.AddContext(ref, ContextDialog, d.Id.String(), node.ToUrl(baseUrl))
 
 				c.AddLabel(ref, lb_dialog)
@@ -171,6 +201,7 @@ func (c *TextCollection) LoadContextFromDialogs(baseUrl string, dlg *dialog.Dial
 
 				if node.Transition.HasText {
 					ref := node.Transition.TextRef
+					c.AddResource(ref, node.Origin.DlgName+".DLG")
 					c.AddContext(ref, ContextDialog, d.Id.String(), url)
 					c.AddLabel(ref, lb_dialog)
 					c.AddLabel(ref, lb_dialog_answer)
@@ -180,6 +211,7 @@ func (c *TextCollection) LoadContextFromDialogs(baseUrl string, dlg *dialog.Dial
 
 				if node.Transition.HasJournalText {
 					ref := node.Transition.JournalTextRef
+					c.AddResource(ref, node.Origin.DlgName+".DLG")
 					c.AddContext(ref, ContextDialog, d.Id.String(), url)
 					c.AddLabel(ref, lb_dialog)
 					c.AddLabel(ref, lb_dialog_journal)
@@ -193,31 +225,50 @@ func (c *TextCollection) LoadContextFromDialogs(baseUrl string, dlg *dialog.Dial
 	}
 }
 
-func (c *TextCollection) LoadContextFromUiScreens(uiFilename string, chu *p.Chu) error {
+// UiLabel is a label control of a CHU window displaying a strref.
+type UiLabel struct {
+	Resource string
+	Window   uint16
+	Control  int16
+	Strref   uint32
+	Font     string
+	Width    int
+	Height   int
+}
+
+// Returns all label controls of the CHU file that display a strref.
+func UiLabelsFromChu(uiFilename string, chu *p.Chu) ([]UiLabel, error) {
 	windows, err := chu.Windows()
 	if err != nil {
-		return fmt.Errorf("unable to get windows from CHU: %v", err)
+		return nil, fmt.Errorf("unable to get windows from CHU: %v", err)
 	}
 
+	var labels []UiLabel
 	for _, window := range windows {
 		controls, err := window.Controls()
 		if err != nil {
-			return fmt.Errorf("unable to get controls from window: %v", err)
+			return nil, fmt.Errorf("unable to get controls from window: %v", err)
 		}
 
 		for _, control := range controls {
 			data, err := control.Data()
 			if err != nil {
-				return fmt.Errorf("unable to get control struct: %v", err)
+				return nil, fmt.Errorf("unable to get control struct: %v", err)
 			}
 
 			switch data.Type {
 			case p.Chu_Control_ControlStruct_StructType__Label:
 				label := data.Properties.(*p.Chu_Control_ControlStruct_Label)
 				if label.InitialTextRef != 0 && label.InitialTextRef != 0xFFFFFFFF {
-					ref := label.InitialTextRef
-					c.AddLabel(ref, lb_ui)
-					c.AddContext(ref, ContextUI, uiFilename, fmt.Sprintf("window %d → control %d", window.WinId, int16(data.ControlId)))
+					labels = append(labels, UiLabel{
+						Resource: uiFilename,
+						Window:   window.WinId,
+						Control:  int16(data.ControlId),
+						Strref:   label.InitialTextRef,
+						Font:     strings.ToUpper(strings.TrimRight(label.Font, "\x00")),
+						Width:    int(data.Width),
+						Height:   int(data.Height),
+					})
 				}
 			default:
 				continue
@@ -225,6 +276,22 @@ func (c *TextCollection) LoadContextFromUiScreens(uiFilename string, chu *p.Chu)
 		}
 	}
 
+	return labels, nil
+}
+
+func (c *TextCollection) LoadContextFromUiScreens(uiFilename string, chu *p.Chu) error {
+	labels, err := UiLabelsFromChu(uiFilename, chu)
+	if err != nil {
+		return err
+	}
+
+	for _, label := range labels {
+		ref := label.Strref
+		c.AddLabel(ref, lb_ui)
+		c.AddResource(ref, uiFilename)
+		c.AddContext(ref, ContextUI, uiFilename, fmt.Sprintf("window %d → control %d", label.Window, label.Control))
+	}
+
 	return nil
 }
 
@@ -232,12 +299,14 @@ func (c *TextCollection) LoadContextFromCreature(creFilename string, cre *p.Cre,
 	longName := cre.LongNameRef
 	if longName != 0 && longName != 0xFFFFFFFF {
 		c.AddLabel(longName, lb_creature)
+		c.AddResource(longName, creFilename)
 		c.AddContext(longName, ContextCreature, "Long name", strings.ToLower(creFilename))
 	}
 
 	shortName := cre.ShortNameRef
 	if shortName != 0 && shortName != 0xFFFFFFFF {
 		c.AddLabel(shortName, lb_creature)
+		c.AddResource(shortName, creFilename)
 		c.AddContext(shortName, ContextCreature, "Short name (tooltip)", strings.ToLower(creFilename))
 	}
 
@@ -258,6 +327,7 @@ func (c *TextCollection) LoadContextFromCreature(creFilename string, cre *p.Cre,
 		if dialog := cre.Body.Header.Dialog; dialog != "" && dialog != "0" && dialog != "None" {
 			c.AddLabel(ref, strings.ToUpper(dialog))
 		}
+		c.AddResource(ref, creFilename)
 		c.AddCreatureSoundContext(ref, identifier, strings.ToLower(creFilename))
 	}
 
@@ -270,6 +340,7 @@ func (c *TextCollection) LoadContextFromCreature(creFilename string, cre *p.Cre,
 				if strref, context := getStrrefFromEffect(uint32(v.Opcode), v.Parameter1); strref != 0 && strref != 0xFFFFFFFF {
 					c.AddLabel(strref, lb_creature)
 					c.AddLabel(strref, lb_effect)
+					c.AddResource(strref, creFilename)
 					c.AddContext(strref, ContextEffect, context, fmt.Sprintf(creatureEffectPattern, creFilename, i))
 				}
 			case *p.Eff_BodyV2:
@@ -283,11 +354,13 @@ func (c *TextCollection) LoadContextFromCreature(creFilename string, cre *p.Cre,
 					for i := fromStrref; i < fromStrref+count; i++ {
 						c.AddLabel(i, lb_effect)
 						c.AddLabel(i, lb_cynicismQuote)
+						c.AddResource(i, creFilename)
 						c.AddContext(i, ContextEffect, "Show floating text", fmt.Sprintf(creatureEffectPattern, creFilename, i))
 					}
 				} else if strref, context := getStrrefFromEffect(uint32(v.Opcode), v.Parameter1); strref != 0 && strref != 0xFFFFFFFF {
 					c.AddLabel(strref, lb_creature)
 					c.AddLabel(strref, lb_effect)
+					c.AddResource(strref, creFilename)
 					c.AddContext(strref, ContextEffect, context, fmt.Sprintf(creatureEffectPattern, creFilename, i))
 				}
 			}
@@ -308,6 +381,7 @@ func (c *TextCollection) LoadContextFromWorldMaps(wmpFilename string, wmp *p.Wmp
 
 		if nameRef != 0 && nameRef != 0xFFFFFFFF {
 			c.AddLabel(nameRef, lb_world_map)
+			c.AddResource(nameRef, wmpFilename)
 			c.AddContext(nameRef, ContextWorldMap, "World map name", fmt.Sprintf("%s → map %d", wmpFilename, wmEntry.MapId))
 		}
 
@@ -319,11 +393,15 @@ func (c *TextCollection) LoadContextFromWorldMaps(wmpFilename string, wmp *p.Wmp
 		for i, area := range areas {
 			if captionRef := area.CaptionRef; captionRef != 0 && captionRef != 0xFFFFFFFF {
 				c.AddLabel(captionRef, lb_area)
+				c.AddResource(captionRef, wmpFilename)
 				c.AddContext(captionRef, ContextWorldMap, "Area caption", fmt.Sprintf("%s → map %d → area %d", wmpFilename, wmEntry.MapId, i))
+				c.addAreaResref(captionRef, area.Area)
 			}
 			if tooltipRef := area.TooltipRef; tooltipRef != 0 && tooltipRef != 0xFFFFFFFF {
 				c.AddLabel(tooltipRef, lb_area)
+				c.AddResource(tooltipRef, wmpFilename)
 				c.AddContext(tooltipRef, ContextWorldMap, "Area tooltip", fmt.Sprintf("%s → map %d → area %d", wmpFilename, wmEntry.MapId, i))
+				c.addAreaResref(tooltipRef, area.Area)
 			}
 		}
 	}
@@ -336,12 +414,14 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 		for i, region := range regions {
 			if infoRef := region.InfoRef; infoRef != 0 && infoRef != 0xFFFFFFFF {
 				c.AddLabel(infoRef, lb_area_trigger)
+				c.AddResource(infoRef, areFilename)
 				c.AddContext(infoRef, ContextArea, "Info point on the map", fmt.Sprintf("%s → trigger %d", areFilename, i))
 			}
 
 			if speakerRef := region.PstSpeakerNameRef; speakerRef != 0 && speakerRef != 0xFFFFFFFF {
 				c.AddLabel(speakerRef, lb_area_trigger)
 				c.AddLabel(speakerRef, lb_area_speaker)
+				c.AddResource(speakerRef, areFilename)
 				c.AddContext(speakerRef, ContextArea, "Trigger's speaker name (PST only)", fmt.Sprintf("%s → trigger %d", areFilename, i))
 			}
 		}
@@ -351,6 +431,7 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 		for i, container := range containers {
 			if lockpickRef := container.LockpickRef; lockpickRef != 0 && lockpickRef != 0xFFFFFFFF {
 				c.AddLabel(lockpickRef, lb_area_container)
+				c.AddResource(lockpickRef, areFilename)
 				c.AddContext(lockpickRef, ContextArea, "Container's lockpicking message", fmt.Sprintf("%s → container %d", areFilename, i))
 			}
 		}
@@ -360,12 +441,14 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 		for i, door := range doors {
 			if unlockMessageRef := door.UnlockMessageRef; unlockMessageRef != 0 && unlockMessageRef != 0xFFFFFFFF {
 				c.AddLabel(unlockMessageRef, lb_area_door)
+				c.AddResource(unlockMessageRef, areFilename)
 				c.AddContext(unlockMessageRef, ContextArea, "Unlock message", fmt.Sprintf("%s → door %d", areFilename, i))
 			}
 
 			if speakerRef := door.SpeakerNameRef; speakerRef != 0 && speakerRef != 0xFFFFFFFF {
 				c.AddLabel(speakerRef, lb_area_door)
 				c.AddLabel(speakerRef, lb_area_speaker)
+				c.AddResource(speakerRef, areFilename)
 				c.AddContext(speakerRef, ContextArea, "Door's speaker name", fmt.Sprintf("%s → door %d", areFilename, i))
 			}
 		}
@@ -376,6 +459,7 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 			// Read only internal notes
 			if textRef := note.NoteRef; note.NoteRefIsInternal.Value && textRef != 0 && textRef != 0xFFFFFFFF {
 				c.AddLabel(textRef, lb_area_automap_note)
+				c.AddResource(textRef, areFilename)
 				c.AddContext(textRef, ContextArea, "Automap note", fmt.Sprintf("%s → automap note %d", areFilename, i))
 			}
 		}
@@ -385,6 +469,7 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 		for _, creatureTextRef := range restEncounters.CreatureTextRef {
 			if creatureTextRef != 0 && creatureTextRef != 0xFFFFFFFF {
 				c.AddLabel(creatureTextRef, lb_area_rest_encounter)
+				c.AddResource(creatureTextRef, areFilename)
 				c.AddContext(creatureTextRef, ContextArea, "Rest encounter message", areFilename)
 			}
 		}
@@ -396,21 +481,25 @@ func (c *TextCollection) LoadContextFromArea(areFilename string, are *p.Are) err
 func (c *TextCollection) LoadContextFromItem(itmFilename string, itm *p.Itm) error {
 	if unidentNameRef := itm.UnidentifiedNameRef; unidentNameRef != 0 && unidentNameRef != 0xFFFFFFFF {
 		c.AddLabel(unidentNameRef, lb_item)
+		c.AddResource(unidentNameRef, itmFilename)
 		c.AddContext(unidentNameRef, ContextItem, "General (unidentified) item name", itmFilename)
 	}
 
 	if identNameRef := itm.IdentifiedNameRef; identNameRef != 0 && identNameRef != 0xFFFFFFFF {
 		c.AddLabel(identNameRef, lb_item)
+		c.AddResource(identNameRef, itmFilename)
 		c.AddContext(identNameRef, ContextItem, "Identified item name", itmFilename)
 	}
 
 	if unidentDescRef := itm.UnidentifiedDescriptionRef; unidentDescRef != 0 && unidentDescRef != 0xFFFFFFFF {
 		c.AddLabel(unidentDescRef, lb_item)
+		c.AddResource(unidentDescRef, itmFilename)
 		c.AddContext(unidentDescRef, ContextItem, "General (unidentified) item description", itmFilename)
 	}
 
 	if identDescRef := itm.IdentifiedDescriptionRef; identDescRef != 0 && identDescRef != 0xFFFFFFFF {
 		c.AddLabel(identDescRef, lb_item)
+		c.AddResource(identDescRef, itmFilename)
 		c.AddContext(identDescRef, ContextItem, "Identified item description", itmFilename)
 	}
 
@@ -419,6 +508,7 @@ func (c *TextCollection) LoadContextFromItem(itmFilename string, itm *p.Itm) err
 			if strref, context := getStrrefFromEffect(uint32(effect.Opcode), effect.Parameter1); strref != 0 && strref != 0xFFFFFFFF {
 				c.AddLabel(strref, lb_item)
 				c.AddLabel(strref, lb_effect)
+				c.AddResource(strref, itmFilename)
 				c.AddContext(strref, ContextEffect, context, fmt.Sprintf("Item %s → global effect %d", itmFilename, i))
 			}
 		}
@@ -432,6 +522,7 @@ func (c *TextCollection) LoadContextFromItem(itmFilename string, itm *p.Itm) err
 						c.AddLabel(strref, lb_item)
 						c.AddLabel(strref, lb_ability)
 						c.AddLabel(strref, lb_effect)
+						c.AddResource(strref, itmFilename)
 						c.AddContext(strref, ContextEffect, context, fmt.Sprintf("Item %s → ability %d → effect %d", itmFilename, i, j))
 					}
 				}
@@ -445,6 +536,7 @@ func (c *TextCollection) LoadContextFromItem(itmFilename string, itm *p.Itm) err
 func (c *TextCollection) LoadContextFromProjectile(proFilename string, pro *p.Pro) error {
 	if messageRef := pro.MessageRef; messageRef != 0 && messageRef != 0xFFFFFFFF {
 		c.AddLabel(messageRef, lb_projectile)
+		c.AddResource(messageRef, proFilename)
 		c.AddContext(messageRef, ContextProjectile, "Projectile's message", proFilename)
 	}
 
@@ -454,21 +546,25 @@ func (c *TextCollection) LoadContextFromProjectile(proFilename string, pro *p.Pr
 func (c *TextCollection) LoadContextFromSpell(splFilename string, spl *p.Spl) error {
 	if unidentNameRef := spl.UnidentifiedNameRef; unidentNameRef != 0 && unidentNameRef != 0xFFFFFFFF {
 		c.AddLabel(unidentNameRef, lb_spell)
+		c.AddResource(unidentNameRef, splFilename)
 		c.AddContext(unidentNameRef, ContextSpell, "General (unidentified) spell name", splFilename)
 	}
 
 	if identNameRef := spl.IdentifiedNameRef; identNameRef != 0 && identNameRef != 9_999_999 && identNameRef != 0xFFFFFFFF {
 		c.AddLabel(identNameRef, lb_spell)
+		c.AddResource(identNameRef, splFilename)
 		c.AddContext(identNameRef, ContextSpell, "Identified spell name", splFilename)
 	}
 
 	if unidentDescRef := spl.UnidentifiedDescriptionRef; unidentDescRef != 0 && unidentDescRef != 0xFFFFFFFF {
 		c.AddLabel(unidentDescRef, lb_spell)
+		c.AddResource(unidentDescRef, splFilename)
 		c.AddContext(unidentDescRef, ContextSpell, "General (unidentified) spell description", splFilename)
 	}
 
 	if identDescRef := spl.IdentifiedDescriptionRef; identDescRef != 0 && identDescRef != 9_999_999 && identDescRef != 0xFFFFFFFF {
 		c.AddLabel(identDescRef, lb_spell)
+		c.AddResource(identDescRef, splFilename)
 		c.AddContext(identDescRef, ContextSpell, "Identified spell description", splFilename)
 	}
 
@@ -477,6 +573,7 @@ func (c *TextCollection) LoadContextFromSpell(splFilename string, spl *p.Spl) er
 			if strref, context := getStrrefFromEffect(uint32(effect.Opcode), effect.Parameter1); strref != 0 && strref != 0xFFFFFFFF {
 				c.AddLabel(strref, lb_spell)
 				c.AddLabel(strref, lb_effect)
+				c.AddResource(strref, splFilename)
 				c.AddContext(strref, ContextEffect, context, fmt.Sprintf("Spell %s → effect %d", splFilename, i))
 			}
 		}
@@ -490,6 +587,7 @@ func (c *TextCollection) LoadContextFromSpell(splFilename string, spl *p.Spl) er
 						c.AddLabel(strref, lb_spell)
 						c.AddLabel(strref, lb_ability)
 						c.AddLabel(strref, lb_effect)
+						c.AddResource(strref, splFilename)
 						c.AddContext(strref, ContextEffect, context, fmt.Sprintf("Spell %s → ability %d → effect %d", splFilename, i, j))
 					}
 				}
@@ -503,6 +601,7 @@ func (c *TextCollection) LoadContextFromSpell(splFilename string, spl *p.Spl) er
 func (c *TextCollection) LoadContextFromStore(stoFilename string, sto *p.Sto) error {
 	if nameRef := sto.NameRef; nameRef != 0 && nameRef != 0xFFFFFFFF {
 		c.AddLabel(nameRef, lb_store)
+		c.AddResource(nameRef, stoFilename)
 		c.AddContext(nameRef, ContextStore, "Store name", stoFilename)
 	}
 
@@ -511,6 +610,7 @@ func (c *TextCollection) LoadContextFromStore(stoFilename string, sto *p.Sto) er
 			if nameRef := drink.DrinkNameRef; nameRef != 0 && nameRef != 0xFFFFFFFF {
 				c.AddLabel(nameRef, lb_store)
 				c.AddLabel(nameRef, lb_store_drink)
+				c.AddResource(nameRef, stoFilename)
 				c.AddContext(nameRef, ContextStore, "Drink name (at merchant)", fmt.Sprintf("%s → drink %d", stoFilename, i))
 			}
 		}
@@ -531,10 +631,12 @@ func (c *TextCollection) LoadContextFromEffect(effFilename string, eff *p.Eff) e
 		for i := fromStrref; i < fromStrref+count; i++ {
 			c.AddLabel(i, lb_effect)
 			c.AddLabel(i, lb_cynicismQuote)
+			c.AddResource(i, effFilename)
 			c.AddContext(i, ContextEffect, "Show floating text", effFilename)
 		}
 	} else if strref, context := getStrrefFromEffect(opcode, param1); strref != 0 && strref != 0xFFFFFFFF {
 		c.AddLabel(strref, lb_effect)
+		c.AddResource(strref, effFilename)
 		c.AddContext(strref, ContextEffect, context, effFilename)
 	}
 
@@ -571,7 +673,7 @@ func (c *TextCollection) FillKnownContext() {
 }
 
 // Effect state names
-func (c *TextCollection) LoadContextFromEffText2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFromEffText2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		effName, ok := twoda.Get(rowKey, "EFFECT_NAME")
 		if !ok {
@@ -590,6 +692,7 @@ func (c *TextCollection) LoadContextFromEffText2DA(_ string, twoda *p.TwoDA) err
 
 		if strref := uint32(strrefSigned); strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_effect)
+			c.AddResource(strref, filename)
 			c.AddContext(strref, ContextEffect, "EFFTEXT.2DA (state name shown in the game message window)", effName)
 		}
 	}
@@ -597,7 +700,7 @@ func (c *TextCollection) LoadContextFromEffText2DA(_ string, twoda *p.TwoDA) err
 }
 
 // Engine's standard text references
-func (c *TextCollection) LoadContextFromEngineSt2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFromEngineSt2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		strrefStr, ok := twoda.Get(rowKey, "StrRef")
 		if !ok {
@@ -611,6 +714,7 @@ func (c *TextCollection) LoadContextFromEngineSt2DA(_ string, twoda *p.TwoDA) er
 
 		if strref := uint32(strrefSigned); strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_ui)
+			c.AddResource(strref, filename)
 			c.AddContext(strref, ContextUI, "ENGINEST.2DA (used in UI)", rowKey)
 		}
 	}
@@ -618,7 +722,7 @@ func (c *TextCollection) LoadContextFromEngineSt2DA(_ string, twoda *p.TwoDA) er
 }
 
 // Magic dispel primary type
-func (c *TextCollection) LoadContextFromMSchool2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFromMSchool2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		strrefStr, ok := twoda.Get(rowKey, "RES_REF")
 		if !ok {
@@ -632,6 +736,7 @@ func (c *TextCollection) LoadContextFromMSchool2DA(_ string, twoda *p.TwoDA) err
 
 		if strref := uint32(strref); strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_spell)
+			c.AddResource(strref, filename)
 			c.AddContext(strref, ContextSpell, "MSCHOOL.2DA (the text that appears when magic is dispelled based on its primary type)", rowKey)
 		}
 	}
@@ -639,7 +744,7 @@ func (c *TextCollection) LoadContextFromMSchool2DA(_ string, twoda *p.TwoDA) err
 }
 
 // Magic dispel secondary type
-func (c *TextCollection) LoadContextFromMSecType2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFromMSecType2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		strrefStr, ok := twoda.Get(rowKey, "RES_REF")
 		if !ok {
@@ -653,6 +758,7 @@ func (c *TextCollection) LoadContextFromMSecType2DA(_ string, twoda *p.TwoDA) er
 
 		if strref := uint32(strref); strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_spell)
+			c.AddResource(strref, filename)
 			c.AddContext(strref, ContextSpell, "MSECTYPE.2DA (the text that appears when magic is dispelled based on its secondary type)", rowKey)
 		}
 	}
@@ -672,7 +778,7 @@ func parseStrrefWithPrefix(s string) (uint32, bool, error) {
 }
 
 // Ranger's Tracking skill
-func (c *TextCollection) LoadContextFromTracking2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFromTracking2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		strrefStr, ok := twoda.Get(rowKey, "STRREF")
 		if !ok {
@@ -687,11 +793,14 @@ func (c *TextCollection) LoadContextFromTracking2DA(_ string, twoda *p.TwoDA) er
 		if strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_ranger_tracking)
 			if isStandalone {
+				c.AddResource(strref, filename)
 				c.AddContext(strref, ContextTracking2DA, "The text displayed by the rangers Tracking skill", fmt.Sprintf("Area %s", rowKey))
 			} else {
 				c.AddLabel(67807, lb_ranger_tracking)
+				c.AddResource(67807, filename)
 				c.AddContext(67807, ContextTracking2DA, "The default text displayed by the rangers Tracking skill. (See the tag). Possible variables to embed", strrefStr)
 
+				c.AddResource(strref, filename)
 				c.AddContext(strref, ContextTracking2DA, "Used to embed into #67807 when the area is", rowKey)
 			}
 		}
@@ -707,6 +816,7 @@ func (c *TextCollection) LoadContextFrom25StWeap2DA(filename string, twoda *p.Tw
 				nameref := uint32(nameref)
 				if nameref != 0 && nameref != 0xFFFFFFFF {
 					c.AddLabel(nameref, lb_item)
+					c.AddResource(nameref, filename)
 					c.AddContext(nameref, ContextItem, fmt.Sprintf("%s (starting equipment name) for slots", filename), rowKey)
 				}
 			}
@@ -717,6 +827,7 @@ func (c *TextCollection) LoadContextFrom25StWeap2DA(filename string, twoda *p.Tw
 				descref := uint32(descref)
 				if descref != 0 && descref != 0xFFFFFFFF {
 					c.AddLabel(descref, lb_item)
+					c.AddResource(descref, filename)
 					c.AddContext(descref, ContextItem, fmt.Sprintf("%s (starting equipment description) for slots", filename), rowKey)
 				}
 			}
@@ -727,7 +838,7 @@ func (c *TextCollection) LoadContextFrom25StWeap2DA(filename string, twoda *p.Tw
 }
 
 // Credits
-func (c *TextCollection) LoadContextFrom25ECred2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFrom25ECred2DA(filename string, twoda *p.TwoDA) error {
 	if row, ok := twoda.Row("DEFAULT"); ok {
 		for i, strrefStr := range row {
 			strref, err := strconv.ParseUint(strrefStr, 10, 32)
@@ -737,6 +848,7 @@ func (c *TextCollection) LoadContextFrom25ECred2DA(_ string, twoda *p.TwoDA) err
 
 			if strref := uint32(strref); strref != 0 && strref != 0xFFFFFFFF {
 				c.AddLabel(strref, lb_ui)
+				c.AddResource(strref, filename)
 				c.AddContext(strref, ContextUI, "25ECRED.2DA (credits text)", fmt.Sprintf("%s.BMP", twoda.GetByIndexOrDefault("BMP", i)))
 			}
 		}
@@ -747,7 +859,7 @@ func (c *TextCollection) LoadContextFrom25ECred2DA(_ string, twoda *p.TwoDA) err
 }
 
 // 7 eyes
-func (c *TextCollection) LoadContextFrom7Eyes2DA(_ string, twoda *p.TwoDA) error {
+func (c *TextCollection) LoadContextFrom7Eyes2DA(filename string, twoda *p.TwoDA) error {
 	for _, rowKey := range twoda.RowKeys {
 		strrefStr, ok := twoda.Get(rowKey, "STRREF")
 		if !ok {
@@ -761,6 +873,7 @@ func (c *TextCollection) LoadContextFrom7Eyes2DA(_ string, twoda *p.TwoDA) error
 
 		if strref := uint32(strref); strref != 0 && strref != 0xFFFFFFFF {
 			c.AddLabel(strref, lb_spell)
+			c.AddResource(strref, filename)
 			c.AddContext(strref, ContextSpell, "7EYES.2DA (Spell Effect: Seven Eyes). The text is shown when an effect is blocked by an active spellstate", rowKey)
 		}
 	}
@@ -768,7 +881,7 @@ func (c *TextCollection) LoadContextFrom7Eyes2DA(_ string, twoda *p.TwoDA) error
 }
 
 // Subtitles
-func (c *TextCollection) LoadContextFromCharSnd2DA(_ string, twoda *p.TwoDA, ids *p.Ids) error {
+func (c *TextCollection) LoadContextFromCharSnd2DA(filename string, twoda *p.TwoDA, ids *p.Ids) error {
 	columns := twoda.Columns
 	for _, rowKey := range twoda.RowKeys {
 		var slotName string
@@ -799,6 +912,7 @@ func (c *TextCollection) LoadContextFromCharSnd2DA(_ string, twoda *p.TwoDA, ids
 
 			if strref := uint32(strref); strref != 0 && strref != 0xFFFFFFFF {
 				c.AddLabel(strref, lb_sound_subtitles)
+				c.AddResource(strref, filename)
 				c.AddContext(strref, ContextSubtitles, "CHARSND.2DA (subtitles; M* – male, F* – female)", fmt.Sprintf("%s → %s", columns[col], slotName))
 			}
 		}
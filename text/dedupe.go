@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/samber/lo"
+	"github.com/sbtlocalization/sbt-infinity/utils"
+)
+
+// DuplicateGroup is a set of strrefs sharing the same source text.
+type DuplicateGroup struct {
+	Source       string
+	Ids          []uint32
+	Sources      map[uint32]string // differ only in normalized groups
+	Translations map[uint32]string
+}
+
+// Collapses whitespace and drops punctuation, so "Yes." and "Yes!" become equal.
+func NormalizeSource(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case unicode.IsPunct(r):
+			continue
+		default:
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Groups the translated strrefs by their source text. Strrefs without a
+// translation, or with the source text in its place, are left out. Only
+// groups with at least two translated strrefs are returned, ordered by the
+// first strref.
+func FindDuplicates(sources, translations map[uint32]string, normalize bool) []*DuplicateGroup {
+	groups := make(map[string]*DuplicateGroup)
+
+	for _, id := range slices.Sorted(maps.Keys(translations)) {
+		source, ok := sources[id]
+		if !ok || strings.TrimSpace(source) == "" {
+			continue
+		}
+		translation := strings.TrimSpace(translations[id])
+		if translation == "" || translation == strings.TrimSpace(source) {
+			continue
+		}
+
+		key := source
+		if normalize {
+			key = NormalizeSource(source)
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &DuplicateGroup{Source: source, Sources: make(map[uint32]string), Translations: make(map[uint32]string)}
+			groups[key] = group
+		}
+		group.Ids = append(group.Ids, id)
+		group.Sources[id] = source
+		group.Translations[id] = translations[id]
+	}
+
+	var result []*DuplicateGroup
+	for _, group := range groups {
+		if len(group.Ids) > 1 {
+			result = append(result, group)
+		}
+	}
+	slices.SortFunc(result, func(a, b *DuplicateGroup) int {
+		return cmp.Compare(a.Ids[0], b.Ids[0])
+	})
+	return result
+}
+
+// Splits a normalized group into groups of strrefs with exactly the same
+// source text, ordered by the first strref. A translation is propagated
+// only within such a group, so "Yes." and "Yes?" keep their punctuation.
+func (g *DuplicateGroup) SplitBySource() []*DuplicateGroup {
+	var result []*DuplicateGroup
+	bySource := make(map[string]*DuplicateGroup)
+	for _, id := range g.Ids {
+		source, ok := g.Sources[id]
+		if !ok {
+			source = g.Source
+		}
+		group, ok := bySource[source]
+		if !ok {
+			group = &DuplicateGroup{Source: source, Sources: make(map[uint32]string), Translations: make(map[uint32]string)}
+			bySource[source] = group
+			result = append(result, group)
+		}
+		group.Ids = append(group.Ids, id)
+		group.Sources[id] = source
+		group.Translations[id] = g.Translations[id]
+	}
+	return result
+}
+
+// Splits every translation of the group into male and female variants.
+// A translation without a female variant uses the male text for both.
+func (g *DuplicateGroup) variants(separator string) (map[uint32]string, map[uint32]string) {
+	male := make(map[uint32]string, len(g.Ids))
+	female := make(map[uint32]string, len(g.Ids))
+	for _, id := range g.Ids {
+		m, f, hasSplit := utils.SplitMaleFemaleText(g.Translations[id], separator)
+		if !hasSplit {
+			f = m
+		}
+		male[id], female[id] = m, f
+	}
+	return male, female
+}
+
+// Reports whether male or female variants of the translations differ.
+func (g *DuplicateGroup) Disagrees(separator string) bool {
+	male, female := g.variants(separator)
+	return len(lo.Uniq(lo.Values(male))) > 1 || len(lo.Uniq(lo.Values(female))) > 1
+}
+
+// Returns the translation to propagate to the whole group. If chosen is
+// a strref of the group, its translation is used; otherwise the most
+// common variant is picked separately for male and female texts.
+// Returns false if there is no single majority.
+func (g *DuplicateGroup) Resolve(separator string, chosen map[uint32]struct{}) (string, bool) {
+	for _, id := range g.Ids {
+		if _, ok := chosen[id]; ok {
+			return g.Translations[id], true
+		}
+	}
+
+	male, female := g.variants(separator)
+	m, okMale := majority(g.Ids, male)
+	f, okFemale := majority(g.Ids, female)
+	if !okMale || !okFemale {
+		return "", false
+	}
+
+	if m == f {
+		return m, true
+	}
+	return m + separator + f, true
+}
+
+func majority(ids []uint32, values map[uint32]string) (string, bool) {
+	counts := make(map[string]int)
+	for _, id := range ids {
+		counts[values[id]]++
+	}
+
+	best, bestCount, tie := "", 0, false
+	for value, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tie = value, count, false
+		case count == bestCount:
+			tie = true
+		}
+	}
+	return best, !tie
+}
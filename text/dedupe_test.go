@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	sources := map[uint32]string{1: "Yes.", 2: "Yes.", 3: "Yes!", 4: "No.", 5: "Yes."}
+	translations := map[uint32]string{1: "Так.", 2: "Так!", 3: "Так.", 4: "Ні.", 5: "Так."}
+
+	groups := FindDuplicates(sources, translations, false)
+	if len(groups) != 1 || !slices.Equal(groups[0].Ids, []uint32{1, 2, 5}) {
+		t.Fatalf("FindDuplicates() = %v, want one group of 1, 2, 5", groups)
+	}
+
+	groups = FindDuplicates(sources, translations, true)
+	if len(groups) != 1 || !slices.Equal(groups[0].Ids, []uint32{1, 2, 3, 5}) {
+		t.Fatalf("FindDuplicates(normalize) = %v, want one group of 1, 2, 3, 5", groups)
+	}
+
+	split := groups[0].SplitBySource()
+	if len(split) != 2 || !slices.Equal(split[0].Ids, []uint32{1, 2, 5}) || split[1].Source != "Yes!" || !slices.Equal(split[1].Ids, []uint32{3}) {
+		t.Errorf("SplitBySource() = %v, want groups of 1, 2, 5 and 3", split)
+	}
+}
+
+func TestFindDuplicatesUntranslated(t *testing.T) {
+	const sep = " // "
+	sources := map[uint32]string{1: "Yes.", 2: "Yes.", 3: "Yes.", 4: "Yes?", 5: "Yes?"}
+	translations := map[uint32]string{1: "Yes.", 2: "Так.", 3: "", 4: "Yes?", 5: "Так?"}
+
+	if groups := FindDuplicates(sources, translations, false); len(groups) != 0 {
+		t.Errorf("FindDuplicates() = %v, want no groups", groups)
+	}
+
+	// Only 2 and 5 are translated; they differ, but each keeps its own text.
+	groups := FindDuplicates(sources, translations, true)
+	if len(groups) != 1 || !slices.Equal(groups[0].Ids, []uint32{2, 5}) || !groups[0].Disagrees(sep) {
+		t.Fatalf("FindDuplicates(normalize) = %v, want one disagreeing group of 2, 5", groups)
+	}
+	for _, g := range groups[0].SplitBySource() {
+		if got, ok := g.Resolve(sep, nil); !ok || got != translations[g.Ids[0]] {
+			t.Errorf("Resolve() of %q = %q, %v, want %q", g.Source, got, ok, translations[g.Ids[0]])
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const sep = " // "
+
+	tests := []struct {
+		name         string
+		translations map[uint32]string
+		chosen       map[uint32]struct{}
+		want         string
+		wantOk       bool
+	}{
+		{"majority", map[uint32]string{1: "Так.", 2: "Так!", 3: "Так."}, nil, "Так.", true},
+		{"tie", map[uint32]string{1: "Так.", 2: "Так!"}, nil, "", false},
+		{"chosen", map[uint32]string{1: "Так.", 2: "Так!"}, map[uint32]struct{}{2: {}}, "Так!", true},
+		{"genders", map[uint32]string{1: "Готовий.", 2: "Готовий. // Готова.", 3: "Готовий. // Готова."}, nil, "Готовий. // Готова.", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &DuplicateGroup{Translations: tt.translations}
+			for id := range tt.translations {
+				g.Ids = append(g.Ids, id)
+			}
+			slices.Sort(g.Ids)
+
+			got, ok := g.Resolve(sep, tt.chosen)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Resolve() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
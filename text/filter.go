@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/fs"
+)
+
+var contextTypeNames = map[ContextType]string{
+	ContextArea:          "AREAS",
+	ContextCreature:      "CREATURES",
+	ContextCreatureSound: "USED BY CREATURE",
+	ContextDialog:        "DIALOGS",
+	ContextEffect:        "EFFECTS",
+	ContextItem:          "ITEMS",
+	ContextProjectile:    "PROJECTILES",
+	ContextSubtitles:     "SUBTITLES",
+	ContextSpell:         "SPELLS",
+	ContextStore:         "STORES",
+	ContextTlkSound:      "SOUND",
+	ContextTracking2DA:   "TRACKING.2DA",
+	ContextUI:            "UI",
+	ContextWorldMap:      "WORLD MAPS",
+}
+
+// Returns the name of the context type as it appears in the exported XLSX.
+func (t ContextType) String() string {
+	if name, ok := contextTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// Parses a context type name (case insensitive), e.g. "ITEMS" or "world maps".
+func ContextTypeFromString(name string) (ContextType, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for t, n := range contextTypeNames {
+		if n == name {
+			return t, nil
+		}
+	}
+	names := slices.Sorted(maps.Values(contextTypeNames))
+	return 0, fmt.Errorf("unknown context type %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// EntryFilter selects entries of a TextCollection. Every non-empty criterion
+// must match; values inside a single criterion are alternatives.
+type EntryFilter struct {
+	Ids       []uint32
+	Labels    []string
+	Contexts  []ContextType
+	Resources []*fs.CompiledFilter
+}
+
+func (f *EntryFilter) Match(entry *TextEntry) bool {
+	if len(f.Ids) > 0 {
+		if _, found := slices.BinarySearch(f.Ids, entry.Id); !found {
+			return false
+		}
+	}
+
+	if len(f.Labels) > 0 && !slices.ContainsFunc(f.Labels, func(label string) bool {
+		_, ok := entry.Labels[label]
+		return ok
+	}) {
+		return false
+	}
+
+	if len(f.Contexts) > 0 && !slices.ContainsFunc(f.Contexts, func(t ContextType) bool {
+		return len(entry.Context[t]) > 0
+	}) {
+		return false
+	}
+
+	if len(f.Resources) > 0 && !slices.ContainsFunc(f.Resources, func(filter *fs.CompiledFilter) bool {
+		for resource := range entry.Resources {
+			if filter.Match(resource) {
+				return true
+			}
+		}
+		return false
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// Returns sorted IDs of entries matching the filter. A nil filter matches everything.
+func (c *TextCollection) Filter(f *EntryFilter) []uint32 {
+	ids := make([]uint32, 0, len(c.Entries))
+	for id, entry := range c.Entries {
+		if f == nil || f.Match(entry) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+type SplitMode int
+
+const (
+	SplitNone SplitMode = iota
+	SplitByDialog
+	SplitByLabel
+	SplitBySize
+)
+
+type SplitOptions struct {
+	Mode SplitMode
+	Size int
+	// Preferred labels for SplitByLabel, checked in order before the others.
+	Labels []string
+}
+
+// Parses the value of --split-by: "dialog", "label" or "size=N".
+func ParseSplitOptions(value string) (SplitOptions, error) {
+	switch {
+	case value == "":
+		return SplitOptions{Mode: SplitNone}, nil
+	case value == "dialog":
+		return SplitOptions{Mode: SplitByDialog}, nil
+	case value == "label":
+		return SplitOptions{Mode: SplitByLabel}, nil
+	case strings.HasPrefix(value, "size="):
+		size, err := strconv.Atoi(strings.TrimPrefix(value, "size="))
+		if err != nil || size <= 0 {
+			return SplitOptions{}, fmt.Errorf("invalid chunk size in %q", value)
+		}
+		return SplitOptions{Mode: SplitBySize, Size: size}, nil
+	default:
+		return SplitOptions{}, fmt.Errorf("unknown split mode %q (valid: dialog, label, size=N)", value)
+	}
+}
+
+type Chunk struct {
+	Name string
+	Ids  []uint32
+}
+
+// Splits sorted IDs into chunks. Every entry goes to exactly one chunk, so
+// translators working on different chunks never touch the same strref.
+func (c *TextCollection) Split(ids []uint32, opts SplitOptions) []Chunk {
+	switch opts.Mode {
+	case SplitBySize:
+		var chunks []Chunk
+		width := len(strconv.Itoa((len(ids) - 1) / opts.Size))
+		for i := 0; i < len(ids); i += opts.Size {
+			end := min(i+opts.Size, len(ids))
+			chunks = append(chunks, Chunk{
+				Name: fmt.Sprintf("%0*d", width, i/opts.Size),
+				Ids:  ids[i:end],
+			})
+		}
+		return chunks
+	case SplitByDialog:
+		return groupIds(ids, func(id uint32) string {
+			return dialogChunkName(c.Entries[id])
+		})
+	case SplitByLabel:
+		return groupIds(ids, func(id uint32) string {
+			return labelChunkName(c.Entries[id], opts.Labels)
+		})
+	default:
+		return []Chunk{{Name: "", Ids: ids}}
+	}
+}
+
+func dialogChunkName(entry *TextEntry) string {
+	var dialogs []string
+	for resource := range entry.Resources {
+		if name, ok := strings.CutSuffix(resource, ".DLG"); ok {
+			dialogs = append(dialogs, name)
+		}
+	}
+	if len(dialogs) == 0 {
+		return "no dialog"
+	}
+	return slices.Min(dialogs)
+}
+
+func labelChunkName(entry *TextEntry, preferred []string) string {
+	for _, label := range preferred {
+		if _, ok := entry.Labels[label]; ok {
+			return label
+		}
+	}
+	if len(entry.Labels) == 0 {
+		return "no label"
+	}
+	return slices.Min(slices.Collect(maps.Keys(entry.Labels)))
+}
+
+func groupIds(ids []uint32, key func(uint32) string) []Chunk {
+	groups := make(map[string][]uint32)
+	for _, id := range ids {
+		k := key(id)
+		groups[k] = append(groups[k], id)
+	}
+
+	chunks := make([]Chunk, 0, len(groups))
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		chunks = append(chunks, Chunk{Name: name, Ids: groups[name]})
+	}
+	return chunks
+}
+
+// Turns a chunk name into something safe to use as a part of a file name.
+func ChunkFileName(base, chunkName string) string {
+	if chunkName == "" {
+		return base
+	}
+
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, chunkName)
+
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-" + safe + ext
+}
+
+// Returns sorted IDs of entries used as names of creatures, items, spells,
+// places and stores: the terms of the glossary.
+func (c *TextCollection) NameIds() []uint32 {
+	var ids []uint32
+	for id, entry := range c.Entries {
+		for _, def := range nameContexts {
+			if slices.ContainsFunc(def.keys, func(key string) bool {
+				_, ok := entry.Context[def.context][key]
+				return ok
+			}) {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/fs"
+)
+
+func newTestCollection() *TextCollection {
+	c := &TextCollection{Entries: make(map[uint32]*TextEntry)}
+	for id := uint32(1); id <= 6; id++ {
+		c.Entries[id] = &TextEntry{
+			Id:        id,
+			Labels:    make(map[string]struct{}),
+			Resources: make(map[string]struct{}),
+			Context:   make(map[ContextType]map[string][]string),
+		}
+	}
+
+	c.AddLabel(1, lb_store)
+	c.AddLabel(1, lb_store_drink)
+	c.AddContext(1, ContextStore, "Drink name (at merchant)", "INN0202.STO → drink 0")
+	c.AddResource(1, "inn0202.sto")
+
+	c.AddLabel(2, lb_item)
+	c.AddContext(2, ContextItem, "Identified item name", "SW1H01.ITM")
+	c.AddResource(2, "SW1H01.ITM")
+
+	c.AddLabel(3, lb_dialog)
+	c.AddContext(3, ContextDialog, "AR0202[0]", "")
+	c.AddResource(3, "AR0202.DLG")
+
+	c.AddLabel(4, lb_dialog)
+	c.AddContext(4, ContextDialog, "BJAHEIR[2]", "")
+	c.AddResource(4, "BJAHEIR.DLG")
+	c.AddResource(4, "AR0202.DLG")
+
+	c.AddLabel(5, lb_area)
+	c.AddResource(5, "AR0202.ARE")
+
+	return c
+}
+
+func TestFilter(t *testing.T) {
+	c := newTestCollection()
+
+	tests := []struct {
+		name   string
+		filter *EntryFilter
+		want   []uint32
+	}{
+		{"nil filter", nil, []uint32{1, 2, 3, 4, 5, 6}},
+		{"ids", &EntryFilter{Ids: []uint32{2, 3, 7}}, []uint32{2, 3}},
+		{"label", &EntryFilter{Labels: []string{lb_store_drink}}, []uint32{1}},
+		{"labels are alternatives", &EntryFilter{Labels: []string{lb_store, lb_item}}, []uint32{1, 2}},
+		{"context", &EntryFilter{Contexts: []ContextType{ContextDialog}}, []uint32{3, 4}},
+		{"resource", &EntryFilter{Resources: []*fs.CompiledFilter{fs.CompileFilter("AR02*", false, false, true)}}, []uint32{3, 4, 5}},
+		{"combined", &EntryFilter{
+			Labels:    []string{lb_dialog},
+			Resources: []*fs.CompiledFilter{fs.CompileFilter("bjaheir", false, false, true)},
+		}, []uint32{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Filter(tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	c := newTestCollection()
+	ids := c.Filter(nil)
+
+	bySize := c.Split(ids, SplitOptions{Mode: SplitBySize, Size: 4})
+	if len(bySize) != 2 || len(bySize[0].Ids) != 4 || len(bySize[1].Ids) != 2 {
+		t.Errorf("Split(size=4) = %v, want chunks of 4 and 2 entries", bySize)
+	}
+
+	byDialog := c.Split(ids, SplitOptions{Mode: SplitByDialog})
+	want := []Chunk{
+		{Name: "AR0202", Ids: []uint32{3, 4}},
+		{Name: "no dialog", Ids: []uint32{1, 2, 5, 6}},
+	}
+	if len(byDialog) != len(want) {
+		t.Fatalf("Split(dialog) = %v, want %v", byDialog, want)
+	}
+	for i := range want {
+		if byDialog[i].Name != want[i].Name || !slices.Equal(byDialog[i].Ids, want[i].Ids) {
+			t.Errorf("Split(dialog)[%d] = %v, want %v", i, byDialog[i], want[i])
+		}
+	}
+
+	byLabel := c.Split(ids, SplitOptions{Mode: SplitByLabel, Labels: []string{lb_store_drink}})
+	if byLabel[0].Name != "area" || byLabel[len(byLabel)-1].Name != lb_store_drink {
+		t.Errorf("Split(label) = %v, want preferred label to be used for entry 1", byLabel)
+	}
+}
+
+func TestChunkFileName(t *testing.T) {
+	tests := []struct{ base, chunk, want string }{
+		{"out/dialog.xlsx", "", "out/dialog.xlsx"},
+		{"out/dialog.xlsx", "store drink", "out/dialog-store_drink.xlsx"},
+		{"dialog.xlsx", "dialog 3 @ BJAHEIR", "dialog-dialog_3___BJAHEIR.xlsx"},
+	}
+	for _, tt := range tests {
+		if got := ChunkFileName(tt.base, tt.chunk); got != tt.want {
+			t.Errorf("ChunkFileName(%q, %q) = %q, want %q", tt.base, tt.chunk, got, tt.want)
+		}
+	}
+}
+
+func TestNameIds(t *testing.T) {
+	c := newTestCollection()
+	if got := c.NameIds(); !slices.Equal(got, []uint32{2}) {
+		t.Errorf("NameIds() = %v, want [2]", got)
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// TextFit is a text wrapped to the width of a UI label.
+type TextFit struct {
+	Lines []string
+	// Size of the wrapped text in pixels.
+	Width  int
+	Height int
+	// Size of the label box in pixels.
+	BoxWidth  int
+	BoxHeight int
+}
+
+func (f *TextFit) Overflows() bool {
+	return f.Width > f.BoxWidth || f.Height > f.BoxHeight
+}
+
+// Wraps the text at spaces to the width of the box, as the game does.
+// Words longer than the box stay on their own line and overflow it.
+func FitText(face FontFace, s string, width, height int) *TextFit {
+	fit := &TextFit{BoxWidth: width, BoxHeight: height}
+
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line == "" {
+				line = word
+			} else if face.MeasureString(line+" "+word) <= width {
+				line += " " + word
+			} else {
+				fit.Lines = append(fit.Lines, line)
+				line = word
+			}
+		}
+		fit.Lines = append(fit.Lines, line)
+	}
+
+	for _, line := range fit.Lines {
+		fit.Width = max(fit.Width, face.MeasureString(line))
+	}
+	fit.Height = len(fit.Lines) * face.LineHeight()
+
+	return fit
+}
+
+var (
+	fitBackground = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	fitBoxColor   = color.RGBA{0x40, 0xa0, 0x40, 0xff}
+	fitOverColor  = color.RGBA{0xe0, 0x30, 0x30, 0xff}
+	fitTextColor  = color.RGBA{0xf0, 0xe0, 0xb0, 0xff}
+)
+
+// Renders the wrapped text over the outline of the label box. The box is
+// red if the text overflows it.
+func RenderFit(face FontFace, fit *TextFit) *image.RGBA {
+	const margin = 4
+
+	bounds := image.Rect(0, 0, max(fit.BoxWidth, fit.Width)+2*margin, max(fit.BoxHeight, fit.Height)+2*margin)
+	img := image.NewRGBA(bounds)
+	draw.Draw(img, bounds, image.NewUniform(fitBackground), image.Point{}, draw.Src)
+
+	boxColor := fitBoxColor
+	if fit.Overflows() {
+		boxColor = fitOverColor
+	}
+	x0, y0 := margin-1, margin-1
+	x1, y1 := margin+fit.BoxWidth, margin+fit.BoxHeight
+	for x := x0; x <= x1; x++ {
+		img.Set(x, y0, boxColor)
+		img.Set(x, y1, boxColor)
+	}
+	for y := y0; y <= y1; y++ {
+		img.Set(x0, y, boxColor)
+		img.Set(x1, y, boxColor)
+	}
+
+	for i, line := range fit.Lines {
+		face.DrawString(img, margin, margin+i*face.LineHeight(), line, fitTextColor)
+	}
+
+	return img
+}
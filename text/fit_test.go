@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestFitText(t *testing.T) {
+	// Glyphs of 'A' and 'Ї' are 5x8 pixels, spaces are 3 pixels wide.
+	face, err := NewBamFace("NORMAL.BAM", newTestBamFont(), charmap.Windows1251)
+	if err != nil {
+		t.Fatalf("NewBamFace() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		text          string
+		width, height int
+		wantLines     []string
+		wantOverflows bool
+	}{
+		{"fits", "AA A", 20, 8, []string{"AA A"}, false},
+		{"wraps", "AA AA", 15, 16, []string{"AA", "AA"}, false},
+		{"spaces", "A A A", 20, 16, []string{"A A", "A"}, false},
+		{"too high", "AA AA\nЇ", 15, 16, []string{"AA", "AA", "Ї"}, true},
+		{"too wide", "AAAA", 15, 16, []string{"AAAA"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit := FitText(face, tt.text, tt.width, tt.height)
+			if !slices.Equal(fit.Lines, tt.wantLines) || fit.Overflows() != tt.wantOverflows {
+				t.Errorf("FitText() = %q (overflows %v), want %q (overflows %v)", fit.Lines, fit.Overflows(), tt.wantLines, tt.wantOverflows)
+			}
+		})
+	}
+}
+
+func TestRenderFitTtf(t *testing.T) {
+	face, err := NewTtfFace("goregular", goregular.TTF, 14)
+	if err != nil {
+		t.Fatalf("NewTtfFace() error = %v", err)
+	}
+
+	fit := FitText(face, "Save the game", 40, 20)
+	if !fit.Overflows() || len(fit.Lines) < 2 {
+		t.Errorf("FitText() = %q, want the text wrapped and overflowing", fit.Lines)
+	}
+
+	img := RenderFit(face, fit)
+	if img.Bounds().Dx() < 40 || img.Bounds().Dy() < fit.Height {
+		t.Errorf("RenderFit() bounds = %v, want at least the box and the text", img.Bounds())
+	}
+}
@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/encoding"
+)
+
+// Font reports which characters it is able to render.
+type Font interface {
+	Name() string
+	HasGlyph(r rune) bool
+}
+
+// FontFace measures and draws text with the metrics of a game font.
+type FontFace interface {
+	Font
+	MeasureString(s string) int
+	LineHeight() int
+	// Draws the string with the top of the line at y.
+	DrawString(dst draw.Image, x, y int, s string, c color.Color)
+}
+
+type ttfFont struct {
+	name string
+	font *sfnt.Font
+	buf  sfnt.Buffer
+	face font.Face
+}
+
+// Parses a TrueType font, as used by the Enhanced Editions.
+func ParseTtfFont(name string, data []byte) (Font, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse TTF font %s: %w", name, err)
+	}
+	return &ttfFont{name: name, font: f}, nil
+}
+
+// Parses a TrueType font and prepares it for rendering at the pixel size.
+func NewTtfFace(name string, data []byte, size float64) (FontFace, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse TTF font %s: %w", name, err)
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create face for TTF font %s: %w", name, err)
+	}
+	return &ttfFont{name: name, font: f, face: face}, nil
+}
+
+func (f *ttfFont) Name() string {
+	return f.name
+}
+
+func (f *ttfFont) HasGlyph(r rune) bool {
+	idx, err := f.font.GlyphIndex(&f.buf, r)
+	return err == nil && idx != 0
+}
+
+func (f *ttfFont) MeasureString(s string) int {
+	return font.MeasureString(f.face, s).Ceil()
+}
+
+func (f *ttfFont) LineHeight() int {
+	return f.face.Metrics().Height.Ceil()
+}
+
+func (f *ttfFont) DrawString(dst draw.Image, x, y int, s string, c color.Color) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: f.face,
+		Dot:  fixed.P(x, y+f.face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(s)
+}
+
+// Returns the family name stored in the TTF font, e.g. "Noto Serif".
+func TtfFamilyName(data []byte) (string, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return "", err
+	}
+	return f.Name(nil, sfnt.NameIDFamily)
+}
+
+type bamFrame struct {
+	width, height int
+	offset        uint32
+}
+
+type bamFont struct {
+	name            string
+	data            []byte
+	compressedColor byte
+	glyphs          map[byte]bamFrame
+	lineHeight      int
+	spaceWidth      int
+	encoder         *encoding.Encoder
+}
+
+// Parses a BAM V1 font of the classic games. Cycle N of such a BAM holds
+// the glyph of the character with code N+1 in the game code page.
+func ParseBamFont(name string, data []byte, codePage encoding.Encoding) (Font, error) {
+	return parseBamFont(name, data, codePage)
+}
+
+// Parses a BAM V1 font and prepares it for rendering.
+func NewBamFace(name string, data []byte, codePage encoding.Encoding) (FontFace, error) {
+	return parseBamFont(name, data, codePage)
+}
+
+func parseBamFont(name string, data []byte, codePage encoding.Encoding) (*bamFont, error) {
+	data, err := decompressBam(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read BAM font %s: %w", name, err)
+	}
+
+	if len(data) < 24 || string(data[:8]) != "BAM V1  " {
+		return nil, fmt.Errorf("BAM font %s is not a BAM V1 file", name)
+	}
+
+	frameCount := int(binary.LittleEndian.Uint16(data[8:]))
+	cycleCount := int(data[10])
+	framesOffset := int(binary.LittleEndian.Uint32(data[12:]))
+	lookupOffset := int(binary.LittleEndian.Uint32(data[20:]))
+	cyclesOffset := framesOffset + frameCount*12
+
+	if cyclesOffset+cycleCount*4 > len(data) {
+		return nil, fmt.Errorf("BAM font %s is truncated", name)
+	}
+
+	f := &bamFont{
+		name:            name,
+		data:            data,
+		compressedColor: data[11],
+		glyphs:          make(map[byte]bamFrame),
+		encoder:         codePage.NewEncoder(),
+	}
+
+	for c := range min(cycleCount, 255) {
+		cycle := data[cyclesOffset+c*4:]
+		count := int(binary.LittleEndian.Uint16(cycle))
+		first := int(binary.LittleEndian.Uint16(cycle[2:]))
+		if count == 0 {
+			continue
+		}
+
+		lookup := lookupOffset + first*2
+		if lookup+2 > len(data) {
+			continue
+		}
+		frame := int(binary.LittleEndian.Uint16(data[lookup:]))
+		if frame >= frameCount {
+			continue
+		}
+
+		entry := data[framesOffset+frame*12:]
+		glyph := bamFrame{
+			width:  int(binary.LittleEndian.Uint16(entry)),
+			height: int(binary.LittleEndian.Uint16(entry[2:])),
+			offset: binary.LittleEndian.Uint32(entry[8:]),
+		}
+		if c+1 == ' ' {
+			// The space is an empty frame of its width, not a glyph.
+			f.spaceWidth = glyph.width
+		} else if glyph.width > 1 || glyph.height > 1 {
+			f.glyphs[byte(c+1)] = glyph
+			f.lineHeight = max(f.lineHeight, glyph.height)
+		}
+	}
+	if f.spaceWidth == 0 {
+		f.spaceWidth = f.lineHeight / 3
+	}
+
+	return f, nil
+}
+
+func decompressBam(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:8]) != "BAMCV1  " {
+		return data, nil
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(data[12:]))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func (f *bamFont) Name() string {
+	return f.name
+}
+
+func (f *bamFont) glyph(r rune) (bamFrame, bool) {
+	encoded, err := f.encoder.String(string(r))
+	if err != nil || len(encoded) != 1 {
+		return bamFrame{}, false
+	}
+	glyph, ok := f.glyphs[encoded[0]]
+	return glyph, ok
+}
+
+func (f *bamFont) HasGlyph(r rune) bool {
+	_, ok := f.glyph(r)
+	return ok
+}
+
+func (f *bamFont) MeasureString(s string) int {
+	width := 0
+	for _, r := range s {
+		if r == ' ' {
+			width += f.spaceWidth
+		} else if glyph, ok := f.glyph(r); ok {
+			width += glyph.width
+		}
+	}
+	return width
+}
+
+func (f *bamFont) LineHeight() int {
+	return f.lineHeight
+}
+
+func (f *bamFont) DrawString(dst draw.Image, x, y int, s string, c color.Color) {
+	for _, r := range s {
+		if r == ' ' {
+			x += f.spaceWidth
+			continue
+		}
+		glyph, ok := f.glyph(r)
+		if !ok {
+			continue
+		}
+		top := y + f.lineHeight - glyph.height
+		for i, index := range f.framePixels(glyph) {
+			// Palette index 0 is transparent.
+			if index != 0 {
+				dst.Set(x+i%glyph.width, top+i/glyph.width, c)
+			}
+		}
+		x += glyph.width
+	}
+}
+
+// Returns palette indices of the frame, unpacking the RLE of the compressed color.
+func (f *bamFont) framePixels(glyph bamFrame) []byte {
+	size := glyph.width * glyph.height
+	offset := int(glyph.offset & 0x7FFFFFFF)
+	if offset >= len(f.data) {
+		return nil
+	}
+
+	if glyph.offset&0x80000000 != 0 {
+		return f.data[offset:min(offset+size, len(f.data))]
+	}
+
+	pixels := make([]byte, 0, size)
+	for i := offset; i < len(f.data) && len(pixels) < size; i++ {
+		pixel := f.data[i]
+		if pixel == f.compressedColor && i+1 < len(f.data) {
+			i++
+			for range int(f.data[i]) + 1 {
+				pixels = append(pixels, pixel)
+			}
+		} else {
+			pixels = append(pixels, pixel)
+		}
+	}
+	return pixels[:min(len(pixels), size)]
+}
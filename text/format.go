@@ -18,6 +18,11 @@ import (
 )
 
 func (c *TextCollection) ExportToXlsx(outputPath string, timestamps map[uint32]int64) error {
+	return c.ExportEntriesToXlsx(outputPath, slices.Sorted(maps.Keys(c.Entries)), timestamps)
+}
+
+// Exports only the entries with the given IDs, in the given order.
+func (c *TextCollection) ExportEntriesToXlsx(outputPath string, ids []uint32, timestamps map[uint32]int64) error {
 	xlsxFile := xlsx.NewFile()
 	sheet, err := xlsxFile.AddSheet("Sheet1")
 	if err != nil {
@@ -37,8 +42,6 @@ func (c *TextCollection) ExportToXlsx(outputPath string, timestamps map[uint32]i
 	headerRow.AddCell().Value = "pitch variance"
 	headerRow.AddCell().Value = "timestamp"
 
-	ids := slices.Sorted(maps.Keys(c.Entries))
-
 	for _, id := range ids {
 		entry := c.Entries[id]
 
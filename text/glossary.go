@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"codeberg.org/tealeg/xlsx/v4"
+)
+
+// Term types of the glossary, in the order of output.
+var GlossaryTypes = []string{"creature", "item", "spell", "area", "store"}
+
+// Contexts of strrefs holding names of creatures, items, spells, places and
+// stores, by term type. The extension selects
+// resources the names belong to; names of areas are only used in world
+// maps, so their resrefs are taken from the context instead.
+var nameContexts = map[string]struct {
+	context   ContextType
+	keys      []string
+	extension string
+	resrefKey string
+}{
+	"creature": {ContextCreature, []string{"Long name", "Short name (tooltip)"}, ".CRE", ""},
+	"item":     {ContextItem, []string{"Identified item name", "General (unidentified) item name"}, ".ITM", ""},
+	"spell":    {ContextSpell, []string{"Identified spell name", "General (unidentified) spell name"}, ".SPL", ""},
+	"area":     {ContextWorldMap, []string{"Area caption", "Area tooltip"}, "", worldMapAreaKey},
+	"store":    {ContextStore, []string{"Store name"}, ".STO", ""},
+}
+
+// GlossaryTerm is a name of the given type. Strrefs with the same text are
+// merged into one term.
+type GlossaryTerm struct {
+	Source string `json:"source"`
+	// Distinct translations of the strrefs, if a translation is given.
+	Targets []string `json:"targets,omitempty"`
+	Type    string   `json:"type"`
+	Ids     []uint32 `json:"ids"`
+	Resrefs []string `json:"resrefs"`
+	// Number of places (e.g. item files) using the name.
+	Occurrences int `json:"occurrences"`
+}
+
+// Collects names of the given types (all types if empty) from the loaded
+// context. Terms are ordered by type and source text.
+func (c *TextCollection) Glossary(types []string, translations map[uint32]string) ([]GlossaryTerm, error) {
+	if len(types) == 0 {
+		types = GlossaryTypes
+	}
+	for _, termType := range types {
+		if _, ok := nameContexts[termType]; !ok {
+			return nil, fmt.Errorf("unknown term type %q (valid: %s)", termType, strings.Join(GlossaryTypes, ", "))
+		}
+	}
+
+	var terms []GlossaryTerm
+	for _, termType := range GlossaryTypes {
+		if !slices.Contains(types, termType) {
+			continue
+		}
+		terms = append(terms, c.glossaryTerms(termType, translations)...)
+	}
+
+	return terms, nil
+}
+
+func (c *TextCollection) glossaryTerms(termType string, translations map[uint32]string) []GlossaryTerm {
+	def := nameContexts[termType]
+	bySource := make(map[string]*GlossaryTerm)
+
+	for _, id := range slices.Sorted(maps.Keys(c.Entries)) {
+		entry := c.Entries[id]
+		source := strings.TrimSpace(entry.Text)
+		if source == "" {
+			continue
+		}
+
+		occurrences := 0
+		for _, key := range def.keys {
+			occurrences += len(entry.Context[def.context][key])
+		}
+		if occurrences == 0 {
+			continue
+		}
+
+		term, ok := bySource[source]
+		if !ok {
+			term = &GlossaryTerm{Source: source, Type: termType}
+			bySource[source] = term
+		}
+		term.Ids = append(term.Ids, id)
+		term.Occurrences += occurrences
+
+		resrefs := entry.Context[def.context][def.resrefKey]
+		if def.resrefKey == "" {
+			resrefs = nil
+			for resource := range entry.Resources {
+				if resref, ok := strings.CutSuffix(resource, def.extension); ok {
+					resrefs = append(resrefs, resref)
+				}
+			}
+		}
+		for _, resref := range resrefs {
+			if !slices.Contains(term.Resrefs, resref) {
+				term.Resrefs = append(term.Resrefs, resref)
+			}
+		}
+
+		if target := strings.TrimSpace(translations[id]); target != "" && !slices.Contains(term.Targets, target) {
+			term.Targets = append(term.Targets, target)
+		}
+	}
+
+	terms := make([]GlossaryTerm, 0, len(bySource))
+	for _, term := range bySource {
+		slices.Sort(term.Resrefs)
+		terms = append(terms, *term)
+	}
+	slices.SortFunc(terms, func(a, b GlossaryTerm) int {
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.Source), strings.ToLower(b.Source)),
+			cmp.Compare(a.Source, b.Source),
+		)
+	})
+	return terms
+}
+
+// Writes the terms to an XLSX file, one term per row. Several translations
+// of a term are joined with " | ".
+func WriteGlossaryXlsx(outputPath string, terms []GlossaryTerm) error {
+	xlsxFile := xlsx.NewFile()
+	sheet, err := xlsxFile.AddSheet("Glossary")
+	if err != nil {
+		return fmt.Errorf("failed to add sheet: %w", err)
+	}
+
+	headerRow := sheet.AddRow()
+	for _, title := range []string{"source", "target", "type", "resrefs", "occurrences", "strrefs"} {
+		headerRow.AddCell().Value = title
+	}
+
+	for _, term := range terms {
+		row := sheet.AddRow()
+		row.AddCell().SetString(term.Source)
+		row.AddCell().SetString(strings.Join(term.Targets, " | "))
+		row.AddCell().SetString(term.Type)
+		row.AddCell().SetString(strings.Join(term.Resrefs, ", "))
+		row.AddCell().SetInt(term.Occurrences)
+		row.AddCell().SetString(joinIds(term.Ids))
+	}
+
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("unable to create output directory %s: %v", outputDir, err)
+	}
+
+	if err := xlsxFile.Save(outputPath); err != nil {
+		return fmt.Errorf("failed to save xlsx file: %w", err)
+	}
+	return nil
+}
+
+type tbxDocument struct {
+	XMLName xml.Name     `xml:"tbx"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Type    string       `xml:"type,attr"`
+	Style   string       `xml:"style,attr"`
+	Lang    string       `xml:"xml:lang,attr"`
+	Header  string       `xml:"tbxHeader>fileDesc>sourceDesc>p"`
+	Entries []tbxConcept `xml:"text>body>conceptEntry"`
+}
+
+type tbxConcept struct {
+	Id       string       `xml:"id,attr"`
+	Subject  tbxDescrip   `xml:"descrip"`
+	Note     string       `xml:"note"`
+	LangSecs []tbxLangSec `xml:"langSec"`
+}
+
+type tbxDescrip struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tbxLangSec struct {
+	Lang  string   `xml:"xml:lang,attr"`
+	Terms []string `xml:"termSec>term"`
+}
+
+// Writes the terms as a TBX-Basic (ISO 30042:2019) term base. Language codes
+// like en_US are written as en-US. Target terms are only written if
+// targetLang is set.
+func WriteGlossaryTbx(w io.Writer, terms []GlossaryTerm, sourceLang, targetLang string) error {
+	sourceLang = strings.ReplaceAll(sourceLang, "_", "-")
+	targetLang = strings.ReplaceAll(targetLang, "_", "-")
+
+	doc := tbxDocument{
+		Xmlns:  "urn:iso:std:iso:30042:ed-2",
+		Type:   "TBX-Basic",
+		Style:  "dca",
+		Lang:   sourceLang,
+		Header: "Names of creatures, items, spells, areas and stores exported by sbt-inf",
+	}
+
+	for i, term := range terms {
+		concept := tbxConcept{
+			Id:      fmt.Sprintf("c%d", i+1),
+			Subject: tbxDescrip{Type: "subjectField", Value: term.Type},
+			Note: fmt.Sprintf("strrefs: %s; resrefs: %s; occurrences: %d",
+				joinIds(term.Ids), strings.Join(term.Resrefs, ", "), term.Occurrences),
+			LangSecs: []tbxLangSec{{Lang: sourceLang, Terms: []string{term.Source}}},
+		}
+		if targetLang != "" && len(term.Targets) > 0 {
+			concept.LangSecs = append(concept.LangSecs, tbxLangSec{Lang: targetLang, Terms: term.Targets})
+		}
+		doc.Entries = append(doc.Entries, concept)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write TBX: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func joinIds(ids []uint32) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
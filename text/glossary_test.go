@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGlossary(t *testing.T) {
+	c := &TextCollection{Entries: make(map[uint32]*TextEntry)}
+	for id, text := range map[uint32]string{1: "Imoen", 2: "Imoen", 3: "Long Sword", 4: "Hello", 5: "Friendly Arm Inn"} {
+		c.Entries[id] = &TextEntry{
+			Id:        id,
+			Text:      text,
+			Labels:    make(map[string]struct{}),
+			Resources: make(map[string]struct{}),
+			Context:   make(map[ContextType]map[string][]string),
+		}
+	}
+
+	c.AddContext(1, ContextCreature, "Long name", "imoen.cre")
+	c.AddResource(1, "IMOEN.CRE")
+	c.AddContext(2, ContextCreature, "Long name", "imoen2.cre")
+	c.AddContext(2, ContextCreature, "Short name (tooltip)", "imoen2.cre")
+	c.AddResource(2, "IMOEN2.CRE")
+	c.AddContext(3, ContextItem, "Identified item name", "SW1H01.ITM")
+	c.AddContext(3, ContextItem, "Identified item name", "SW1H02.ITM")
+	c.AddResource(3, "SW1H01.ITM")
+	c.AddResource(3, "SW1H02.ITM")
+	c.AddContext(4, ContextDialog, "IMOEN[0]", "")
+	c.AddContext(5, ContextWorldMap, "Area caption", "WORLDMAP.WMP → map 0 → area 1")
+	c.AddResource(5, "WORLDMAP.WMP")
+	c.addAreaResref(5, "ar2300")
+
+	translations := map[uint32]string{1: "Імоен", 2: "Імоен", 3: "Довгий меч"}
+
+	terms, err := c.Glossary(nil, translations)
+	if err != nil {
+		t.Fatalf("Glossary() error = %v", err)
+	}
+
+	want := []GlossaryTerm{
+		{Source: "Imoen", Targets: []string{"Імоен"}, Type: "creature", Ids: []uint32{1, 2}, Resrefs: []string{"IMOEN", "IMOEN2"}, Occurrences: 3},
+		{Source: "Long Sword", Targets: []string{"Довгий меч"}, Type: "item", Ids: []uint32{3}, Resrefs: []string{"SW1H01", "SW1H02"}, Occurrences: 2},
+		{Source: "Friendly Arm Inn", Type: "area", Ids: []uint32{5}, Resrefs: []string{"AR2300"}, Occurrences: 1},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("Glossary() = %+v, want %+v", terms, want)
+	}
+	for i := range want {
+		got := terms[i]
+		if got.Source != want[i].Source || got.Type != want[i].Type || got.Occurrences != want[i].Occurrences ||
+			!slices.Equal(got.Targets, want[i].Targets) || !slices.Equal(got.Ids, want[i].Ids) || !slices.Equal(got.Resrefs, want[i].Resrefs) {
+			t.Errorf("Glossary()[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	if _, err := c.Glossary([]string{"dialog"}, nil); err == nil {
+		t.Errorf("Glossary(dialog) error = nil, want unknown type error")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGlossaryTbx(&buf, terms[:1], "en_US", "uk_UA"); err != nil {
+		t.Fatalf("WriteGlossaryTbx() error = %v", err)
+	}
+	for _, s := range []string{`xml:lang="en-US"`, `<langSec xml:lang="uk-UA">`, "<term>Імоен</term>", `<descrip type="subjectField">creature</descrip>`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("WriteGlossaryTbx() output does not contain %s:\n%s", s, buf.String())
+		}
+	}
+}
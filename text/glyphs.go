@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// MissingGlyph is a character that some of the fonts are unable to render.
+type MissingGlyph struct {
+	Rune  rune
+	Fonts []string
+	Ids   []uint32
+}
+
+// Returns every character used in the texts, with sorted strrefs using it.
+// Whitespace and control characters are skipped: the game does not render them.
+func CollectCharacters(texts map[uint32]string) map[rune][]uint32 {
+	chars := make(map[rune][]uint32)
+	for _, id := range slices.Sorted(maps.Keys(texts)) {
+		seen := make(map[rune]struct{})
+		for _, r := range texts[id] {
+			if unicode.IsSpace(r) || unicode.IsControl(r) {
+				continue
+			}
+			if _, ok := seen[r]; ok {
+				continue
+			}
+			seen[r] = struct{}{}
+			chars[r] = append(chars[r], id)
+		}
+	}
+	return chars
+}
+
+// Checks every character used in the texts against the fonts.
+// Returns characters missing in at least one font, ordered by code point.
+func FindMissingGlyphs(texts map[uint32]string, fonts []Font) []MissingGlyph {
+	var missing []MissingGlyph
+
+	chars := CollectCharacters(texts)
+	for _, r := range slices.Sorted(maps.Keys(chars)) {
+		var fontNames []string
+		for _, font := range fonts {
+			if !font.HasGlyph(r) {
+				fontNames = append(fontNames, font.Name())
+			}
+		}
+		if len(fontNames) > 0 {
+			missing = append(missing, MissingGlyph{Rune: r, Fonts: fontNames, Ids: chars[r]})
+		}
+	}
+
+	return missing
+}
+
+// Decodes texts of a TLK file from the code page of a classic game. TLK
+// files of the Enhanced Editions are in UTF-8, so if every text is valid
+// UTF-8, the texts are returned as is.
+func DecodeTlkTexts(texts map[uint32]string, codePage encoding.Encoding) (map[uint32]string, error) {
+	isUtf8 := true
+	for _, text := range texts {
+		if !utf8.ValidString(text) {
+			isUtf8 = false
+			break
+		}
+	}
+	if isUtf8 {
+		return texts, nil
+	}
+
+	decoder := codePage.NewDecoder()
+	decoded := make(map[uint32]string, len(texts))
+	for id, text := range texts {
+		s, err := decoder.String(text)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode text of entry %d: %w", id, err)
+		}
+		decoded[id] = s
+	}
+	return decoded, nil
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"encoding/binary"
+	"maps"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Builds a BAM V1 font with glyphs for 'A' and 'Ї' (0xAF in windows-1251)
+// a 1x1 placeholder frame for 'B' and a 3 pixel wide space.
+func newTestBamFont() []byte {
+	const cycleCount = 0xAF
+	frames := [][2]uint16{{5, 8}, {1, 1}, {3, 1}}
+	cycles := map[int]uint16{' ' - 1: 2, 'A' - 1: 0, 'B' - 1: 1, 0xAF - 1: 0}
+
+	framesOffset := 24
+	cyclesOffset := framesOffset + len(frames)*12
+	lookupOffset := cyclesOffset + cycleCount*4
+
+	data := make([]byte, lookupOffset+len(cycles)*2)
+	copy(data, "BAM V1  ")
+	binary.LittleEndian.PutUint16(data[8:], uint16(len(frames)))
+	data[10] = cycleCount
+	binary.LittleEndian.PutUint32(data[12:], uint32(framesOffset))
+	binary.LittleEndian.PutUint32(data[20:], uint32(lookupOffset))
+
+	for i, frame := range frames {
+		binary.LittleEndian.PutUint16(data[framesOffset+i*12:], frame[0])
+		binary.LittleEndian.PutUint16(data[framesOffset+i*12+2:], frame[1])
+	}
+
+	lookup := 0
+	for c := range cycleCount {
+		frame, ok := cycles[c]
+		if !ok {
+			continue
+		}
+		binary.LittleEndian.PutUint16(data[cyclesOffset+c*4:], 1)
+		binary.LittleEndian.PutUint16(data[cyclesOffset+c*4+2:], uint16(lookup))
+		binary.LittleEndian.PutUint16(data[lookupOffset+lookup*2:], frame)
+		lookup++
+	}
+
+	return data
+}
+
+func TestFindMissingGlyphs(t *testing.T) {
+	font, err := ParseBamFont("NORMAL.BAM", newTestBamFont(), charmap.Windows1251)
+	if err != nil {
+		t.Fatalf("ParseBamFont() error = %v", err)
+	}
+
+	texts := map[uint32]string{
+		1: "A AЇ",
+		2: "AB",
+		3: "Ґ\nA",
+	}
+
+	// 'Ї' is present in the font, 'B' only has a placeholder frame.
+	missing := FindMissingGlyphs(texts, []Font{font})
+
+	want := []MissingGlyph{
+		{Rune: 'B', Fonts: []string{"NORMAL.BAM"}, Ids: []uint32{2}},
+		{Rune: 'Ґ', Fonts: []string{"NORMAL.BAM"}, Ids: []uint32{3}},
+	}
+
+	if len(missing) != len(want) {
+		t.Fatalf("FindMissingGlyphs() = %v, want %v", missing, want)
+	}
+	for i := range want {
+		if missing[i].Rune != want[i].Rune || !slices.Equal(missing[i].Fonts, want[i].Fonts) || !slices.Equal(missing[i].Ids, want[i].Ids) {
+			t.Errorf("FindMissingGlyphs()[%d] = %v, want %v", i, missing[i], want[i])
+		}
+	}
+}
+
+func TestDecodeTlkTexts(t *testing.T) {
+	cp1251, _ := charmap.Windows1251.NewEncoder().String("Їжак")
+
+	tests := []struct {
+		name  string
+		texts map[uint32]string
+		want  map[uint32]string
+	}{
+		{"utf-8", map[uint32]string{1: "Їжак", 2: "Hedgehog"}, map[uint32]string{1: "Їжак", 2: "Hedgehog"}},
+		{"code page", map[uint32]string{1: cp1251, 2: "Hedgehog"}, map[uint32]string{1: "Їжак", 2: "Hedgehog"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTlkTexts(tt.texts, charmap.Windows1251)
+			if err != nil {
+				t.Fatalf("DecodeTlkTexts() error = %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("DecodeTlkTexts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	p "github.com/sbtlocalization/sbt-infinity/parser"
+	"github.com/spf13/afero"
+)
+
+// Returns the path of the female TLK file next to a male one, like
+// lang/uk_UA/dialogf.tlk for lang/uk_UA/dialog.tlk. The case of the
+// name is kept: DIALOG.TLK gives DIALOGF.TLK.
+func FemaleTlkPath(path string) string {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	suffix := "f"
+	if base := filepath.Base(stem); base != "" && strings.ToUpper(base) == base {
+		suffix = "F"
+	}
+	return stem + suffix + ext
+}
+
+// Reads the entries of a TLK file and, if it exists, of the female TLK
+// file next to it (see FemaleTlkPath). The female entries are nil if
+// there is no such file.
+func ReadTlkWriteEntries(fs afero.Fs, path string) ([]TlkWriteEntry, []TlkWriteEntry, uint16, error) {
+	readEntries := func(path string) ([]TlkWriteEntry, uint16, error) {
+		tlkFile, err := p.ReadTlkFile(fs, path)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer tlkFile.Close()
+
+		entries, err := TlkWriteEntriesFromTlk(tlkFile.Tlk)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read TLK file %s: %w", path, err)
+		}
+		return entries, tlkFile.Lang, nil
+	}
+
+	male, lang, err := readEntries(path)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	femalePath := FemaleTlkPath(path)
+	if _, err := fs.Stat(femalePath); err != nil {
+		return male, nil, lang, nil
+	}
+
+	female, _, err := readEntries(femalePath)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return male, female, lang, nil
+}
+
+// TlkPatch is a new text of a TLK entry. Without a female variant, the
+// male entry is used for both files.
+type TlkPatch struct {
+	Key       uint32
+	Male      TlkWriteEntry
+	Female    TlkWriteEntry
+	HasFemale bool
+}
+
+type TlkPatchStats struct {
+	Applied     int
+	Unchanged   int
+	Appended    int
+	Conflicting []uint32 // keys of kept female variants
+}
+
+func (s *TlkPatchStats) count(old *TlkWriteEntry, new TlkWriteEntry) {
+	switch {
+	case old == nil:
+		s.Appended++
+	case *old == new:
+		s.Unchanged++
+	default:
+		s.Applied++
+	}
+}
+
+// Applies the patches over the base TLK entries. Entries without patches
+// are kept as is; patches beyond the end of the base are appended to both
+// files, so they always have the same number of entries.
+//
+// If there is a base female TLK, only texts of its entries are replaced:
+// their sound and flags stay, as they may differ from the male ones. An
+// entry with a distinct female variant in the base, but a single text in
+// the patch, keeps the variant and is reported as conflicting.
+//
+// Without a base female TLK, the female entries are only built when some
+// patch has a female variant; otherwise nil is returned for them.
+func PatchTlkEntries(baseMale, baseFemale []TlkWriteEntry, patches []TlkPatch) ([]TlkWriteEntry, []TlkWriteEntry, TlkPatchStats, TlkPatchStats) {
+	var maleStats, femaleStats TlkPatchStats
+
+	hasBaseFemale := baseFemale != nil
+	if !hasBaseFemale {
+		baseFemale = baseMale
+	}
+
+	size := max(len(baseMale), len(baseFemale))
+	for _, patch := range patches {
+		size = max(size, int(patch.Key)+1)
+	}
+
+	maleEntries := make([]TlkWriteEntry, size)
+	femaleEntries := make([]TlkWriteEntry, size)
+	for i := range size {
+		maleEntries[i] = NewEmptyTlkEntry()
+		femaleEntries[i] = NewEmptyTlkEntry()
+	}
+	copy(maleEntries, baseMale)
+	copy(femaleEntries, baseFemale)
+
+	hasFemale := hasBaseFemale
+
+	for _, patch := range patches {
+		male, female := patch.Male, patch.Female
+		if patch.HasFemale {
+			hasFemale = true
+		} else {
+			female = male
+		}
+
+		var oldMale, oldFemale *TlkWriteEntry
+		if int(patch.Key) < len(baseMale) {
+			oldMale = &baseMale[patch.Key]
+		}
+		if int(patch.Key) < len(baseFemale) {
+			oldFemale = &baseFemale[patch.Key]
+		}
+
+		maleStats.count(oldMale, male)
+		maleEntries[patch.Key] = male
+
+		if hasBaseFemale && oldFemale != nil {
+			if !patch.HasFemale && oldMale != nil && oldFemale.Text != oldMale.Text && oldFemale.Text != female.Text {
+				// the base has a distinct female variant, but the patch does not
+				femaleStats.Conflicting = append(femaleStats.Conflicting, patch.Key)
+				continue
+			}
+			text, hasText := female.Text, female.HasText
+			female = *oldFemale
+			female.Text, female.HasText = text, hasText
+		}
+
+		femaleStats.count(oldFemale, female)
+		femaleEntries[patch.Key] = female
+	}
+
+	if !hasFemale {
+		return maleEntries, nil, maleStats, femaleStats
+	}
+	return maleEntries, femaleEntries, maleStats, femaleStats
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFemaleTlkPath(t *testing.T) {
+	tests := map[string]string{
+		"lang/uk_UA/dialog.tlk": "lang/uk_UA/dialogf.tlk",
+		"LANG/DIALOG.TLK":       "LANG/DIALOGF.TLK",
+		"custom.tlk":            "customf.tlk",
+	}
+	for path, want := range tests {
+		if got := FemaleTlkPath(path); got != want {
+			t.Errorf("FemaleTlkPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestPatchTlkEntries(t *testing.T) {
+	entry := func(text string) TlkWriteEntry {
+		return TlkWriteEntry{Text: text, HasText: text != ""}
+	}
+	voiced := func(text, sound string) TlkWriteEntry {
+		e := entry(text)
+		e.HasSound, e.AudioName = true, sound
+		return e
+	}
+	texts := func(entries []TlkWriteEntry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.Text)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		baseMale    []TlkWriteEntry
+		baseFemale  []TlkWriteEntry
+		patches     []TlkPatch
+		wantMale    []string
+		wantFemale  []string // nil if no female file is written
+		wantMaleSt  TlkPatchStats
+		wantFemSt   TlkPatchStats
+		checkFemale func(t *testing.T, female []TlkWriteEntry)
+	}{
+		{
+			name:       "patch and append without female file",
+			baseMale:   []TlkWriteEntry{entry("A"), entry("B")},
+			patches:    []TlkPatch{{Key: 1, Male: entry("Б")}, {Key: 3, Male: entry("Г")}},
+			wantMale:   []string{"A", "Б", "", "Г"},
+			wantMaleSt: TlkPatchStats{Applied: 1, Appended: 1},
+			wantFemSt:  TlkPatchStats{Applied: 1, Appended: 1},
+		},
+		{
+			name:       "female variant creates female file",
+			baseMale:   []TlkWriteEntry{entry("A"), entry("B")},
+			patches:    []TlkPatch{{Key: 0, Male: entry("Готовий"), Female: entry("Готова"), HasFemale: true}},
+			wantMale:   []string{"Готовий", "B"},
+			wantFemale: []string{"Готова", "B"},
+			wantMaleSt: TlkPatchStats{Applied: 1},
+			wantFemSt:  TlkPatchStats{Applied: 1},
+		},
+		{
+			name:       "same count in both files",
+			baseMale:   []TlkWriteEntry{entry("A")},
+			baseFemale: []TlkWriteEntry{entry("A"), entry("B")},
+			patches:    []TlkPatch{{Key: 0, Male: entry("А")}},
+			wantMale:   []string{"А", ""},
+			wantFemale: []string{"А", "B"},
+			wantMaleSt: TlkPatchStats{Applied: 1},
+			wantFemSt:  TlkPatchStats{Applied: 1},
+		},
+		{
+			name:       "appended to both files",
+			baseMale:   []TlkWriteEntry{entry("A")},
+			baseFemale: []TlkWriteEntry{entry("A")},
+			patches:    []TlkPatch{{Key: 2, Male: entry("В")}},
+			wantMale:   []string{"A", "", "В"},
+			wantFemale: []string{"A", "", "В"},
+			wantMaleSt: TlkPatchStats{Appended: 1},
+			wantFemSt:  TlkPatchStats{Appended: 1},
+		},
+		{
+			name:       "female flags kept",
+			baseMale:   []TlkWriteEntry{voiced("Hello", "M_HELLO")},
+			baseFemale: []TlkWriteEntry{voiced("Hello", "F_HELLO")},
+			patches:    []TlkPatch{{Key: 0, Male: voiced("Привіт", "M_HELLO")}},
+			wantMale:   []string{"Привіт"},
+			wantFemale: []string{"Привіт"},
+			wantMaleSt: TlkPatchStats{Applied: 1},
+			wantFemSt:  TlkPatchStats{Applied: 1},
+			checkFemale: func(t *testing.T, female []TlkWriteEntry) {
+				if !female[0].HasSound || female[0].AudioName != "F_HELLO" {
+					t.Errorf("female sound = %v %q, want the base F_HELLO", female[0].HasSound, female[0].AudioName)
+				}
+			},
+		},
+		{
+			name:       "unchanged",
+			baseMale:   []TlkWriteEntry{entry("A")},
+			baseFemale: []TlkWriteEntry{entry("A")},
+			patches:    []TlkPatch{{Key: 0, Male: entry("A")}},
+			wantMale:   []string{"A"},
+			wantFemale: []string{"A"},
+			wantMaleSt: TlkPatchStats{Unchanged: 1},
+			wantFemSt:  TlkPatchStats{Unchanged: 1},
+		},
+		{
+			name:       "distinct female variant kept",
+			baseMale:   []TlkWriteEntry{entry("Ready")},
+			baseFemale: []TlkWriteEntry{entry("Ready!")},
+			patches:    []TlkPatch{{Key: 0, Male: entry("Готовий")}},
+			wantMale:   []string{"Готовий"},
+			wantFemale: []string{"Ready!"},
+			wantMaleSt: TlkPatchStats{Applied: 1},
+			wantFemSt:  TlkPatchStats{Conflicting: []uint32{0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			male, female, maleStats, femaleStats := PatchTlkEntries(tt.baseMale, tt.baseFemale, tt.patches)
+
+			if got := texts(male); !slices.Equal(got, tt.wantMale) {
+				t.Errorf("male = %q, want %q", got, tt.wantMale)
+			}
+			if got := texts(female); !slices.Equal(got, tt.wantFemale) {
+				t.Errorf("female = %q, want %q", got, tt.wantFemale)
+			}
+			if female != nil && len(female) != len(male) {
+				t.Errorf("len(female) = %d, want %d as male", len(female), len(male))
+			}
+			if maleStats.Applied != tt.wantMaleSt.Applied || maleStats.Unchanged != tt.wantMaleSt.Unchanged || maleStats.Appended != tt.wantMaleSt.Appended {
+				t.Errorf("male stats = %+v, want %+v", maleStats, tt.wantMaleSt)
+			}
+			if femaleStats.Applied != tt.wantFemSt.Applied || femaleStats.Unchanged != tt.wantFemSt.Unchanged ||
+				femaleStats.Appended != tt.wantFemSt.Appended || !slices.Equal(femaleStats.Conflicting, tt.wantFemSt.Conflicting) {
+				t.Errorf("female stats = %+v, want %+v", femaleStats, tt.wantFemSt)
+			}
+			if tt.checkFemale != nil {
+				tt.checkFemale(t, female)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// StatsCounts holds entry, word and character counts of a set of strrefs,
+// in total, translated and left to translate.
+type StatsCounts struct {
+	Entries           int `json:"entries"`
+	Words             int `json:"words"`
+	Chars             int `json:"chars"`
+	TranslatedEntries int `json:"translated_entries"`
+	TranslatedWords   int `json:"translated_words"`
+	TranslatedChars   int `json:"translated_chars"`
+	LeftEntries       int `json:"left_entries"`
+	LeftWords         int `json:"left_words"`
+	LeftChars         int `json:"left_chars"`
+}
+
+// Returns the share of translated words in percent.
+func (s *StatsCounts) Percent() float64 {
+	if s.Words == 0 {
+		if s.Entries == 0 {
+			return 0
+		}
+		return 100 * float64(s.TranslatedEntries) / float64(s.Entries)
+	}
+	return 100 * float64(s.TranslatedWords) / float64(s.Words)
+}
+
+func (s *StatsCounts) add(words, chars int, translated bool) {
+	s.Entries++
+	s.Words += words
+	s.Chars += chars
+	if translated {
+		s.TranslatedEntries++
+		s.TranslatedWords += words
+		s.TranslatedChars += chars
+	} else {
+		s.LeftEntries++
+		s.LeftWords += words
+		s.LeftChars += chars
+	}
+}
+
+// Stats groups the counts by category. A strref shared by several categories
+// is counted once in each of them and once in Total.
+type Stats struct {
+	Total     StatsCounts             `json:"total"`
+	ByLabel   map[string]*StatsCounts `json:"by_label"`
+	ByDialog  map[string]*StatsCounts `json:"by_dialog"`
+	ByContext map[string]*StatsCounts `json:"by_context"`
+}
+
+// Counts words and characters of the entries with non-empty text. An entry
+// is translated if translations contain a non-empty text different from the
+// source. Translations may be nil.
+func (c *TextCollection) Stats(ids []uint32, translations map[uint32]string) *Stats {
+	stats := &Stats{
+		ByLabel:   make(map[string]*StatsCounts),
+		ByDialog:  make(map[string]*StatsCounts),
+		ByContext: make(map[string]*StatsCounts),
+	}
+
+	for _, id := range ids {
+		entry, ok := c.Entries[id]
+		if !ok || strings.TrimSpace(entry.Text) == "" {
+			continue
+		}
+
+		words := len(strings.Fields(entry.Text))
+		chars := utf8.RuneCountInString(entry.Text)
+		translation := translations[id]
+		translated := strings.TrimSpace(translation) != "" && translation != entry.Text
+
+		stats.Total.add(words, chars, translated)
+
+		for label := range entry.Labels {
+			countsFor(stats.ByLabel, label).add(words, chars, translated)
+		}
+		for resource := range entry.Resources {
+			if name, ok := strings.CutSuffix(resource, ".DLG"); ok {
+				countsFor(stats.ByDialog, name).add(words, chars, translated)
+			}
+		}
+		for t, context := range entry.Context {
+			if len(context) > 0 {
+				countsFor(stats.ByContext, t.String()).add(words, chars, translated)
+			}
+		}
+	}
+
+	return stats
+}
+
+func countsFor(m map[string]*StatsCounts, key string) *StatsCounts {
+	counts, ok := m[key]
+	if !ok {
+		counts = &StatsCounts{}
+		m[key] = counts
+	}
+	return counts
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	c := newTestCollection()
+	c.Entries[1].Text = "Elven wine"
+	c.Entries[2].Text = "Long Sword"
+	c.Entries[3].Text = "Hello there, friend."
+	c.Entries[4].Text = "Go away."
+
+	translations := map[uint32]string{
+		1: "Ельфійське вино",
+		2: "Long Sword",
+		3: "Привіт, друже.",
+	}
+
+	stats := c.Stats(c.Filter(nil), translations)
+
+	want := StatsCounts{Entries: 4, Words: 9, Chars: 48, TranslatedEntries: 2, TranslatedWords: 5, TranslatedChars: 30,
+		LeftEntries: 2, LeftWords: 4, LeftChars: 18}
+	if stats.Total != want {
+		t.Errorf("Total = %+v, want %+v", stats.Total, want)
+	}
+
+	// Entry 4 is used in two dialogs but counted once per dialog.
+	if got := stats.ByDialog["AR0202"]; got == nil || got.Entries != 2 || got.TranslatedEntries != 1 {
+		t.Errorf("ByDialog[AR0202] = %+v, want 2 entries with 1 translated", got)
+	}
+	if got := stats.ByLabel[lb_dialog]; got == nil || got.Words != 5 || got.LeftWords != 2 || got.LeftChars != 8 {
+		t.Errorf("ByLabel[dialog] = %+v, want 5 words with 2 words and 8 chars left", got)
+	}
+	if got := stats.ByContext["ITEMS"]; got == nil || got.Percent() != 0 {
+		t.Errorf("ByContext[ITEMS] = %+v, want untranslated", got)
+	}
+}
@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"codeberg.org/tealeg/xlsx/v4"
+)
+
+// GlossaryEntry is a source term with its approved translations.
+type GlossaryEntry struct {
+	Source  string   `json:"source"`
+	Targets []string `json:"targets"`
+}
+
+type tbxReadDocument struct {
+	Lang     string           `xml:"lang,attr"`
+	Concepts []tbxReadConcept `xml:"text>body>conceptEntry"`
+	Entries  []tbxReadConcept `xml:"text>body>termEntry"`
+}
+
+// A concept of TBX v3 (conceptEntry, langSec, termSec) or TBX v2
+// (termEntry, langSet, tig or ntig).
+type tbxReadConcept struct {
+	LangSecs []tbxReadLangSec `xml:"langSec"`
+	LangSets []tbxReadLangSec `xml:"langSet"`
+}
+
+type tbxReadLangSec struct {
+	Lang     string   `xml:"lang,attr"`
+	Terms    []string `xml:"termSec>term"`
+	TigTerms []string `xml:"tig>term"`
+	NtigTerm []string `xml:"ntig>termGrp>term"`
+}
+
+func (s tbxReadLangSec) terms() []string {
+	return slices.Concat(s.Terms, s.TigTerms, s.NtigTerm)
+}
+
+// Reads a TBX term base. The source language is the language of the document
+// if given, otherwise the first language of every concept. Terms of all other
+// languages are targets. Concepts without targets are skipped.
+func ReadGlossaryTbx(r io.Reader) ([]GlossaryEntry, error) {
+	var doc tbxReadDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to parse TBX: %w", err)
+	}
+
+	var entries []GlossaryEntry
+	for _, concept := range slices.Concat(doc.Concepts, doc.Entries) {
+		langSecs := slices.Concat(concept.LangSecs, concept.LangSets)
+		if len(langSecs) == 0 {
+			continue
+		}
+
+		sourceIdx := slices.IndexFunc(langSecs, func(s tbxReadLangSec) bool {
+			return doc.Lang != "" && strings.EqualFold(s.Lang, doc.Lang)
+		})
+		if sourceIdx < 0 {
+			sourceIdx = 0
+		}
+
+		var targets []string
+		for i, langSec := range langSecs {
+			if i != sourceIdx {
+				targets = append(targets, langSec.terms()...)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		for _, source := range langSecs[sourceIdx].terms() {
+			entries = append(entries, GlossaryEntry{Source: source, Targets: targets})
+		}
+	}
+	return entries, nil
+}
+
+// Reads a glossary from an XLSX file with "source" and "target" columns
+// (e.g. written by 'text glossary'). Alternative targets are separated with " | ".
+// Rows without a target are skipped.
+func ReadGlossaryXlsx(path string) ([]GlossaryEntry, error) {
+	xlsxFile, err := xlsx.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open xlsx file: %w", err)
+	}
+	if len(xlsxFile.Sheets) == 0 {
+		return nil, fmt.Errorf("xlsx file has no sheets")
+	}
+	sheet := xlsxFile.Sheets[0]
+
+	headerRow, err := sheet.Row(0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read header row: %w", err)
+	}
+
+	sourceIdx, targetIdx := -1, -1
+	colIdx := 0
+	headerRow.ForEachCell(func(cell *xlsx.Cell) error {
+		switch strings.ToLower(strings.TrimSpace(cell.Value)) {
+		case "source":
+			sourceIdx = colIdx
+		case "target":
+			targetIdx = colIdx
+		}
+		colIdx++
+		return nil
+	})
+	if sourceIdx == -1 || targetIdx == -1 {
+		return nil, fmt.Errorf("xlsx file must have 'source' and 'target' columns")
+	}
+
+	var entries []GlossaryEntry
+	for rowIdx := 1; rowIdx < sheet.MaxRow; rowIdx++ {
+		row, err := sheet.Row(rowIdx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read row %d: %w", rowIdx+1, err)
+		}
+
+		source := strings.TrimSpace(row.GetCell(sourceIdx).Value)
+		var targets []string
+		for _, target := range strings.Split(row.GetCell(targetIdx).Value, "|") {
+			if target = strings.TrimSpace(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+		if source != "" && len(targets) > 0 {
+			entries = append(entries, GlossaryEntry{Source: source, Targets: targets})
+		}
+	}
+	return entries, nil
+}
+
+type MatchMode int
+
+const (
+	// Terms must appear as whole words with the same case.
+	MatchExact MatchMode = iota
+	// Terms must appear as whole words in any case.
+	MatchIgnoreCase
+	// Every word of a term may have a different ending (any case).
+	MatchStem
+)
+
+// Parses the value of --match: "exact", "case" or "stem".
+func ParseMatchMode(value string) (MatchMode, error) {
+	switch value {
+	case "exact":
+		return MatchExact, nil
+	case "case":
+		return MatchIgnoreCase, nil
+	case "stem":
+		return MatchStem, nil
+	default:
+		return 0, fmt.Errorf("unknown match mode %q (valid: exact, case, stem)", value)
+	}
+}
+
+// TermMatcher finds terms in texts. In MatchStem mode up to StemTrim last
+// letters of every term word may differ, but at least three letters are kept.
+type TermMatcher struct {
+	Mode     MatchMode
+	StemTrim int
+}
+
+// Reports whether the text contains the term as a sequence of words.
+func (m TermMatcher) Contains(s, term string) bool {
+	return m.containsWords(splitWords(s), splitWords(term))
+}
+
+func (m TermMatcher) containsWords(words, termWords []string) bool {
+	if len(termWords) == 0 {
+		return false
+	}
+	for start := 0; start+len(termWords) <= len(words); start++ {
+		matched := true
+		for i, termWord := range termWords {
+			if !m.wordMatches(words[start+i], termWord) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (m TermMatcher) wordMatches(word, termWord string) bool {
+	switch m.Mode {
+	case MatchIgnoreCase:
+		return strings.EqualFold(word, termWord)
+	case MatchStem:
+		stem := []rune(strings.ToLower(termWord))
+		if keep := max(len(stem)-m.StemTrim, min(len(stem), 3)); keep < len(stem) {
+			stem = stem[:keep]
+		}
+		lower := []rune(strings.ToLower(word))
+		return len(lower) >= len(stem) && len(lower) <= len(stem)+m.StemTrim+2 &&
+			string(lower[:len(stem)]) == string(stem)
+	default:
+		return word == termWord
+	}
+}
+
+// Splits the text into words of letters, digits and inner apostrophes.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '\'' && r != '’' && r != 'ʼ'
+	})
+}
+
+// TermIssue lists entries whose source contains the term, but the translation
+// contains none of the approved targets.
+type TermIssue struct {
+	GlossaryEntry
+	Entries []TermIssueEntry `json:"entries"`
+}
+
+type TermIssueEntry struct {
+	Id          uint32 `json:"id"`
+	Source      string `json:"source"`
+	Translation string `json:"translation"`
+}
+
+// Returns the lowercase prefix of the word of up to size letters. In any
+// mode a word matching a term word starts with its prefix of three letters.
+func wordPrefix(word string, size int) string {
+	runes := []rune(strings.ToLower(word))
+	return string(runes[:min(len(runes), size)])
+}
+
+// termText is a translated entry split into words once for all terms.
+type termText struct {
+	id                  uint32
+	source, translation string
+	sourceWords         []string
+	variantWords        [][]string
+}
+
+// Checks translated entries against the glossary. Untranslated entries (empty
+// or equal to the source) are skipped. If separator is set, every variant of
+// the translation must contain a target. Issues are ordered by the number of
+// entries, then by the source term.
+func CheckTerms(glossary []GlossaryEntry, sources, translations map[uint32]string, separator string, matcher TermMatcher) []TermIssue {
+	// Entries by prefixes of their source words, to check only the entries
+	// which may contain the first word of a term.
+	var texts []termText
+	byPrefix := make(map[string][]int)
+	for _, id := range slices.Sorted(maps.Keys(translations)) {
+		source, translation := sources[id], translations[id]
+		if translation == "" || translation == source {
+			continue
+		}
+
+		t := termText{id: id, source: source, translation: translation, sourceWords: splitWords(source)}
+		variants := []string{translation}
+		if separator != "" {
+			variants = strings.Split(translation, separator)
+		}
+		for _, variant := range variants {
+			t.variantWords = append(t.variantWords, splitWords(variant))
+		}
+
+		prefixes := make(map[string]struct{})
+		for _, word := range t.sourceWords {
+			for size := 1; size <= 3; size++ {
+				prefixes[wordPrefix(word, size)] = struct{}{}
+			}
+		}
+		for prefix := range prefixes {
+			byPrefix[prefix] = append(byPrefix[prefix], len(texts))
+		}
+		texts = append(texts, t)
+	}
+
+	var issues []TermIssue
+	for _, term := range glossary {
+		termWords := splitWords(term.Source)
+		if len(termWords) == 0 {
+			continue
+		}
+		targetWords := make([][]string, len(term.Targets))
+		for i, target := range term.Targets {
+			targetWords[i] = splitWords(target)
+		}
+
+		issue := TermIssue{GlossaryEntry: term}
+		for _, i := range byPrefix[wordPrefix(termWords[0], 3)] {
+			t := texts[i]
+			if !matcher.containsWords(t.sourceWords, termWords) {
+				continue
+			}
+
+			ok := !slices.ContainsFunc(t.variantWords, func(words []string) bool {
+				return !slices.ContainsFunc(targetWords, func(target []string) bool {
+					return matcher.containsWords(words, target)
+				})
+			})
+			if !ok {
+				issue.Entries = append(issue.Entries, TermIssueEntry{Id: t.id, Source: t.source, Translation: t.translation})
+			}
+		}
+		if len(issue.Entries) > 0 {
+			issues = append(issues, issue)
+		}
+	}
+
+	slices.SortStableFunc(issues, func(a, b TermIssue) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Entries), len(a.Entries)),
+			cmp.Compare(a.Source, b.Source),
+		)
+	})
+	return issues
+}
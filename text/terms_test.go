@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package text
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTermMatcher(t *testing.T) {
+	tests := []struct {
+		mode       MatchMode
+		text, term string
+		want       bool
+	}{
+		{MatchExact, "Take the Long Sword.", "Long Sword", true},
+		{MatchExact, "Take the long sword.", "Long Sword", false},
+		{MatchExact, "Swordfish", "Sword", false},
+		{MatchIgnoreCase, "Take the long sword.", "Long Sword", true},
+		{MatchStem, "Візьми довгого меча.", "Довгий меч", true},
+		{MatchStem, "Візьми короткого меча.", "Довгий меч", false},
+		{MatchStem, "Це Імоен.", "Імоен", true},
+		{MatchStem, "Довгий і гострий меч", "Довгий меч", false},
+	}
+
+	for _, tt := range tests {
+		m := TermMatcher{Mode: tt.mode, StemTrim: 2}
+		if got := m.Contains(tt.text, tt.term); got != tt.want {
+			t.Errorf("Contains(%q, %q) with mode %d = %v, want %v", tt.text, tt.term, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTerms(t *testing.T) {
+	glossary := []GlossaryEntry{
+		{Source: "Long Sword", Targets: []string{"довгий меч"}},
+		{Source: "Imoen", Targets: []string{"Імоен"}},
+		{Source: "Ox", Targets: []string{"віл"}},
+	}
+	sources := map[uint32]string{
+		1: "A long sword.",
+		2: "Imoen, take the long sword.",
+		3: "Imoen is here.",
+		4: "Imoen!",
+		5: "Oxen are here.",
+	}
+	translations := map[uint32]string{
+		1: "Довгий меч.",
+		2: "Імоен, візьми довгого клинка.",
+		3: "Тут Емоен. // Тут Імоен.",
+		4: "Imoen!",
+		5: "Тут бики.",
+	}
+
+	issues := CheckTerms(glossary, sources, translations, " // ", TermMatcher{Mode: MatchStem, StemTrim: 2})
+	if len(issues) != 3 {
+		t.Fatalf("CheckTerms() = %+v, want 3 issues", issues)
+	}
+	for i, want := range []struct {
+		source string
+		ids    []uint32
+	}{{"Imoen", []uint32{3}}, {"Long Sword", []uint32{2}}, {"Ox", []uint32{5}}} {
+		var ids []uint32
+		for _, entry := range issues[i].Entries {
+			ids = append(ids, entry.Id)
+		}
+		if issues[i].Source != want.source || !slices.Equal(ids, want.ids) {
+			t.Errorf("CheckTerms()[%d] = %s %v, want %s %v", i, issues[i].Source, ids, want.source, want.ids)
+		}
+	}
+}
+
+func TestReadGlossaryTbx(t *testing.T) {
+	const tbx = `<?xml version="1.0" encoding="UTF-8"?>
+<tbx xmlns="urn:iso:std:iso:30042:ed-2" type="TBX-Basic" style="dca" xml:lang="en-US">
+  <text><body>
+    <conceptEntry id="c1">
+      <langSec xml:lang="uk-UA"><termSec><term>Імоен</term></termSec></langSec>
+      <langSec xml:lang="en-US"><termSec><term>Imoen</term></termSec></langSec>
+    </conceptEntry>
+    <conceptEntry id="c2">
+      <langSec xml:lang="en-US"><termSec><term>Gorion</term></termSec></langSec>
+    </conceptEntry>
+  </body></text>
+</tbx>`
+
+	entries, err := ReadGlossaryTbx(strings.NewReader(tbx))
+	if err != nil {
+		t.Fatalf("ReadGlossaryTbx() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Source != "Imoen" || !slices.Equal(entries[0].Targets, []string{"Імоен"}) {
+		t.Errorf("ReadGlossaryTbx() = %+v, want Imoen → Імоен", entries)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	p "github.com/sbtlocalization/sbt-infinity/parser"
 )
 
 const (
@@ -45,6 +47,29 @@ func NewEmptyTlkEntry() TlkWriteEntry {
 	}
 }
 
+// Converts all entries of a parsed TLK file into entries for writing,
+// so the file can be patched and written back.
+func TlkWriteEntriesFromTlk(tlk *p.Tlk) ([]TlkWriteEntry, error) {
+	entries := make([]TlkWriteEntry, len(tlk.Entries))
+	for i, entry := range tlk.Entries {
+		text, err := entry.Text()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read text of entry %d: %w", i, err)
+		}
+
+		entries[i] = TlkWriteEntry{
+			Text:           text,
+			HasText:        entry.Flags.TextExists,
+			HasSound:       entry.Flags.SoundExists,
+			HasToken:       entry.Flags.TokenExists,
+			AudioName:      entry.AudioName,
+			VolumeVariance: entry.VolumeVariance,
+			PitchVariance:  entry.PitchVariance,
+		}
+	}
+	return entries, nil
+}
+
 func WriteTlkFile(path string, entries []TlkWriteEntry, opts TlkWriteOptions) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -0,0 +1,451 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// Extensions of WeiDU sources that may reference TRA entries.
+var SourceExtensions = []string{".tp2", ".tpa", ".tph", ".tpp", ".d", ".baf"}
+
+// Directives followed by TRA file (or folder for AUTO_TRA) paths.
+var includeDirectives = []string{"LOAD_TRA", "USING", "WITH_TRA", "AUTO_TRA"}
+
+// TraRef is a reference to a TRA entry (@123) in a source file.
+type TraRef struct {
+	Id   uint32
+	Line int
+}
+
+// TraInclude is a TRA path given in a LANGUAGE, LOAD_TRA, USING, WITH_TRA
+// or AUTO_TRA directive. The path may contain %s or %LANGUAGE%.
+type TraInclude struct {
+	Directive string
+	Path      string
+	Line      int
+}
+
+// SourceFile is a WeiDU source with its TRA references and includes.
+type SourceFile struct {
+	Path     string
+	Refs     []TraRef
+	Includes []TraInclude
+}
+
+type sourceTokenKind int
+
+const (
+	tokenWord sourceTokenKind = iota
+	tokenString
+	tokenRef
+)
+
+type sourceToken struct {
+	kind sourceTokenKind
+	text string
+	line int
+}
+
+// Splits WeiDU source into words, strings and TRA references, skipping
+// comments.
+func tokenizeSource(content string) []sourceToken {
+	var tokens []sourceToken
+	line := 1
+	pos := 0
+
+	advance := func(end int) {
+		line += strings.Count(content[pos:end], "\n")
+		pos = end
+	}
+
+	for pos < len(content) {
+		ch := content[pos]
+		switch {
+		case unicode.IsSpace(rune(ch)):
+			advance(pos + 1)
+		case strings.HasPrefix(content[pos:], "//"):
+			end := strings.IndexByte(content[pos:], '\n')
+			if end < 0 {
+				end = len(content) - pos
+			}
+			advance(pos + end)
+		case strings.HasPrefix(content[pos:], "/*"):
+			end := strings.Index(content[pos+2:], "*/")
+			if end < 0 {
+				advance(len(content))
+			} else {
+				advance(pos + 2 + end + 2)
+			}
+		case ch == '~' || ch == '"':
+			delimiter := content[pos : pos+1]
+			if strings.HasPrefix(content[pos:], "~~~~~") {
+				delimiter = "~~~~~"
+			}
+			start := pos + len(delimiter)
+			end := strings.Index(content[start:], delimiter)
+			if end < 0 {
+				end = len(content) - start
+			}
+			tokens = append(tokens, sourceToken{tokenString, content[start : start+end], line})
+			advance(min(start+end+len(delimiter), len(content)))
+		case ch == '@':
+			end := pos + 1
+			for end < len(content) && content[end] >= '0' && content[end] <= '9' {
+				end++
+			}
+			if _, err := strconv.ParseUint(content[pos+1:end], 10, 32); err == nil {
+				tokens = append(tokens, sourceToken{tokenRef, content[pos+1 : end], line})
+			}
+			advance(max(end, pos+1))
+		default:
+			end := pos + 1
+			for end < len(content) && !unicode.IsSpace(rune(content[end])) && !strings.ContainsRune("~\"@()", rune(content[end])) {
+				end++
+			}
+			tokens = append(tokens, sourceToken{tokenWord, content[pos:end], line})
+			advance(end)
+		}
+	}
+	return tokens
+}
+
+// Finds TRA references and include directives in WeiDU source.
+func ScanSource(path, content string) SourceFile {
+	src := SourceFile{Path: path}
+	tokens := tokenizeSource(content)
+
+	for i, token := range tokens {
+		switch token.kind {
+		case tokenRef:
+			id, _ := strconv.ParseUint(token.text, 10, 32)
+			src.Refs = append(src.Refs, TraRef{Id: uint32(id), Line: token.line})
+		case tokenWord:
+			directive := strings.ToUpper(token.text)
+			first := i + 1
+			if directive == "LANGUAGE" {
+				// LANGUAGE ~name~ ~folder~ ~file.tra~ ...
+				first = i + 3
+			} else if !slices.Contains(includeDirectives, directive) {
+				continue
+			}
+			for j := first; j < len(tokens) && tokens[j].kind == tokenString; j++ {
+				src.Includes = append(src.Includes, TraInclude{Directive: directive, Path: tokens[j].text, Line: tokens[j].line})
+			}
+		}
+	}
+	return src
+}
+
+// Audit issue kinds.
+const (
+	IssueMissing        = "missing"         // referenced entry absent in a language
+	IssueUnused         = "unused"          // entry not referenced by any source
+	IssueDuplicate      = "duplicate"       // entry defined twice in a file
+	IssueFemale         = "female"          // female variant only in one of languages
+	IssueSound          = "sound"           // sound file differs between languages
+	IssueMissingFile    = "missing-file"    // TRA file of the base language absent
+	IssueMissingInclude = "missing-include" // included TRA file does not exist
+)
+
+// AuditIssue is a single problem found by Audit. File is a source or TRA
+// path relative to the mod folder.
+type AuditIssue struct {
+	Kind     string `json:"kind"`
+	Language string `json:"language"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Id       uint32 `json:"id"`
+	Message  string `json:"message"`
+}
+
+// TraLanguage is a tra/<language>/ folder with its parsed files keyed by
+// lower-case slash-separated path relative to the folder.
+type TraLanguage struct {
+	Name  string
+	Dir   string
+	Files map[string]*parser.TraFile
+	Paths map[string]string // original relative paths by key
+}
+
+type AuditOptions struct {
+	// Folder with language subfolders. If empty, the first folder named
+	// "tra" in the mod folder is used.
+	TraDir string
+	// Language the others are compared with. If empty, "english" is used
+	// if present, otherwise the first language.
+	BaseLanguage string
+}
+
+// ModAudit is the result of Audit.
+type ModAudit struct {
+	Sources   []SourceFile
+	Languages []*TraLanguage
+	Base      string
+	Issues    []AuditIssue
+}
+
+// Finds the first folder named "tra" (case-insensitive) in the mod folder.
+func findTraDir(modDir string) (string, error) {
+	var found string
+	err := filepath.WalkDir(modDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.EqualFold(d.Name(), "tra") && path != modDir {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("no tra folder found in %s", modDir)
+	}
+	return found, nil
+}
+
+// Loads WeiDU sources of the mod folder.
+func loadSources(modDir string) ([]SourceFile, error) {
+	var sources []SourceFile
+	err := filepath.WalkDir(modDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !slices.Contains(SourceExtensions, strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", path, err)
+		}
+		rel, _ := filepath.Rel(modDir, path)
+		sources = append(sources, ScanSource(filepath.ToSlash(rel), string(data)))
+		return nil
+	})
+	return sources, err
+}
+
+// Loads TRA files of every language subfolder.
+func LoadLanguages(traDir string) ([]*TraLanguage, error) {
+	dirEntries, err := os.ReadDir(traDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tra folder: %w", err)
+	}
+
+	var languages []*TraLanguage
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+		lang := &TraLanguage{
+			Name:  dirEntry.Name(),
+			Dir:   filepath.Join(traDir, dirEntry.Name()),
+			Files: make(map[string]*parser.TraFile),
+			Paths: make(map[string]string),
+		}
+		err := filepath.WalkDir(lang.Dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".tra") {
+				return nil
+			}
+			tf, err := parser.ParseTraFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			rel, _ := filepath.Rel(lang.Dir, path)
+			key := strings.ToLower(filepath.ToSlash(rel))
+			lang.Files[key] = tf
+			lang.Paths[key] = filepath.ToSlash(rel)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		languages = append(languages, lang)
+	}
+	return languages, nil
+}
+
+// Returns the keys of the TRA files a source resolves references with:
+// the files with the same base name for .d and .baf sources (the AUTO_TRA
+// convention), otherwise nil meaning all files of the language.
+func sourceScope(src SourceFile, lang *TraLanguage) []string {
+	ext := strings.ToLower(filepath.Ext(src.Path))
+	if ext != ".d" && ext != ".baf" {
+		return nil
+	}
+	base := strings.ToLower(strings.TrimSuffix(filepath.Base(src.Path), filepath.Ext(src.Path)))
+
+	var scope []string
+	for key := range lang.Files {
+		if strings.TrimSuffix(filepath.Base(key), ".tra") == base {
+			scope = append(scope, key)
+		}
+	}
+	return scope
+}
+
+// Cross-checks TRA references of the mod sources against every language.
+func Audit(modDir string, opts AuditOptions) (*ModAudit, error) {
+	traDir := opts.TraDir
+	if traDir == "" {
+		var err error
+		if traDir, err = findTraDir(modDir); err != nil {
+			return nil, err
+		}
+	}
+
+	sources, err := loadSources(modDir)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := LoadLanguages(traDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no language folders in %s", traDir)
+	}
+
+	audit := &ModAudit{Sources: sources, Languages: languages, Base: opts.BaseLanguage}
+	if audit.Base == "" {
+		audit.Base = languages[0].Name
+		for _, lang := range languages {
+			if strings.EqualFold(lang.Name, "english") {
+				audit.Base = lang.Name
+			}
+		}
+	}
+	baseIdx := slices.IndexFunc(languages, func(lang *TraLanguage) bool { return lang.Name == audit.Base })
+	if baseIdx < 0 {
+		return nil, fmt.Errorf("base language %s not found in %s", audit.Base, traDir)
+	}
+	base := languages[baseIdx]
+
+	relTra := func(lang *TraLanguage, key string) string {
+		rel, _ := filepath.Rel(modDir, filepath.Join(lang.Dir, key))
+		return filepath.ToSlash(rel)
+	}
+
+	for _, lang := range languages {
+		ids := make(map[string]map[uint32]*parser.TraEntry, len(lang.Files))
+		used := make(map[string]map[uint32]bool, len(lang.Files))
+		for key, tf := range lang.Files {
+			ids[key] = tf.ToMap()
+			used[key] = make(map[uint32]bool)
+
+			seen := make(map[uint32]bool)
+			for _, entry := range tf.Entries {
+				if seen[entry.ID] {
+					audit.Issues = append(audit.Issues, AuditIssue{IssueDuplicate, lang.Name, relTra(lang, key), 0, entry.ID, "defined more than once"})
+				}
+				seen[entry.ID] = true
+			}
+		}
+
+		for _, src := range sources {
+			scope := sourceScope(src, lang)
+			if scope == nil {
+				scope = slices.Collect(maps.Keys(lang.Files))
+			}
+
+			for _, ref := range src.Refs {
+				found := false
+				for _, key := range scope {
+					if _, ok := ids[key][ref.Id]; ok {
+						used[key][ref.Id] = true
+						found = true
+					}
+				}
+				if !found {
+					audit.Issues = append(audit.Issues, AuditIssue{IssueMissing, lang.Name, src.Path, ref.Line, ref.Id, "referenced entry not found"})
+				}
+			}
+
+			for _, include := range src.Includes {
+				path := strings.NewReplacer("%s", lang.Name, "%LANGUAGE%", lang.Name, "%MOD_FOLDER%", filepath.Base(modDir)).Replace(include.Path)
+				if strings.Contains(path, "%") || includeExists(modDir, path) {
+					continue
+				}
+				audit.Issues = append(audit.Issues, AuditIssue{IssueMissingInclude, lang.Name, src.Path, include.Line, 0, fmt.Sprintf("%s path %s not found", include.Directive, path)})
+			}
+		}
+
+		for key, fileIds := range ids {
+			for id := range fileIds {
+				if !used[key][id] {
+					audit.Issues = append(audit.Issues, AuditIssue{IssueUnused, lang.Name, relTra(lang, key), 0, id, "not referenced by any source"})
+				}
+			}
+		}
+
+		if lang == base {
+			continue
+		}
+		for key, baseFile := range base.Files {
+			if _, ok := lang.Files[key]; !ok {
+				audit.Issues = append(audit.Issues, AuditIssue{IssueMissingFile, lang.Name, relTra(lang, key), 0, 0, "file of " + base.Name + " not found"})
+				continue
+			}
+			entries := ids[key]
+			for _, baseEntry := range baseFile.Entries {
+				entry, ok := entries[baseEntry.ID]
+				if !ok {
+					continue
+				}
+				if (baseEntry.FemaleText == "") != (entry.FemaleText == "") {
+					message := "female variant missing"
+					if baseEntry.FemaleText == "" {
+						message = "female variant not in " + base.Name
+					}
+					audit.Issues = append(audit.Issues, AuditIssue{IssueFemale, lang.Name, relTra(lang, key), 0, entry.ID, message})
+				}
+				if !strings.EqualFold(baseEntry.SoundFile, entry.SoundFile) {
+					audit.Issues = append(audit.Issues, AuditIssue{IssueSound, lang.Name, relTra(lang, key), 0, entry.ID,
+						fmt.Sprintf("sound [%s], in %s [%s]", entry.SoundFile, base.Name, baseEntry.SoundFile)})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(audit.Issues, func(a, b AuditIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Language, b.Language),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Id, b.Id),
+		)
+	})
+	return audit, nil
+}
+
+// Checks an include path relative to the game folder (the parent of the
+// mod folder, as WeiDU resolves it) or to the mod folder.
+func includeExists(modDir, path string) bool {
+	for _, dir := range []string{filepath.Dir(modDir), modDir} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	game := t.TempDir()
+	files := map[string]string{
+		"mymod/setup-mymod.tp2": `BACKUP ~mymod/backup~
+LANGUAGE ~English~ ~english~ ~mymod/tra/english/setup.tra~
+LANGUAGE ~Ukrainian~ ~ukrainian~ ~mymod/tra/%s/setup.tra~ ~mymod/tra/%s/extra.tra~
+BEGIN @1 // @99 in a comment
+COMPILE ~mymod/d/npc.d~ USING ~mymod/tra/%LANGUAGE%/npc.tra~
+PRINT ~mail@2~`,
+		"mymod/d/npc.d": `BEGIN NPC
+IF ~True()~ THEN BEGIN a SAY @0 IF ~~ THEN REPLY @1 EXIT END`,
+		"mymod/tra/english/setup.tra":    "@1 = ~My mod~\n@2 = ~Unused~\n",
+		"mymod/tra/english/npc.tra":      "@0 = ~Hi~ [NPC01]\n@1 = ~Bye~\n",
+		"mymod/tra/ukrainian/setup.tra":  "@1 = ~Мій мод~\n@1 = ~Дубль~\n",
+		"mymod/tra/ukrainian/extra.tra":  "@5 = ~Extra~\n",
+		"mymod/tra/ukrainian/npc.tra":    "@0 = ~Привіт~ ~Привіт~\n",
+		"mymod/tra/ukrainian/notes.txt":  "not a tra",
+		"mymod/backup/ignored/README.md": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(game, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	audit, err := Audit(filepath.Join(game, "mymod"), AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if audit.Base != "english" {
+		t.Errorf("Audit() base = %s, want english", audit.Base)
+	}
+
+	var got []string
+	for _, issue := range audit.Issues {
+		got = append(got, fmt.Sprintf("%s %s %s @%d", issue.Kind, issue.Language, issue.File, issue.Id))
+	}
+	want := []string{
+		"duplicate ukrainian tra/ukrainian/setup.tra @1",
+		"female ukrainian tra/ukrainian/npc.tra @0",
+		"missing ukrainian d/npc.d @1",
+		"missing-include english setup-mymod.tp2 @0",
+		"sound ukrainian tra/ukrainian/npc.tra @0",
+		"unused english tra/english/setup.tra @2",
+		"unused ukrainian tra/ukrainian/extra.tra @5",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Audit() issues =\n%v\nwant\n%v", got, want)
+	}
+}
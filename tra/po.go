@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// Suffix of the msgctxt of female variants: "123 female".
+const PoFemaleSuffix = " female"
+
+// PoMessage is a single gettext PO message.
+type PoMessage struct {
+	Comments   []string // translator comments (#)
+	References []string // references (#:)
+	Flags      []string // flags (#,), e.g. fuzzy
+	Context    string
+	Id         string
+	Str        string
+}
+
+func (m PoMessage) IsFuzzy() bool {
+	return slices.Contains(m.Flags, "fuzzy")
+}
+
+// Returns the msgctxt of the TRA entry or its female variant.
+func PoContext(id uint32, female bool) string {
+	ctx := strconv.FormatUint(uint64(id), 10)
+	if female {
+		ctx += PoFemaleSuffix
+	}
+	return ctx
+}
+
+// Parses the msgctxt of a message made by PoContext.
+func ParsePoContext(ctx string) (uint32, bool, error) {
+	female := strings.HasSuffix(ctx, PoFemaleSuffix)
+	id, err := strconv.ParseUint(strings.TrimSuffix(ctx, PoFemaleSuffix), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("msgctxt %q is not a TRA ID", ctx)
+	}
+	return uint32(id), female, nil
+}
+
+// Converts the template TRA entries to PO messages: the template text is
+// the msgid and the translation text, if given, is the msgstr. Female
+// variants are separate messages; with allFemale every entry gets one,
+// even if the template has no female variant. Entries with empty text are
+// skipped.
+func TraToPo(template, translation *parser.TraFile, allFemale bool) []PoMessage {
+	var translated map[uint32]*parser.TraEntry
+	if translation != nil {
+		translated = translation.ToMap()
+	}
+
+	var messages []PoMessage
+	for _, entry := range template.Entries {
+		if entry.MaleText == "" {
+			continue
+		}
+
+		var references []string
+		if entry.SoundFile != "" {
+			references = []string{entry.SoundFile}
+		}
+
+		male := PoMessage{References: references, Context: PoContext(entry.ID, false), Id: entry.MaleText}
+		female := PoMessage{References: references, Context: PoContext(entry.ID, true), Id: entry.FemaleText}
+		if female.Id == "" {
+			female.Id = entry.MaleText
+		}
+		if t, ok := translated[entry.ID]; ok {
+			male.Str = t.MaleText
+			female.Str = t.FemaleText
+		}
+
+		messages = append(messages, male)
+		if entry.FemaleText != "" || allFemale {
+			messages = append(messages, female)
+		}
+	}
+	return messages
+}
+
+// Applies translated PO messages to the template entries. Untranslated and
+// fuzzy messages keep the template text. Returns an error listing the
+// messages whose IDs are not in the template.
+func PoToTra(template *parser.TraFile, messages []PoMessage) (int, error) {
+	entries := make(map[uint32][]*parser.TraEntry, len(template.Entries))
+	for i := range template.Entries {
+		entry := &template.Entries[i]
+		entries[entry.ID] = append(entries[entry.ID], entry)
+	}
+
+	var unknown []string
+	applied := 0
+	for _, msg := range messages {
+		id, female, err := ParsePoContext(msg.Context)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := entries[id]; !ok {
+			unknown = append(unknown, msg.Context)
+			continue
+		}
+		if msg.Str == "" || msg.IsFuzzy() {
+			continue
+		}
+
+		for _, entry := range entries[id] {
+			if female {
+				entry.FemaleText = msg.Str
+			} else {
+				entry.MaleText = msg.Str
+			}
+		}
+		applied++
+	}
+
+	if len(unknown) > 0 {
+		return 0, fmt.Errorf("%d messages are not in the template: %s", len(unknown), strings.Join(unknown, ", "))
+	}
+	return applied, nil
+}
+
+// Writes PO messages with a header for the given language.
+func WritePo(w io.Writer, project, language string, messages []PoMessage) error {
+	bw := bufio.NewWriter(w)
+
+	header := "Project-Id-Version: " + project + "\n"
+	if language != "" {
+		header += "Language: " + language + "\n"
+	}
+	header += "MIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\nX-Generator: sbt-inf\n"
+	writePoString(bw, "msgid", "")
+	writePoString(bw, "msgstr", header)
+
+	for _, msg := range messages {
+		bw.WriteString("\n")
+		for _, comment := range msg.Comments {
+			fmt.Fprintf(bw, "# %s\n", comment)
+		}
+		if len(msg.References) > 0 {
+			fmt.Fprintf(bw, "#: %s\n", strings.Join(msg.References, " "))
+		}
+		if len(msg.Flags) > 0 {
+			fmt.Fprintf(bw, "#, %s\n", strings.Join(msg.Flags, ", "))
+		}
+		writePoString(bw, "msgctxt", msg.Context)
+		writePoString(bw, "msgid", msg.Id)
+		writePoString(bw, "msgstr", msg.Str)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing PO file: %w", err)
+	}
+	return nil
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// Writes a keyword with a quoted string, splitting multi-line strings
+// after newlines as gettext tools do.
+func writePoString(w *bufio.Writer, keyword, s string) {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		fmt.Fprintf(w, "%s \"%s\"\n", keyword, poEscaper.Replace(s))
+		return
+	}
+
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			fmt.Fprintf(w, "\"%s\"\n", poEscaper.Replace(line))
+		}
+	}
+}
+
+// Reads messages of a PO file, skipping the header and obsolete messages.
+// Only the first plural form of plural messages is read.
+func ReadPo(r io.Reader) ([]PoMessage, error) {
+	var messages []PoMessage
+	var msg PoMessage
+	var target *string
+	hasStr := false
+	lineNum := 0
+
+	// Ends the current message; the header has empty msgid and msgctxt.
+	flush := func() {
+		if hasStr && !(msg.Id == "" && msg.Context == "") {
+			messages = append(messages, msg)
+		}
+		msg = PoMessage{}
+		target = nil
+		hasStr = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#~"):
+			continue
+		case strings.HasPrefix(line, "#"):
+			if hasStr {
+				flush()
+			}
+			switch {
+			case strings.HasPrefix(line, "#:"):
+				msg.References = append(msg.References, strings.Fields(line[2:])...)
+			case strings.HasPrefix(line, "#,"):
+				for _, flag := range strings.Split(line[2:], ",") {
+					msg.Flags = append(msg.Flags, strings.TrimSpace(flag))
+				}
+			case strings.HasPrefix(line, "# "), line == "#":
+				msg.Comments = append(msg.Comments, strings.TrimPrefix(strings.TrimPrefix(line, "#"), " "))
+			}
+			continue
+		case strings.HasPrefix(line, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", lineNum)
+			}
+			s, err := unquotePo(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			*target += s
+			continue
+		}
+
+		keyword, value, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: unexpected %q", lineNum, line)
+		}
+		s, err := unquotePo(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		switch keyword {
+		case "msgctxt":
+			if hasStr {
+				flush()
+			}
+			msg.Context = s
+			target = &msg.Context
+		case "msgid":
+			if hasStr {
+				flush()
+			}
+			msg.Id = s
+			target = &msg.Id
+		case "msgstr", "msgstr[0]":
+			msg.Str = s
+			target = &msg.Str
+			hasStr = true
+		case "msgid_plural":
+			target = new(string)
+		default:
+			if !strings.HasPrefix(keyword, "msgstr[") {
+				return nil, fmt.Errorf("line %d: unknown keyword %s", lineNum, keyword)
+			}
+			target = new(string)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read PO file: %w", err)
+	}
+	flush()
+
+	return messages, nil
+}
+
+func unquotePo(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+
+	var sb strings.Builder
+	s = s[1 : len(s)-1]
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+func TestPoRoundTrip(t *testing.T) {
+	template, err := parser.ParseTra(strings.NewReader("// Notes\n@1 = ~Hello, \"friend\"~ [SND01]\n@2 = ~Line one\nLine two~ ~She~\n@3 = ~~\n"))
+	if err != nil {
+		t.Fatalf("ParseTra() error = %v", err)
+	}
+
+	var sb strings.Builder
+	if err := WritePo(&sb, "setup.tra", "uk", TraToPo(template, nil, true)); err != nil {
+		t.Fatalf("WritePo() error = %v", err)
+	}
+	po := sb.String()
+	for _, want := range []string{"#: SND01\nmsgctxt \"1\"\nmsgid \"Hello, \\\"friend\\\"\"", "msgctxt \"2 female\"\nmsgid \"She\"", "msgid \"\"\n\"Line one\\n\"\n\"Line two\""} {
+		if !strings.Contains(po, want) {
+			t.Errorf("WritePo() = %s, want to contain %q", po, want)
+		}
+	}
+	if strings.Contains(po, "msgctxt \"3\"") {
+		t.Errorf("WritePo() = %s, want no empty entry", po)
+	}
+
+	// Translate as Poedit would.
+	po = strings.Replace(po, "msgctxt \"1\"\nmsgid \"Hello, \\\"friend\\\"\"\nmsgstr \"\"", "msgctxt \"1\"\nmsgid \"Hello, \\\"friend\\\"\"\nmsgstr \"Привіт, ~друже~\"", 1)
+	po = strings.Replace(po, "msgctxt \"1 female\"\nmsgid \"Hello, \\\"friend\\\"\"\nmsgstr \"\"", "#, fuzzy\nmsgctxt \"1 female\"\nmsgid \"Hello, \\\"friend\\\"\"\nmsgstr \"Привіт, подруго\"", 1)
+	po = strings.Replace(po, "msgctxt \"2 female\"\nmsgid \"She\"\nmsgstr \"\"", "msgctxt \"2 female\"\nmsgid \"She\"\nmsgstr \"\"\n\"Вона\"", 1)
+
+	messages, err := ReadPo(strings.NewReader(po))
+	if err != nil {
+		t.Fatalf("ReadPo() error = %v", err)
+	}
+	if len(messages) != 4 {
+		t.Fatalf("ReadPo() = %+v, want 4 messages", messages)
+	}
+	if messages[1].Str != "Привіт, подруго" || !messages[1].IsFuzzy() {
+		t.Errorf("ReadPo() messages[1] = %+v, want fuzzy translation", messages[1])
+	}
+
+	applied, err := PoToTra(template, messages)
+	if err != nil || applied != 2 {
+		t.Fatalf("PoToTra() = %d, %v, want 2 applied", applied, err)
+	}
+
+	sb.Reset()
+	if err := WriteParsed(&sb, template); err != nil {
+		t.Fatalf("WriteParsed() error = %v", err)
+	}
+	want := "// Notes\n@1 = %Привіт, ~друже~% [SND01]\n@2 = ~Line one\nLine two~ ~Вона~\n@3 = ~~\n"
+	if sb.String() != want {
+		t.Errorf("WriteParsed() = %q, want %q", sb.String(), want)
+	}
+
+	if _, err := PoToTra(template, []PoMessage{{Context: "7", Id: "x", Str: "y"}}); err == nil {
+		t.Errorf("PoToTra() error = nil, want unknown ID error")
+	}
+}
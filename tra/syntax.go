@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// Wraps the text with the preferred delimiter if the text allows it.
+func wrapWithDelimiter(text, delimiter string) string {
+	if delimiter != "" && !strings.Contains(text, delimiter) {
+		return delimiter + text + delimiter
+	}
+	return WrapWithDelimiters(text)
+}
+
+type replacement struct {
+	start, end int
+	text       string
+}
+
+// Formats a parsed entry keeping its original layout. Only the texts and
+// the sound file changed since parsing are re-written; a changed text
+// keeps its original delimiter where possible, but concatenation (^) is
+// not preserved. Entries without syntax are formatted with FormatEntry.
+func FormatEntrySyntax(entry parser.TraEntry) string {
+	syntax := entry.Syntax
+	if syntax == nil {
+		return FormatEntry(entry, 0)
+	}
+
+	male, female, sound := syntax.MaleText, syntax.FemaleText, syntax.SoundFile
+	var replacements []replacement
+
+	if entry.MaleText != male.Value {
+		replacements = append(replacements, replacement{male.Span.Start, male.Span.End, wrapWithDelimiter(entry.MaleText, male.Delimiter)})
+	}
+
+	textEnd := male.Span.End
+	if !female.Span.IsEmpty() {
+		textEnd = female.Span.End
+	}
+
+	if entry.FemaleText != female.Value {
+		switch {
+		case female.Span.IsEmpty():
+			replacements = append(replacements, replacement{male.Span.End, male.Span.End, " " + wrapWithDelimiter(entry.FemaleText, male.Delimiter)})
+		case entry.FemaleText == "":
+			replacements = append(replacements, replacement{male.Span.End, female.Span.End, ""})
+		default:
+			replacements = append(replacements, replacement{female.Span.Start, female.Span.End, wrapWithDelimiter(entry.FemaleText, female.Delimiter)})
+		}
+	}
+
+	if entry.SoundFile != syntax.SoundValue {
+		switch {
+		case sound.IsEmpty():
+			replacements = append(replacements, replacement{textEnd, textEnd, fmt.Sprintf(" [%s]", entry.SoundFile)})
+		case entry.SoundFile == "":
+			replacements = append(replacements, replacement{textEnd, sound.End, ""})
+		default:
+			replacements = append(replacements, replacement{sound.Start, sound.End, fmt.Sprintf("[%s]", entry.SoundFile)})
+		}
+	}
+
+	if len(replacements) == 0 {
+		return syntax.Raw
+	}
+
+	slices.SortStableFunc(replacements, func(a, b replacement) int {
+		return a.start - b.start
+	})
+
+	var sb strings.Builder
+	pos := 0
+	for _, r := range replacements {
+		sb.WriteString(syntax.Raw[pos:r.start])
+		sb.WriteString(r.text)
+		pos = r.end
+	}
+	sb.WriteString(syntax.Raw[pos:])
+	return sb.String()
+}
+
+// Writes a parsed TRA file keeping its comments, whitespace, entry order
+// and formatting, so that only the changed entries differ from the
+// original. Entries appended to tf.Entries after parsing are written at the
+// end. A file that was not parsed is written as with Write.
+func WriteParsed(w io.Writer, tf *parser.TraFile) error {
+	if len(tf.Nodes) == 0 {
+		return Write(w, tf.Entries)
+	}
+
+	written := make([]bool, len(tf.Entries))
+	var sb strings.Builder
+	for _, node := range tf.Nodes {
+		if node.Kind != parser.TraEntryNode {
+			sb.WriteString(node.Raw)
+			continue
+		}
+		if node.Entry >= len(tf.Entries) {
+			continue
+		}
+		sb.WriteString(FormatEntrySyntax(tf.Entries[node.Entry]))
+		written[node.Entry] = true
+	}
+
+	for i, entry := range tf.Entries {
+		if written[i] {
+			continue
+		}
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(FormatEntry(entry, 0))
+		sb.WriteString("\n")
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("error writing TRA file: %w", err)
+	}
+	return nil
+}
+
+func WriteParsedFile(path string, tf *parser.TraFile) error {
+	outputDir := filepath.Dir(path)
+	if outputDir != "" && outputDir != "." {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("unable to create output directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create output file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteParsed(file, tf)
+}
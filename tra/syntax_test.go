@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+const sampleTra = `// Translator notes: keep the rhymes.
+/* Block
+   comment */
+
+@0001   = ~Hello~ ^
+          ~ there~ // greeting
+@2 = %Half off~% [SND01]
+!3=~~~~~~a~b~~~~~ ~Her~
+@10 = "Quote ~x~"
+`
+
+func TestWriteParsedRoundTrip(t *testing.T) {
+	tf, err := parser.ParseTra(strings.NewReader(sampleTra))
+	if err != nil {
+		t.Fatalf("ParseTra() error = %v", err)
+	}
+	if len(tf.Entries) != 4 || tf.Entries[0].MaleText != "Hello there" {
+		t.Fatalf("ParseTra() entries = %+v", tf.Entries)
+	}
+
+	var sb strings.Builder
+	if err := WriteParsed(&sb, tf); err != nil {
+		t.Fatalf("WriteParsed() error = %v", err)
+	}
+	if sb.String() != sampleTra {
+		t.Errorf("WriteParsed() = %q, want %q", sb.String(), sampleTra)
+	}
+}
+
+func TestWriteParsedChanges(t *testing.T) {
+	tf, err := parser.ParseTra(strings.NewReader(sampleTra))
+	if err != nil {
+		t.Fatalf("ParseTra() error = %v", err)
+	}
+
+	tf.Entries[0].MaleText = "Привіт"
+	tf.Entries[1].MaleText = "Знижка~"
+	tf.Entries[1].SoundFile = ""
+	tf.Entries[2].FemaleText = ""
+	tf.Entries[3].FemaleText = "Вона"
+	tf.Entries[3].SoundFile = "SND02"
+	tf.Entries = append(tf.Entries, parser.TraEntry{ID: 11, MaleText: "New"})
+
+	var sb strings.Builder
+	if err := WriteParsed(&sb, tf); err != nil {
+		t.Fatalf("WriteParsed() error = %v", err)
+	}
+
+	want := `// Translator notes: keep the rhymes.
+/* Block
+   comment */
+
+@0001   = ~Привіт~ // greeting
+@2 = %Знижка~%
+!3=~~~~~~a~b~~~~~
+@10 = "Quote ~x~" "Вона" [SND02]
+@11 = ~New~
+`
+	if sb.String() != want {
+		t.Errorf("WriteParsed() = %q, want %q", sb.String(), want)
+	}
+}
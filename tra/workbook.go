@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+// WorkbookRow is a TRA entry in all languages of a workbook.
+type WorkbookRow struct {
+	File    string // path relative to the language folder
+	Id      uint32
+	Entries []*parser.TraEntry // by language, nil if missing in it
+}
+
+// Returns true if the entry is missing in some language.
+func (r WorkbookRow) IsIncomplete() bool {
+	return slices.Contains(r.Entries, nil)
+}
+
+// Builds a row for every TRA entry of the languages, sorted by file and ID.
+// Files of different languages are matched regardless of the case of their
+// paths; the File of a row is the path of the first language that has it.
+// The last definition of an ID in a file wins as in WeiDU.
+func WorkbookRows(languages []*TraLanguage) []WorkbookRow {
+	type rowKey struct {
+		key string
+		id  uint32
+	}
+
+	rows := make(map[rowKey]*WorkbookRow)
+	for i, lang := range languages {
+		for key, tf := range lang.Files {
+			for id, entry := range tf.ToMap() {
+				row := rows[rowKey{key, id}]
+				if row == nil {
+					row = &WorkbookRow{
+						File:    lang.Paths[key],
+						Id:      id,
+						Entries: make([]*parser.TraEntry, len(languages)),
+					}
+					rows[rowKey{key, id}] = row
+				}
+				row.Entries[i] = entry
+			}
+		}
+	}
+
+	result := make([]WorkbookRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	slices.SortFunc(result, func(a, b WorkbookRow) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.File), strings.ToLower(b.File)), cmp.Compare(a.Id, b.Id))
+	})
+	return result
+}
+
+// WorkbookText is the text, female variant and sound file of a TRA entry
+// in a language column of a workbook.
+type WorkbookText struct {
+	Id         uint32
+	MaleText   string
+	FemaleText string
+	SoundFile  string
+}
+
+// Applies the workbook texts of a language to the TRA file: all entries
+// with the ID are replaced, comments and layout of the file are kept.
+// A text with no male text, female variant and sound file is not added
+// if the file does not have the ID.
+func MergeWorkbookTexts(tf *parser.TraFile, texts []WorkbookText) {
+	for _, text := range texts {
+		found := false
+		for i := range tf.Entries {
+			if entry := &tf.Entries[i]; entry.ID == text.Id {
+				entry.MaleText, entry.FemaleText, entry.SoundFile = text.MaleText, text.FemaleText, text.SoundFile
+				found = true
+			}
+		}
+		if !found && (text.MaleText != "" || text.FemaleText != "" || text.SoundFile != "") {
+			tf.Entries = append(tf.Entries, parser.TraEntry{
+				ID:         text.Id,
+				MaleText:   text.MaleText,
+				FemaleText: text.FemaleText,
+				SoundFile:  text.SoundFile,
+			})
+		}
+	}
+}
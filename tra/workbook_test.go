@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package tra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbtlocalization/sbt-infinity/parser"
+)
+
+func TestWorkbookRows(t *testing.T) {
+	parse := func(content string) *parser.TraFile {
+		tf, err := parser.ParseTra(strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("ParseTra() error = %v", err)
+		}
+		return tf
+	}
+
+	languages := []*TraLanguage{
+		{
+			Name:  "english",
+			Files: map[string]*parser.TraFile{"setup.tra": parse("@2 = ~Two~\n@1 = ~One~\n@1 = ~First~\n")},
+			Paths: map[string]string{"setup.tra": "Setup.tra"},
+		},
+		{
+			Name: "ukrainian",
+			Files: map[string]*parser.TraFile{
+				"setup.tra": parse("@1 = ~Один~ ~Одна~ [UK01]\n"),
+				"extra.tra": parse("@5 = ~П'ять~\n"),
+			},
+			Paths: map[string]string{"setup.tra": "setup.tra", "extra.tra": "extra.tra"},
+		},
+	}
+
+	rows := WorkbookRows(languages)
+
+	want := []struct {
+		file       string
+		id         uint32
+		english    string
+		ukrainian  string
+		incomplete bool
+	}{
+		{"extra.tra", 5, "", "П'ять", true},
+		{"Setup.tra", 1, "First", "Один", false},
+		{"Setup.tra", 2, "Two", "", true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("WorkbookRows() = %d rows, want %d", len(rows), len(want))
+	}
+	text := func(entry *parser.TraEntry) string {
+		if entry == nil {
+			return ""
+		}
+		return entry.MaleText
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.File != w.file || row.Id != w.id || text(row.Entries[0]) != w.english || text(row.Entries[1]) != w.ukrainian || row.IsIncomplete() != w.incomplete {
+			t.Errorf("rows[%d] = %s @%d %q %q incomplete %v, want %s @%d %q %q incomplete %v", i,
+				row.File, row.Id, text(row.Entries[0]), text(row.Entries[1]), row.IsIncomplete(),
+				w.file, w.id, w.english, w.ukrainian, w.incomplete)
+		}
+	}
+	if entry := rows[1].Entries[1]; entry.FemaleText != "Одна" || entry.SoundFile != "UK01" {
+		t.Errorf("rows[1] ukrainian = %+v, want female variant and sound", entry)
+	}
+}
+
+func TestMergeWorkbookTexts(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		texts    []WorkbookText
+		want     string
+	}{
+		{
+			name:     "replaced with comments kept",
+			original: "// Greetings\n@1 = ~Hello~\n@2 = ~Bye~ [BYE]\n",
+			texts:    []WorkbookText{{Id: 1, MaleText: "Привіт"}, {Id: 2, MaleText: "Бувай", SoundFile: "BYE"}},
+			want:     "// Greetings\n@1 = ~Привіт~\n@2 = ~Бувай~ [BYE]\n",
+		},
+		{
+			name:     "female variant added",
+			original: "@1 = ~Ready~\n",
+			texts:    []WorkbookText{{Id: 1, MaleText: "Готовий", FemaleText: "Готова"}},
+			want:     "@1 = ~Готовий~ ~Готова~\n",
+		},
+		{
+			name:     "new entry appended",
+			original: "@1 = ~One~\n",
+			texts:    []WorkbookText{{Id: 1, MaleText: "One"}, {Id: 2, MaleText: "Два"}},
+			want:     "@1 = ~One~\n@2 = ~Два~\n",
+		},
+		{
+			name:     "empty entry not added",
+			original: "@1 = ~One~\n",
+			texts:    []WorkbookText{{Id: 2}},
+			want:     "@1 = ~One~\n",
+		},
+		{
+			name:     "existing entry emptied",
+			original: "@1 = ~One~\n",
+			texts:    []WorkbookText{{Id: 1}},
+			want:     "@1 = ~~\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseTra(strings.NewReader(tt.original))
+			if err != nil {
+				t.Fatalf("ParseTra() error = %v", err)
+			}
+
+			MergeWorkbookTexts(tf, tt.texts)
+
+			var sb strings.Builder
+			if err := WriteParsed(&sb, tf); err != nil {
+				t.Fatalf("WriteParsed() error = %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("MergeWorkbookTexts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
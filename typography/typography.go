@@ -0,0 +1,473 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package typography normalizes quotes, dashes, spaces and look-alike
+// letters in translated texts.
+package typography
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Options toggles the normalization rules. Language selects the quote style
+// and the short words bound with non-breaking spaces; without a known
+// language quotes and short words are left as is.
+type Options struct {
+	Language          string `toml:"language"`
+	Spaces            bool   `toml:"spaces"`
+	Ellipsis          bool   `toml:"ellipsis"`
+	Dashes            bool   `toml:"dashes"`
+	Quotes            bool   `toml:"quotes"`
+	Apostrophes       bool   `toml:"apostrophes"`
+	NonBreakingSpaces bool   `toml:"non_breaking_spaces"`
+	Homoglyphs        bool   `toml:"homoglyphs"`
+}
+
+// Returns options with all rules enabled except non-breaking spaces,
+// which not every game font is able to render.
+func DefaultOptions() Options {
+	return Options{
+		Spaces:      true,
+		Ellipsis:    true,
+		Dashes:      true,
+		Quotes:      true,
+		Apostrophes: true,
+		Homoglyphs:  true,
+	}
+}
+
+const (
+	RuleSpaces            = "spaces"
+	RuleEllipsis          = "ellipsis"
+	RuleDashes            = "dashes"
+	RuleQuotes            = "quotes"
+	RuleApostrophes       = "apostrophes"
+	RuleNonBreakingSpaces = "non-breaking spaces"
+	RuleHomoglyphs        = "homoglyphs"
+)
+
+type quoteStyle struct {
+	open, close           string
+	innerOpen, innerClose string
+	// Non-breaking spaces inside the quotes (French style).
+	spaced bool
+}
+
+var quoteStyles = map[string]quoteStyle{
+	"uk": {"«", "»", "„", "“", false},
+	"ru": {"«", "»", "„", "“", false},
+	"be": {"«", "»", "„", "“", false},
+	"en": {"“", "”", "‘", "’", false},
+	"de": {"„", "“", "‚", "‘", false},
+	"pl": {"„", "”", "«", "»", false},
+	"fr": {"«", "»", "“", "”", true},
+}
+
+// Short words that should not stay at the end of a line.
+var shortWords = map[string][]string{
+	"uk": {"а", "в", "з", "і", "й", "о", "у", "та", "що", "до", "на", "не", "по", "це", "як"},
+	"ru": {"а", "в", "и", "к", "о", "с", "у", "во", "до", "за", "из", "ко", "на", "не", "но", "от", "по", "со"},
+	"be": {"а", "в", "з", "і", "й", "у", "ў", "да", "на", "не", "па"},
+	"en": {"a", "an", "I", "of", "to", "in", "on", "at"},
+	"pl": {"a", "i", "o", "u", "w", "z"},
+}
+
+// Result describes the changes made to a text.
+type Result struct {
+	Text  string
+	Rules []string
+	// Mixed-script words that could not be fixed automatically.
+	Warnings []string
+}
+
+func (r *Result) Changed() bool {
+	return len(r.Rules) > 0
+}
+
+// Normalizer applies the enabled rules to texts.
+type Normalizer struct {
+	opts     Options
+	style    quoteStyle
+	hasStyle bool
+	short    map[string]struct{}
+}
+
+func New(opts Options) *Normalizer {
+	lang := strings.ToLower(opts.Language)
+	if idx := strings.IndexAny(lang, "_-"); idx >= 0 {
+		lang = lang[:idx]
+	}
+
+	style, hasStyle := quoteStyles[lang]
+
+	short := make(map[string]struct{})
+	for _, word := range shortWords[lang] {
+		short[word] = struct{}{}
+		short[capitalizeWord(word)] = struct{}{}
+	}
+
+	return &Normalizer{opts: opts, style: style, hasStyle: hasStyle, short: short}
+}
+
+// Reports whether the language of the options is known, so quotes can be
+// normalized.
+func (n *Normalizer) HasLanguage() bool {
+	return n.hasStyle
+}
+
+// Engine tokens like <CHARNAME> and dice like 1d6-1, kept as they are.
+var protected = regexp.MustCompile(`<[^<>\s]+>|\b\d+[dD]\d+(?:[+-]\d+)?\b`)
+
+// First rune of the private use area, standing for protected parts of the
+// text while the rules are applied.
+const maskBase = 0xE000
+
+// Replaces protected parts of the text with private use runes. They are
+// neither letters, digits nor spaces, like the brackets of tokens.
+func mask(s string) (string, []string) {
+	var parts []string
+	masked := protected.ReplaceAllStringFunc(s, func(part string) string {
+		parts = append(parts, part)
+		return string(rune(maskBase + len(parts) - 1))
+	})
+	return masked, parts
+}
+
+func unmask(s string, parts []string) string {
+	if len(parts) == 0 {
+		return s
+	}
+	pairs := make([]string, 0, 2*len(parts))
+	for i, part := range parts {
+		pairs = append(pairs, string(rune(maskBase+i)), part)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// Normalizes the text. Engine tokens like <CHARNAME> and dice like 1d6-1
+// are never changed.
+func (n *Normalizer) Normalize(s string) Result {
+	masked, parts := mask(s)
+	result := Result{Text: masked}
+
+	apply := func(enabled bool, rule string, f func(string) string) {
+		if !enabled {
+			return
+		}
+		if changed := f(result.Text); changed != result.Text {
+			result.Text = changed
+			result.Rules = append(result.Rules, rule)
+		}
+	}
+
+	apply(n.opts.Spaces, RuleSpaces, normalizeSpaces)
+	apply(n.opts.Ellipsis, RuleEllipsis, normalizeEllipsis)
+	apply(n.opts.Dashes, RuleDashes, normalizeDashes)
+	apply(n.opts.Quotes && n.hasStyle, RuleQuotes, n.normalizeQuotes)
+	apply(n.opts.Apostrophes, RuleApostrophes, normalizeApostrophes)
+	apply(n.opts.NonBreakingSpaces, RuleNonBreakingSpaces, n.bindShortWords)
+
+	if n.opts.Homoglyphs {
+		fixed, unresolved := fixHomoglyphs(result.Text)
+		if fixed != result.Text {
+			result.Text = fixed
+			result.Rules = append(result.Rules, RuleHomoglyphs)
+		}
+		result.Warnings = unresolved
+	}
+
+	result.Text = unmask(result.Text, parts)
+	return result
+}
+
+var (
+	multipleSpaces  = regexp.MustCompile(`[ \t]{2,}`)
+	trailingSpaces  = regexp.MustCompile(`[ \t]+\n`)
+	threeDots       = regexp.MustCompile(`(^|[^.])\.\.\.([^.]|$)`)
+	spacedHyphen    = regexp.MustCompile(`(\S) +(?:-|--|–) +`)
+	lineStartHyphen = regexp.MustCompile(`(?m)^(?:-|--|–) +`)
+	doubleHyphen    = regexp.MustCompile(`(\pL)--(\pL)`)
+	numberRange     = regexp.MustCompile(`(\d)-(\d)`)
+	innerApostrophe = regexp.MustCompile(`(\pL)['ʼ‘](\pL)`)
+)
+
+func normalizeSpaces(s string) string {
+	s = multipleSpaces.ReplaceAllString(s, " ")
+	return trailingSpaces.ReplaceAllString(s, "\n")
+}
+
+func normalizeEllipsis(s string) string {
+	// The pattern consumes the neighbours, so repeat for adjacent matches.
+	for {
+		replaced := threeDots.ReplaceAllString(s, "$1…$2")
+		if replaced == s {
+			return s
+		}
+		s = replaced
+	}
+}
+
+func normalizeDashes(s string) string {
+	s = spacedHyphen.ReplaceAllString(s, "$1 — ")
+	s = lineStartHyphen.ReplaceAllString(s, "— ")
+	s = doubleHyphen.ReplaceAllString(s, "$1—$2")
+	return numberRange.ReplaceAllString(s, "$1–$2")
+}
+
+func normalizeApostrophes(s string) string {
+	return innerApostrophe.ReplaceAllString(s, "$1’$2")
+}
+
+func isDoubleQuote(r rune) bool {
+	switch r {
+	case '"', '“', '”', '„', '«', '»':
+		return true
+	default:
+		return false
+	}
+}
+
+// Re-pairs double quotes of any kind using the quote style of the language.
+// A quote is opening if it follows a space, a bracket, a dash or another
+// opening quote. Quotes inside quotes use the inner style. If the quotes
+// are unbalanced, the text is left as is.
+func (n *Normalizer) normalizeQuotes(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	depth := 0
+
+	for i, r := range runes {
+		if !isDoubleQuote(r) {
+			b.WriteRune(r)
+			continue
+		}
+
+		opening := i == 0 || unicode.IsSpace(runes[i-1]) || strings.ContainsRune("([{—–-/", runes[i-1]) ||
+			(isDoubleQuote(runes[i-1]) && i+1 < len(runes) && unicode.IsLetter(runes[i+1]))
+		if r == '«' || r == '„' {
+			opening = true
+		} else if r == '»' {
+			opening = false
+		}
+
+		if opening {
+			depth++
+			if depth == 1 {
+				b.WriteString(n.style.open)
+				if n.style.spaced {
+					b.WriteRune(' ')
+				}
+			} else {
+				b.WriteString(n.style.innerOpen)
+			}
+		} else {
+			if depth == 0 {
+				return s
+			}
+			if depth == 1 {
+				if n.style.spaced {
+					b.WriteRune(' ')
+				}
+				b.WriteString(n.style.close)
+			} else {
+				b.WriteString(n.style.innerClose)
+			}
+			depth--
+		}
+	}
+
+	if depth != 0 {
+		return s
+	}
+
+	result := b.String()
+	if n.style.spaced {
+		// Drop regular spaces doubled by the non-breaking ones.
+		result = strings.ReplaceAll(result, "  ", " ")
+		result = strings.ReplaceAll(result, "  ", " ")
+	}
+	return result
+}
+
+// Replaces the space after short words and before dashes with a non-breaking one.
+func (n *Normalizer) bindShortWords(s string) string {
+	s = strings.ReplaceAll(s, " —", " —")
+
+	runes := []rune(s)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if unicode.IsLetter(r) {
+			continue
+		}
+		if r == ' ' && i > start {
+			if _, ok := n.short[string(runes[start:i])]; ok {
+				runes[i] = ' '
+			}
+		}
+		start = i + 1
+	}
+	return string(runes)
+}
+
+// Latin letters looking like Cyrillic ones, and the other way around.
+var (
+	latinToCyrillic = map[rune]rune{
+		'a': 'а', 'c': 'с', 'e': 'е', 'i': 'і', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+		'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'I': 'І', 'K': 'К', 'M': 'М',
+		'O': 'О', 'P': 'Р', 'T': 'Т', 'X': 'Х',
+	}
+	cyrillicToLatin = func() map[rune]rune {
+		m := make(map[rune]rune, len(latinToCyrillic))
+		for latin, cyrillic := range latinToCyrillic {
+			m[cyrillic] = latin
+		}
+		return m
+	}()
+)
+
+// Fixes words mixing Latin and Cyrillic letters by replacing look-alike
+// letters of the minority script. Returns words that can not be fixed.
+func fixHomoglyphs(s string) (string, []string) {
+	var unresolved []string
+	runes := []rune(s)
+
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		latin, cyrillic := 0, 0
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsMark(runes[end])) {
+			switch {
+			case unicode.Is(unicode.Latin, runes[end]):
+				latin++
+			case unicode.Is(unicode.Cyrillic, runes[end]):
+				cyrillic++
+			}
+			end++
+		}
+
+		if latin > 0 && cyrillic > 0 {
+			table, from := latinToCyrillic, unicode.Latin
+			if latin > cyrillic {
+				table, from = cyrillicToLatin, unicode.Cyrillic
+			}
+
+			word := slices.Clone(runes[start:end])
+			fixable := true
+			for i, r := range word {
+				if !unicode.Is(from, r) {
+					continue
+				}
+				if replacement, ok := table[r]; ok {
+					word[i] = replacement
+				} else {
+					fixable = false
+				}
+			}
+
+			if fixable {
+				copy(runes[start:end], word)
+			} else {
+				unresolved = append(unresolved, string(runes[start:end]))
+			}
+		}
+
+		start = end
+	}
+
+	return string(runes), unresolved
+}
+
+func capitalizeWord(word string) string {
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// Change records a text modified by the normalizer.
+type Change struct {
+	Id       uint32   `json:"id"`
+	Rules    []string `json:"rules"`
+	Warnings []string `json:"warnings,omitempty"`
+	Before   string   `json:"before"`
+	After    string   `json:"after"`
+}
+
+// Normalizes male and female variants of the text joined with the separator
+// one by one, so the quotes of one variant are not paired with the other.
+func (n *Normalizer) NormalizeVariants(s, separator string) Result {
+	if separator == "" || !strings.Contains(s, separator) {
+		return n.Normalize(s)
+	}
+
+	parts := strings.Split(s, separator)
+	result := Result{}
+	for i, part := range parts {
+		partResult := n.Normalize(part)
+		parts[i] = partResult.Text
+		for _, rule := range partResult.Rules {
+			if !slices.Contains(result.Rules, rule) {
+				result.Rules = append(result.Rules, rule)
+			}
+		}
+		result.Warnings = append(result.Warnings, partResult.Warnings...)
+	}
+	result.Text = strings.Join(parts, separator)
+	return result
+}
+
+// Normalizes the text in place. Returns the change and whether it should be
+// reported: the text was modified or has unresolved look-alike letters.
+func (n *Normalizer) Apply(id uint32, s *string, separator string) (Change, bool) {
+	result := n.NormalizeVariants(*s, separator)
+	if !result.Changed() && len(result.Warnings) == 0 {
+		return Change{}, false
+	}
+	change := Change{
+		Id:       id,
+		Rules:    result.Rules,
+		Warnings: result.Warnings,
+		Before:   *s,
+		After:    result.Text,
+	}
+	*s = result.Text
+	return change, true
+}
+
+// Normalizes the texts in place and returns the changes ordered by ID.
+func (n *Normalizer) NormalizeAll(texts map[uint32]string, separator string) []Change {
+	var changes []Change
+	for _, id := range slices.Sorted(maps.Keys(texts)) {
+		s := texts[id]
+		if change, ok := n.Apply(id, &s, separator); ok {
+			changes = append(changes, change)
+			texts[id] = s
+		}
+	}
+	return changes
+}
+
+// Prints the changes as a readable diff. IDs are written after the prefix
+// ("#" for strrefs, "@" for TRA references).
+func PrintChanges(w io.Writer, changes []Change, idPrefix string) {
+	for _, c := range changes {
+		if len(c.Rules) > 0 {
+			fmt.Fprintf(w, "%s%d [%s]\n", idPrefix, c.Id, strings.Join(c.Rules, ", "))
+			fmt.Fprintf(w, "  - %q\n  + %q\n", c.Before, c.After)
+		}
+		if len(c.Warnings) > 0 {
+			fmt.Fprintf(w, "%s%d mixed scripts: %s\n", idPrefix, c.Id, strings.Join(c.Warnings, ", "))
+		}
+	}
+}
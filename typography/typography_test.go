@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: © 2026 SBT Localization https://sbt.localization.com.ua
+// SPDX-FileContributor: Serhii Olendarenko <sergey.olendarenko@gmail.com>
+//
+// SPDX-License-Identifier: GPL-3.0-only
+
+package typography
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	uk := DefaultOptions()
+	uk.Language = "uk_UA"
+
+	en := DefaultOptions()
+	en.Language = "en_US"
+
+	nbsp := uk
+	nbsp.NonBreakingSpaces = true
+
+	noQuotes := uk
+	noQuotes.Quotes = false
+
+	unknown := DefaultOptions()
+
+	tests := []struct {
+		name     string
+		opts     Options
+		input    string
+		want     string
+		rules    []string
+		warnings []string
+	}{
+		{"unchanged", uk, "Привіт, <CHARNAME>.", "Привіт, <CHARNAME>.", nil, nil},
+		{"spaces", uk, "Так  і є. \nДалі", "Так і є.\nДалі", []string{RuleSpaces}, nil},
+		{"ellipsis", uk, "Ну... гаразд.", "Ну… гаразд.", []string{RuleEllipsis}, nil},
+		{"more dots kept", uk, "Ну.... гаразд", "Ну.... гаразд", nil, nil},
+		{"dashes", uk, "Це - він.\n- Так, 10-20 монет.", "Це — він.\n— Так, 10–20 монет.", []string{RuleDashes}, nil},
+		{"quotes uk", uk, `Він сказав: "Це "Брама" Балдура".`, "Він сказав: «Це „Брама“ Балдура».", []string{RuleQuotes}, nil},
+		{"quotes en", en, `He said: "It's a "trap"".`, "He said: “It’s a ‘trap’”.", []string{RuleQuotes, RuleApostrophes}, nil},
+		{"unbalanced quotes kept", uk, `Він сказав: "Це`, `Він сказав: "Це`, nil, nil},
+		{"quotes disabled", noQuotes, `"Так"`, `"Так"`, nil, nil},
+		{"quotes without language", unknown, `«Так» - "так"`, `«Так» — "так"`, []string{RuleDashes}, nil},
+		{"apostrophe", uk, "ім'я та п'ять", "ім’я та п’ять", []string{RuleApostrophes}, nil},
+		{"non-breaking spaces", nbsp, "Він і я - в місті", "Він і я — в місті", []string{RuleDashes, RuleNonBreakingSpaces}, nil},
+		{"tokens kept", uk, `<TOKEN_1-2> - це "<GABBER>"...`, "<TOKEN_1-2> — це «<GABBER>»…", []string{RuleEllipsis, RuleDashes, RuleQuotes}, nil},
+		{"dice kept", uk, "Шкода 1d6-1, 2-3 удари, 2D4+1.", "Шкода 1d6-1, 2–3 удари, 2D4+1.", []string{RuleDashes}, nil},
+		{"homoglyphs", uk, "Дpакон i Baldur", "Дракон i Baldur", []string{RuleHomoglyphs}, nil},
+		{"unresolved homoglyphs", uk, "Дракoнz", "Дракoнz", nil, []string{"Дракoнz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.opts).Normalize(tt.input)
+			if got.Text != tt.want {
+				t.Errorf("Normalize() text = %q, want %q", got.Text, tt.want)
+			}
+			if !slices.Equal(got.Rules, tt.rules) {
+				t.Errorf("Normalize() rules = %q, want %q", got.Rules, tt.rules)
+			}
+			if !slices.Equal(got.Warnings, tt.warnings) {
+				t.Errorf("Normalize() warnings = %q, want %q", got.Warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Language = "uk"
+
+	texts := map[uint32]string{
+		1: "Так.",
+		2: `"Він" // "Вона`,
+		3: "Ну...",
+	}
+	changes := New(opts).NormalizeAll(texts, " // ")
+
+	if len(changes) != 2 || changes[0].Id != 2 || changes[1].Id != 3 {
+		t.Fatalf("NormalizeAll() changes = %+v, want changes of 2 and 3", changes)
+	}
+	if want := `«Він» // "Вона`; texts[2] != want {
+		t.Errorf("texts[2] = %q, want %q", texts[2], want)
+	}
+	if want := "Ну…"; texts[3] != want {
+		t.Errorf("texts[3] = %q, want %q", texts[3], want)
+	}
+}